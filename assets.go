@@ -49,6 +49,9 @@ type Formats struct {
 
 	// root is the directory which is prepended to every resource url internally.
 	root string
+
+	// loaded holds the urls loaded by LoadAsync, which Load doesn't load again.
+	loaded map[string]bool
 }
 
 // SetRoot can be used to change the default directory from `assets` to whatever you want.
@@ -85,6 +88,9 @@ func getExt(path string) string {
 
 // load loads the given resource into memory.
 func (formats *Formats) load(url string) error {
+	if formats.loaded[url] {
+		return nil
+	}
 	ext := getExt(url)
 	if loader, ok := Files.formats[ext]; ok {
 		f, err := openFile(filepath.Join(formats.root, url))
@@ -104,7 +110,8 @@ func (formats *Formats) load(url string) error {
 	return fmt.Errorf("no `FileLoader` associated with this extension: %q in url %q", ext, url)
 }
 
-// Load loads the given resource(s) into memory, stopping at the first error.
+// Load loads the given resource(s) into memory, stopping at the first error. Resources
+// which were loaded by LoadAsync are not loaded again.
 func (formats *Formats) Load(urls ...string) error {
 	for _, url := range urls {
 		err := formats.load(url)
@@ -131,6 +138,7 @@ func (formats *Formats) LoadReaderData(url string, f io.Reader) error {
 
 // Unload releases the given resource from memory.
 func (formats *Formats) Unload(url string) error {
+	delete(formats.loaded, url)
 	ext := getExt(url)
 	if loader, ok := Files.formats[ext]; ok {
 		return loader.Unload(url)
@@ -0,0 +1,301 @@
+package engo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// AsyncFileLoader is a FileLoader which splits Load in two for LoadAsync:
+// Decode runs on a worker goroutine, and Finish on the main goroutine. Other
+// loaders only have their files read on a worker goroutine.
+type AsyncFileLoader interface {
+	FileLoader
+
+	// Decode decodes the given resource. It is called on a worker goroutine, so
+	// it must not use OpenGL, nor modify the state of the loader.
+	Decode(url string, data io.Reader) (interface{}, error)
+
+	// Finish stores the resource returned by Decode, so it can be retrieved
+	// using Resource. It is called on the main goroutine, so this is where
+	// textures get uploaded to the GPU.
+	Finish(url string, decoded interface{}) error
+}
+
+// LoadProgress keeps track of resources being loaded by LoadAsync. All of its
+// methods are safe to call from any goroutine.
+type LoadProgress struct {
+	mutex   sync.RWMutex
+	formats *Formats
+
+	total, done int
+	bytes       int64
+	errors      map[string]error
+	err         error
+
+	results chan decodedFile
+}
+
+// decodedFile is a file which was read, and possibly decoded, by a worker
+// goroutine
+type decodedFile struct {
+	url     string
+	path    string
+	loader  FileLoader
+	data    []byte
+	decoded interface{}
+	err     error
+}
+
+var (
+	asyncLoads      []*LoadProgress
+	asyncLoadsMutex sync.Mutex
+)
+
+// LoadAsync starts loading the given resource(s) in the background, and returns
+// right away. The resources are finished on the main goroutine at the start of
+// every frame. Use the returned LoadProgress to follow the progress.
+func (formats *Formats) LoadAsync(urls ...string) *LoadProgress {
+	progress := &LoadProgress{
+		formats: formats,
+		total:   len(urls),
+		errors:  make(map[string]error),
+		results: make(chan decodedFile, len(urls)),
+	}
+
+	if len(urls) == 0 {
+		return progress
+	}
+
+	// Look up the loaders here, as Register and SetRoot may be called while the
+	// workers run
+	jobs := make(chan decodedFile, len(urls))
+	for _, url := range urls {
+		file := decodedFile{url: url}
+		ext := getExt(url)
+		if loader, ok := formats.formats[ext]; ok {
+			file.loader = loader
+			file.path = filepath.Join(formats.root, url)
+		} else {
+			file.err = fmt.Errorf("no `FileLoader` associated with this extension: %q in url %q", ext, url)
+		}
+		jobs <- file
+	}
+	close(jobs)
+
+	workers := runtime.NumCPU()
+	if workers > len(urls) {
+		workers = len(urls)
+	}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for file := range jobs {
+				progress.results <- decode(file, progress)
+			}
+		}()
+	}
+
+	asyncLoadsMutex.Lock()
+	asyncLoads = append(asyncLoads, progress)
+	asyncLoadsMutex.Unlock()
+
+	return progress
+}
+
+// decode reads the given resource, and decodes it if its loader is an
+// AsyncFileLoader. It's called on a worker goroutine.
+func decode(file decodedFile, progress *LoadProgress) decodedFile {
+	if file.err != nil {
+		return file
+	}
+
+	f, err := openFile(file.path)
+	if err != nil {
+		file.err = fmt.Errorf("unable to open resource: %s", err)
+		return file
+	}
+	defer f.Close()
+
+	file.data, err = io.ReadAll(f)
+	if err != nil {
+		file.err = fmt.Errorf("unable to read resource: %s", err)
+		return file
+	}
+
+	progress.mutex.Lock()
+	progress.bytes += int64(len(file.data))
+	progress.mutex.Unlock()
+
+	if async, ok := file.loader.(AsyncFileLoader); ok {
+		file.decoded, file.err = async.Decode(file.url, bytes.NewReader(file.data))
+	}
+
+	return file
+}
+
+// finish hands a file read by a worker goroutine to its loader. It's called on
+// the main goroutine.
+func (formats *Formats) finish(file decodedFile) error {
+	if file.err != nil {
+		return file.err
+	}
+
+	var err error
+	if async, ok := file.loader.(AsyncFileLoader); ok {
+		err = async.Finish(file.url, file.decoded)
+	} else {
+		// This specific loader needs to be given the root
+		if rl, ok := file.loader.(FileLoaderRooter); ok {
+			rl.SetRoot(formats.GetRoot())
+		}
+		err = file.loader.Load(file.url, bytes.NewReader(file.data))
+	}
+	if err != nil {
+		return err
+	}
+
+	if formats.loaded == nil {
+		formats.loaded = make(map[string]bool)
+	}
+	formats.loaded[file.url] = true
+	return nil
+}
+
+// process finishes all files which have been decoded so far, without waiting
+// for the others.
+func (p *LoadProgress) process() {
+	for {
+		select {
+		case file := <-p.results:
+			p.record(file.url, p.formats.finish(file))
+		default:
+			return
+		}
+	}
+}
+
+// record marks the given resource as done, along with the error that occurred
+// while loading it.
+func (p *LoadProgress) record(url string, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.done++
+	if err != nil {
+		p.errors[url] = err
+		if p.err == nil {
+			p.err = err
+		}
+	}
+}
+
+// Wait blocks until all resources are loaded, and returns the first error
+// encountered, if any. As resources are finished on the calling goroutine, Wait
+// must only be called from the main goroutine.
+func (p *LoadProgress) Wait() error {
+	for !p.Finished() {
+		file := <-p.results
+		p.record(file.url, p.formats.finish(file))
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.err
+}
+
+// Finished reports whether all resources are done loading, whether they
+// succeeded or not.
+func (p *LoadProgress) Finished() bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.done == p.total
+}
+
+// FilesDone returns the number of resources that are done loading, whether they
+// succeeded or not.
+func (p *LoadProgress) FilesDone() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.done
+}
+
+// FilesTotal returns the number of resources being loaded.
+func (p *LoadProgress) FilesTotal() int {
+	return p.total
+}
+
+// BytesRead returns the number of bytes read from the resources so far.
+func (p *LoadProgress) BytesRead() int64 {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.bytes
+}
+
+// Progress returns the fraction of resources that are done loading, going from
+// 0 to 1.
+func (p *LoadProgress) Progress() float32 {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.total == 0 {
+		return 1
+	}
+	return float32(p.done) / float32(p.total)
+}
+
+// Err returns the error that occurred while loading the given resource, or nil
+// if it loaded successfully or is still loading.
+func (p *LoadProgress) Err(url string) error {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.errors[url]
+}
+
+// Errors returns the errors that occurred so far, mapped by the url of the
+// resource.
+func (p *LoadProgress) Errors() map[string]error {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	errs := make(map[string]error, len(p.errors))
+	for url, err := range p.errors {
+		errs[url] = err
+	}
+	return errs
+}
+
+// processAsyncLoads finishes the resources decoded since the last frame, and
+// forgets about the loads which have finished. It's called on the main
+// goroutine at the start of every frame.
+func processAsyncLoads() {
+	asyncLoadsMutex.Lock()
+	loads := make([]*LoadProgress, len(asyncLoads))
+	copy(loads, asyncLoads)
+	asyncLoadsMutex.Unlock()
+
+	if len(loads) == 0 {
+		return
+	}
+
+	for _, progress := range loads {
+		progress.process()
+	}
+
+	asyncLoadsMutex.Lock()
+	running := asyncLoads[:0]
+	for _, progress := range asyncLoads {
+		if !progress.Finished() {
+			running = append(running, progress)
+		}
+	}
+	asyncLoads = running
+	asyncLoadsMutex.Unlock()
+}
@@ -0,0 +1,246 @@
+package engo
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testAsyncLoader struct {
+	mutex    sync.Mutex
+	decoded  map[string]string
+	finished map[string]string
+	loads    map[string]int
+}
+
+func (l *testAsyncLoader) Load(url string, data io.Reader) error {
+	decoded, err := l.Decode(url, data)
+	if err != nil {
+		return err
+	}
+	return l.Finish(url, decoded)
+}
+
+func (l *testAsyncLoader) Decode(url string, data io.Reader) (interface{}, error) {
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return nil, err
+	}
+	if string(b) == "corrupt" {
+		return nil, errors.New("unable to decode")
+	}
+
+	l.mutex.Lock()
+	l.decoded[url] = string(b)
+	l.mutex.Unlock()
+	return strings.ToUpper(string(b)), nil
+}
+
+func (l *testAsyncLoader) Finish(url string, decoded interface{}) error {
+	l.finished[url] = decoded.(string)
+	l.loads[url]++
+	return nil
+}
+
+func (l *testAsyncLoader) Unload(url string) error {
+	delete(l.finished, url)
+	return nil
+}
+
+func (l *testAsyncLoader) Resource(url string) (Resource, error) {
+	return testResource{url: url}, nil
+}
+
+// setupAsyncFiles creates the given files in a temporary directory, and sets it
+// as the root of Files
+func setupAsyncFiles(t *testing.T, files map[string]string) *testAsyncLoader {
+	input, mailbox, formats := Input, Mailbox, Files
+	t.Cleanup(func() { Input, Mailbox, Files = input, mailbox, formats })
+	Files = &Formats{formats: make(map[string]FileLoader)}
+	Run(RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, &assetTestScene{})
+	resetScenes()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0666); err != nil {
+			t.Fatalf("failed to create temp file for testing, file: %v, error: %v", name, err)
+		}
+	}
+	Files.SetRoot(dir)
+
+	loader := &testAsyncLoader{decoded: make(map[string]string), finished: make(map[string]string), loads: make(map[string]int)}
+	Files.Register(".async", loader)
+	Files.Register(".test", &testLoader{})
+	return loader
+}
+
+func TestFilesLoadAsync(t *testing.T) {
+	loader := setupAsyncFiles(t, map[string]string{
+		"a.async":  "first",
+		"b.async":  "second",
+		"c.test":   "plain",
+		"d.async":  "corrupt",
+		"e.nofile": "none",
+	})
+
+	urls := []string{"a.async", "b.async", "c.test", "d.async", "notExist.async", "e.nofile"}
+	progress := Files.LoadAsync(urls...)
+	// The workers must not race with changes to the formats
+	Files.Register(".test", &testLoader{})
+	Files.SetRoot(Files.GetRoot())
+	if progress.FilesTotal() != len(urls) {
+		t.Errorf("FilesTotal was not correct, want: %v, got: %v", len(urls), progress.FilesTotal())
+	}
+
+	if err := progress.Wait(); err == nil {
+		t.Error("Wait did not report an error")
+	}
+
+	if !progress.Finished() || progress.Progress() != 1 || progress.FilesDone() != len(urls) {
+		t.Errorf("Loading did not finish, done %v of %v", progress.FilesDone(), progress.FilesTotal())
+	}
+
+	expectedBytes := int64(len("first") + len("second") + len("plain") + len("corrupt"))
+	if progress.BytesRead() != expectedBytes {
+		t.Errorf("BytesRead was not correct, want: %v, got: %v", expectedBytes, progress.BytesRead())
+	}
+
+	if loader.finished["a.async"] != "FIRST" || loader.finished["b.async"] != "SECOND" {
+		t.Errorf("Resources were not finished, got: %v", loader.finished)
+	}
+
+	for _, url := range []string{"a.async", "b.async", "c.test"} {
+		if err := progress.Err(url); err != nil {
+			t.Errorf("Unexpected error for %v: %v", url, err)
+		}
+	}
+
+	errs := progress.Errors()
+	if len(errs) != 3 {
+		t.Errorf("Expected 3 errors, got: %v", errs)
+	}
+	if err := errs["d.async"]; err == nil || err.Error() != "unable to decode" {
+		t.Errorf("Decoding error was not reported, got: %v", err)
+	}
+	if err := errs["notExist.async"]; err == nil || !strings.HasPrefix(err.Error(), "unable to open resource:") {
+		t.Errorf("Missing file was not reported, got: %v", err)
+	}
+	if err := errs["e.nofile"]; err == nil || !strings.HasPrefix(err.Error(), "no `FileLoader` associated with this extension:") {
+		t.Errorf("Missing FileLoader was not reported, got: %v", err)
+	}
+}
+
+func TestFilesLoadAsyncEmpty(t *testing.T) {
+	progress := Files.LoadAsync()
+	if !progress.Finished() || progress.Progress() != 1 {
+		t.Error("Loading nothing did not finish right away")
+	}
+	if err := progress.Wait(); err != nil {
+		t.Errorf("Loading nothing reported an error: %v", err)
+	}
+}
+
+func TestFilesLoadAsyncProcessedEachFrame(t *testing.T) {
+	loader := setupAsyncFiles(t, map[string]string{"a.async": "first"})
+
+	progress := Files.LoadAsync("a.async")
+	for i := 0; !progress.Finished(); i++ {
+		if i > 10000 {
+			t.Fatal("Loading did not finish")
+		}
+		processAsyncLoads()
+		time.Sleep(time.Millisecond)
+	}
+
+	if loader.finished["a.async"] != "FIRST" {
+		t.Errorf("Resource was not finished, got: %v", loader.finished)
+	}
+
+	asyncLoadsMutex.Lock()
+	defer asyncLoadsMutex.Unlock()
+	if len(asyncLoads) != 0 {
+		t.Error("Finished load was not forgotten")
+	}
+}
+
+type testLoadedScene struct {
+	*testStackScene
+	loader   *testAsyncLoader
+	preloads int
+	loaded   bool
+}
+
+func (s *testLoadedScene) PreloadAsync() []string {
+	return []string{"a.async", "b.async"}
+}
+
+func (s *testLoadedScene) Preload() {
+	s.preloads++
+	Files.Load(s.PreloadAsync()...)
+	s.loaded = s.loader.finished["a.async"] != "" && s.loader.finished["b.async"] != ""
+}
+
+func TestSetSceneWithLoadingScene(t *testing.T) {
+	loader := setupAsyncFiles(t, map[string]string{"a.async": "first", "b.async": "second"})
+
+	loading := &testStackScene{name: "Loading"}
+	target := &testLoadedScene{testStackScene: &testStackScene{name: "Loaded"}, loader: loader}
+
+	progress := SetSceneWithLoadingScene(target, loading, false)
+	if CurrentScene() != loading {
+		t.Fatal("Loading scene was not set")
+	}
+	if SceneLoadProgress() != progress {
+		t.Error("SceneLoadProgress did not return the progress of the load")
+	}
+
+	for i := 0; CurrentScene() != target; i++ {
+		if i > 10000 {
+			t.Fatal("Loaded scene was not set")
+		}
+		updateScenes(0.1, 0.1)
+		time.Sleep(time.Millisecond)
+	}
+
+	if target.preloads != 1 || !target.loaded {
+		t.Error("Loaded scene was not preloaded after its assets were loaded")
+	}
+	if loader.loads["a.async"] != 1 || loader.loads["b.async"] != 1 {
+		t.Errorf("Preload loaded the assets again, got: %v", loader.loads)
+	}
+	if len(target.updates) == 0 {
+		t.Error("Loaded scene was not updated")
+	}
+	if SceneLoadProgress() != nil {
+		t.Error("SceneLoadProgress was not reset")
+	}
+}
+
+func TestSetSceneWithLoadingSceneCancel(t *testing.T) {
+	loader := setupAsyncFiles(t, map[string]string{"a.async": "first", "b.async": "second"})
+
+	loading := &testStackScene{name: "CancelLoading"}
+	target := &testLoadedScene{testStackScene: &testStackScene{name: "CancelLoaded"}, loader: loader}
+	other := &testStackScene{name: "CancelOther"}
+
+	progress := SetSceneWithLoadingScene(target, loading, false)
+	SetScene(other, false)
+
+	progress.Wait()
+	updateScenes(0.1, 0.1)
+
+	if CurrentScene() != other {
+		t.Error("Setting another scene did not cancel the load")
+	}
+	if target.preloads != 0 {
+		t.Error("Cancelled scene was preloaded")
+	}
+}
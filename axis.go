@@ -6,11 +6,17 @@ type Axis struct {
 	Name string
 	// Pairs represents the axis pairs of this acis
 	Pairs []AxisPair
+
+	// context is the InputContext the Axis was registered in, if any
+	context *InputContext
 }
 
 // Value returns the value of an Axis.
 func (a Axis) Value() float32 {
 	for _, pair := range a.Pairs {
+		if Input.consumedPair(pair, a.context) {
+			continue
+		}
 		v := pair.Value()
 		if v != AxisNeutral {
 			return v
@@ -90,12 +96,24 @@ func (am *AxisMouse) Value() float32 {
 	return diff
 }
 
+// AxisGamepad is an axis of a Gamepad, such as the horizontal direction of a stick.
 type AxisGamepad struct {
+	// Filter is applied to the raw value of the axis during each update, to remove drift using dead zones,
+	// and to shape the response. It is nil by default, which leaves the value as is. See Gamepad.SetFilter
+	// to set it for all axes.
+	Filter *AxisFilter
+
 	value float32
+	// last is the value during the previous update
+	last float32
+	// raw is the value as it was reported by the gamepad, before the Filter was applied
+	raw float32
 }
 
 func (ag *AxisGamepad) set(v float32) {
+	ag.last = ag.value
 	ag.value = v
+	ag.raw = v
 }
 
 // Value returns the amount and direction the axis is "tilted" from -1 to 1
@@ -103,3 +121,8 @@ func (ag *AxisGamepad) set(v float32) {
 func (ag *AxisGamepad) Value() float32 {
 	return ag.value
 }
+
+// Raw returns the value of the axis as reported by the gamepad, before its Filter was applied.
+func (ag *AxisGamepad) Raw() float32 {
+	return ag.raw
+}
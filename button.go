@@ -4,16 +4,34 @@ package engo
 type Button struct {
 	Triggers []Key
 	Name     string
+	// Extra are the triggers of the Button other than keys, such as mouse
+	// buttons and gamepad buttons.
+	Extra []ButtonTrigger
+
+	// context is the InputContext the Button was registered in, if any
+	context *InputContext
+}
+
+// sees reports whether the Button gets to see the trigger, which it doesn't if
+// a more specific Chord is held down, or if the trigger has been consumed by an
+// InputContext above the one of the Button.
+func (b Button) sees(trigger ButtonTrigger) bool {
+	return !Input.shadowed(trigger) && !Input.consumed(trigger, b.context)
 }
 
 // JustPressed checks whether an input was pressed in the previous frame.
 func (b Button) JustPressed() bool {
 	for _, trigger := range b.Triggers {
 		v := Input.keys.Get(trigger).JustPressed()
-		if v {
+		if v && b.sees(trigger) {
 			return v
 		}
 	}
+	for _, trigger := range b.Extra {
+		if trigger.JustPressed() && b.sees(trigger) {
+			return true
+		}
+	}
 
 	return false
 }
@@ -22,10 +40,15 @@ func (b Button) JustPressed() bool {
 func (b Button) JustReleased() bool {
 	for _, trigger := range b.Triggers {
 		v := Input.keys.Get(trigger).JustReleased()
-		if v {
+		if v && b.sees(trigger) {
 			return v
 		}
 	}
+	for _, trigger := range b.Extra {
+		if trigger.JustReleased() && b.sees(trigger) {
+			return true
+		}
+	}
 
 	return false
 }
@@ -34,10 +57,15 @@ func (b Button) JustReleased() bool {
 func (b Button) Down() bool {
 	for _, trigger := range b.Triggers {
 		v := Input.keys.Get(trigger).Down()
-		if v {
+		if v && b.sees(trigger) {
 			return v
 		}
 	}
+	for _, trigger := range b.Extra {
+		if trigger.Down() && b.sees(trigger) {
+			return true
+		}
+	}
 
 	return false
 }
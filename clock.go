@@ -1,24 +1,49 @@
 package engo
 
 import (
+	"sync"
 	"time"
 )
 
-// timer is a wrapper for time.Now().UnixNano() so we can test
+// TimeSource is what a Clock uses to tell the time. It is a wrapper for time.Now().UnixNano() so we can test
 // this wihout relying on time
-type timer interface {
+type TimeSource interface {
+	// Now returns the current time in nanoseconds
 	Now() int64
 }
 
 // realTime is the actual timer that uses time.Now().UnixNano()
 type realTime struct{}
 
-// Now implements the timer interface
+// Now implements the TimeSource interface
 func (realTime) Now() int64 {
 	return time.Now().UnixNano()
 }
 
-var theTimer timer = realTime{}
+var theTimer TimeSource = realTime{}
+
+// ManualTime is a TimeSource which only moves forward when told to, so that a Clock using it is entirely
+// deterministic. The zero value starts at time zero.
+type ManualTime struct {
+	mutex sync.RWMutex
+	now   int64
+}
+
+// Now implements the TimeSource interface
+func (m *ManualTime) Now() int64 {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.now
+}
+
+// Advance moves the time forward by the given duration
+func (m *ManualTime) Advance(d time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.now += int64(d)
+}
 
 // The amound of nano seconds in a second.
 const secondsInNano int64 = 1000000000
@@ -33,25 +58,45 @@ type Clock struct {
 	frameStamp int64
 	startStamp int64
 
-	paused bool
+	frames    uint64
+	paused    bool
+	timeScale float32
+
+	// source tells the time, or theTimer is used if it's nil
+	source TimeSource
 }
 
 // NewClock creates a new timer which allows you to measure ticks per seconds. Be sure to call `Tick()` whenever you
 // want a tick to occur - it does not automatically tick each frame.
 func NewClock() *Clock {
-	currStamp := theTimer.Now()
+	return NewClockWithSource(nil)
+}
+
+// NewClockWithSource creates a new timer just like NewClock does, which uses the given TimeSource to tell the time.
+// Use a ManualTime to control exactly how much time passes between ticks.
+func NewClockWithSource(source TimeSource) *Clock {
+	clock := &Clock{source: source, timeScale: 1}
 
-	clock := new(Clock)
+	currStamp := clock.now()
 	clock.frameStamp = currStamp
 	clock.startStamp = currStamp
 	return clock
 }
 
+// now returns the current time of the TimeSource of the clock
+func (c *Clock) now() int64 {
+	if c.source != nil {
+		return c.source.Now()
+	}
+	return theTimer.Now()
+}
+
 // Tick indicates a new tick/frame has occurred.
 func (c *Clock) Tick() {
-	currStamp := theTimer.Now()
+	currStamp := c.now()
 
 	c.counter++
+	c.frames++
 
 	c.deltaStamp = currStamp - c.frameStamp
 	c.frameStamp = currStamp
@@ -64,14 +109,35 @@ func (c *Clock) Tick() {
 	}
 }
 
-// Delta is the amount of seconds between the last tick and the one before that
+// Delta is the amount of seconds between the last tick and the one before that, multiplied by the time scale. It
+// is zero while the clock is paused.
 func (c *Clock) Delta() float32 {
 	if c.paused {
 		return 0
 	}
+	return c.UnscaledDelta() * c.timeScale
+}
+
+// UnscaledDelta is the actual amount of seconds between the last tick and the one before that, regardless of the
+// time scale and whether the clock is paused.
+func (c *Clock) UnscaledDelta() float32 {
 	return float32(float64(c.deltaStamp) / float64(secondsInNano))
 }
 
+// SetTimeScale sets the factor Delta is multiplied by, making the game run in slow motion when it's less than 1,
+// or fast-forward when it's greater than 1. Any scale less than zero results in the time scale being set to zero.
+func (c *Clock) SetTimeScale(scale float32) {
+	if scale < 0 {
+		scale = 0
+	}
+	c.timeScale = scale
+}
+
+// TimeScale returns the factor Delta is multiplied by, which defaults to 1
+func (c *Clock) TimeScale() float32 {
+	return c.timeScale
+}
+
 // Pause pauses the clock
 func (c *Clock) Pause() {
 	c.paused = true
@@ -87,8 +153,13 @@ func (c *Clock) FPS() float32 {
 	return float32(c.perSecond)
 }
 
+// Frames is the number of ticks that occurred since the clock was created
+func (c *Clock) Frames() uint64 {
+	return c.frames
+}
+
 // Time is the number of seconds the clock has been running
 func (c *Clock) Time() float32 {
-	currStamp := theTimer.Now()
+	currStamp := c.now()
 	return float32(float64(currStamp-c.startStamp) / float64(secondsInNano))
 }
@@ -129,3 +129,74 @@ func TestClockPause(t *testing.T) {
 		t.Error("Clock did not increase delta after unpausing")
 	}
 }
+
+func TestManualTime(t *testing.T) {
+	source := &ManualTime{}
+	clock := NewClockWithSource(source)
+
+	theTimer = testTime{5000000000}
+	defer func() { theTimer = realTime{} }()
+
+	source.Advance(250 * time.Millisecond)
+	clock.Tick()
+	if clock.Delta() != 0.25 {
+		t.Errorf("Clock's Delta did not match the time the ManualTime advanced, was %v", clock.Delta())
+	}
+
+	source.Advance(time.Second)
+	clock.Tick()
+	if clock.Delta() != 1 || clock.Time() != 1.25 {
+		t.Errorf("Clock did not follow the ManualTime, delta was %v and time was %v", clock.Delta(), clock.Time())
+	}
+	if clock.Frames() != 2 {
+		t.Errorf("Clock did not count the frames, was %v", clock.Frames())
+	}
+
+	clock.Tick()
+	if clock.Delta() != 0 || clock.Frames() != 3 {
+		t.Errorf("Clock advanced without the ManualTime advancing, delta was %v", clock.Delta())
+	}
+}
+
+func TestClockTimeScale(t *testing.T) {
+	source := &ManualTime{}
+	clock := NewClockWithSource(source)
+	if clock.TimeScale() != 1 {
+		t.Errorf("Clock's time scale did not default to 1, was %v", clock.TimeScale())
+	}
+
+	data := []struct {
+		scale, expScale, expDelta float32
+		paused                    bool
+	}{
+		{0.5, 0.5, 0.25, false},
+		{2, 2, 1, false},
+		{0, 0, 0, false},
+		{-1, 0, 0, false},
+		{2, 2, 0, true},
+	}
+	for _, d := range data {
+		clock.SetTimeScale(d.scale)
+		if d.paused {
+			clock.Pause()
+		}
+		source.Advance(500 * time.Millisecond)
+		clock.Tick()
+		clock.Unpause()
+
+		if clock.TimeScale() != d.expScale {
+			t.Errorf("Clock's time scale did not match %v, was %v", d.expScale, clock.TimeScale())
+		}
+		if clock.Delta() != d.expDelta && !d.paused {
+			t.Errorf("Clock's Delta did not match %v with a time scale of %v, was %v", d.expDelta, d.scale, clock.Delta())
+		}
+		if clock.UnscaledDelta() != 0.5 {
+			t.Errorf("Clock's UnscaledDelta was affected by the time scale, was %v", clock.UnscaledDelta())
+		}
+	}
+
+	clock.Pause()
+	if clock.Delta() != 0 || clock.UnscaledDelta() != 0.5 {
+		t.Errorf("Pausing should only affect Delta, got %v and %v", clock.Delta(), clock.UnscaledDelta())
+	}
+}
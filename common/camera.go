@@ -85,12 +85,7 @@ func (cam *CameraSystem) New(w *ecs.World) {
 
 	cam.longTasks = make(map[CameraAxis]*CameraMessage)
 
-	engo.Mailbox.Listen("CameraMessage", func(msg engo.Message) {
-		cammsg, ok := msg.(CameraMessage)
-		if !ok {
-			return
-		}
-
+	engo.ListenFor(engo.Mailbox, func(cammsg CameraMessage) {
 		// Stop with whatever we're doing now
 		if _, ok := cam.longTasks[cammsg.Axis]; ok {
 			delete(cam.longTasks, cammsg.Axis)
@@ -524,10 +524,22 @@ type CollisionSystem struct {
 	// Solids, used to tell which collisions should be treated as solid by bitwise comparison.
 	// if a.Main & b.Group & sys.Solids{ Collisions are treated as solid.  }
 	Solids CollisionGroup
+	// Deferred makes the system Post its CollisionMessages instead of dispatching them right away. Their handlers
+	// then run once the World has been updated, so they can safely remove entities.
+	Deferred bool
 
 	entities []collisionEntity
 }
 
+// send sends the CollisionMessage, either right away or deferred
+func (c *CollisionSystem) send(msg CollisionMessage) {
+	if c.Deferred {
+		engo.Mailbox.Post(msg)
+	} else {
+		engo.Mailbox.Dispatch(msg)
+	}
+}
+
 // Add adds an entity to the CollisionSystem. To be added, the entity has to have a basic, collision, and space component.
 func (c *CollisionSystem) Add(basic *ecs.BasicEntity, collision *CollisionComponent, space *SpaceComponent) {
 	c.entities = append(c.entities, collisionEntity{basic, collision, space})
@@ -555,7 +567,23 @@ func (c *CollisionSystem) Remove(basic ecs.BasicEntity) {
 
 // Update checks the entities for collision with eachother. Only Main entities are check for collision explicitly.
 // If one of the entities are solid, the SpaceComponent is adjusted so that the other entities don't pass through it.
+// Whenever a fixed tick rate is set, the collisions are checked in FixedUpdate instead.
 func (c *CollisionSystem) Update(dt float32) {
+	if engo.FixedTickRate() > 0 {
+		return
+	}
+
+	c.checkCollisions()
+}
+
+// FixedUpdate checks the entities for collision with eachother at the fixed tick rate, so that the outcome does
+// not depend on the frame rate. It implements the engo.FixedUpdater interface.
+func (c *CollisionSystem) FixedUpdate(dt float32) {
+	c.checkCollisions()
+}
+
+// checkCollisions checks the entities for collision with eachother, and moves them apart if they are solid.
+func (c *CollisionSystem) checkCollisions() {
 	for i1, e1 := range c.entities {
 		if e1.CollisionComponent.Main == 0 {
 			//Main cannot pass bitwise comparison with any other items. Do not loop.
@@ -585,7 +613,7 @@ func (c *CollisionSystem) Update(dt float32) {
 						e2.SpaceComponent.Position.Y -= mtd.Y / 2
 						//As the entities are no longer overlapping
 						//e2 wont collide as main
-						engo.Mailbox.Dispatch(CollisionMessage{Entity: e2, To: e1, Groups: cgroup})
+						c.send(CollisionMessage{Entity: e2, To: e1, Groups: cgroup})
 					} else {
 						//collision with one main
 						e1.SpaceComponent.Position.X += mtd.X
@@ -595,7 +623,7 @@ func (c *CollisionSystem) Update(dt float32) {
 
 				//collided can now list the types of collision
 				collided = collided | cgroup
-				engo.Mailbox.Dispatch(CollisionMessage{Entity: e1, To: e2, Groups: cgroup})
+				c.send(CollisionMessage{Entity: e1, To: e2, Groups: cgroup})
 
 				//update the position tracker of e1
 				entityAABB := e1.SpaceComponent.AABB()
@@ -614,3 +614,67 @@ func TestSpaceComponentAddShape(t *testing.T) {
 		}
 	}
 }
+
+func TestCollisionSystemFixedStep(t *testing.T) {
+	engo.Run(engo.RunOptions{
+		NoRun:         true,
+		HeadlessMode:  true,
+		FixedTickRate: 60,
+	}, &fixedStepTestScene{})
+	defer engo.SetFixedTickRate(0)
+
+	ball := ecs.NewBasic()
+	wall := ecs.NewBasic()
+	ents := []collisionEntity{
+		{&ball, &CollisionComponent{Main: Ball}, &SpaceComponent{Position: engo.Point{X: 10, Y: 10}, Width: 50, Height: 50}},
+		{&wall, &CollisionComponent{Group: Ball}, &SpaceComponent{Position: engo.Point{X: 10, Y: 10}, Width: 50, Height: 50}},
+	}
+	sys := CollisionSystem{
+		entities: ents,
+		Solids:   Ball,
+	}
+
+	sys.Update(0.01)
+	if ents[0].Collides != 0 || ents[0].Position != ents[1].Position {
+		t.Error("Collisions should not be checked in Update when a fixed tick rate is set")
+	}
+
+	sys.FixedUpdate(engo.FixedDelta())
+	if ents[0].Collides == 0 || ents[0].Position == ents[1].Position {
+		t.Error("Collisions should be checked in FixedUpdate")
+	}
+}
+
+func TestCollisionSystemDeferred(t *testing.T) {
+	engo.Run(engo.RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, &fixedStepTestScene{})
+
+	ball := ecs.NewBasic()
+	wall := ecs.NewBasic()
+	ents := []collisionEntity{
+		{&ball, &CollisionComponent{Main: Ball}, &SpaceComponent{Position: engo.Point{X: 10, Y: 10}, Width: 50, Height: 50}},
+		{&wall, &CollisionComponent{Group: Ball}, &SpaceComponent{Position: engo.Point{X: 10, Y: 10}, Width: 50, Height: 50}},
+	}
+	sys := CollisionSystem{
+		entities: ents,
+		Deferred: true,
+	}
+
+	received := 0
+	engo.ListenFor(engo.Mailbox, func(msg CollisionMessage) {
+		received++
+		sys.Remove(*msg.Entity.BasicEntity)
+	})
+
+	sys.Update(0.01)
+	if received != 0 {
+		t.Error("Deferred collisions should not be dispatched during Update")
+	}
+
+	engo.Mailbox.Flush()
+	if received != 1 || len(sys.entities) != 1 {
+		t.Errorf("Deferred collisions should be dispatched when flushed, got %d messages", received)
+	}
+}
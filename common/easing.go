@@ -0,0 +1,173 @@
+package common
+
+import (
+	"github.com/EngoEngine/engo/math"
+)
+
+// EaseFunc maps the progress of a Tween, going from 0 to 1, to the progress of
+// the value being animated. Most easing functions start at 0 and end at 1, but
+// may go beyond those in between, such as BackOut and ElasticOut.
+type EaseFunc func(t float32) float32
+
+// easeOut turns an ease-in function into the matching ease-out function
+func easeOut(in EaseFunc) EaseFunc {
+	return func(t float32) float32 {
+		return 1 - in(1-t)
+	}
+}
+
+// easeInOut turns an ease-in function into the matching ease-in-out function
+func easeInOut(in EaseFunc) EaseFunc {
+	return func(t float32) float32 {
+		if t < 0.5 {
+			return in(t*2) / 2
+		}
+		return 1 - in((1-t)*2)/2
+	}
+}
+
+const (
+	backOvershoot   = 1.70158
+	elasticPeriod   = 0.3
+	bounceThreshold = 1 / 2.75
+)
+
+// Linear moves at a constant speed.
+func Linear(t float32) float32 {
+	return t
+}
+
+// QuadIn accelerates from zero velocity, following t^2.
+func QuadIn(t float32) float32 {
+	return t * t
+}
+
+// CubicIn accelerates from zero velocity, following t^3.
+func CubicIn(t float32) float32 {
+	return t * t * t
+}
+
+// QuartIn accelerates from zero velocity, following t^4.
+func QuartIn(t float32) float32 {
+	return t * t * t * t
+}
+
+// QuintIn accelerates from zero velocity, following t^5.
+func QuintIn(t float32) float32 {
+	return t * t * t * t * t
+}
+
+// SineIn accelerates from zero velocity, following a quarter of a sine wave.
+func SineIn(t float32) float32 {
+	return 1 - math.Cos(t*math.Pi/2)
+}
+
+// ExpoIn accelerates from zero velocity, following 2^(10(t-1)).
+func ExpoIn(t float32) float32 {
+	if t <= 0 {
+		return 0
+	}
+	return math.Pow(2, 10*(t-1))
+}
+
+// CircIn accelerates from zero velocity, following a quarter of a circle.
+func CircIn(t float32) float32 {
+	return 1 - math.Sqrt(1-t*t)
+}
+
+// BackIn pulls back a little before moving towards the end.
+func BackIn(t float32) float32 {
+	return t * t * ((backOvershoot+1)*t - backOvershoot)
+}
+
+// ElasticIn wobbles around the start, with increasing amplitude, before
+// snapping to the end.
+func ElasticIn(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	t--
+	return -math.Pow(2, 10*t) * math.Sin((t-elasticPeriod/4)*(2*math.Pi)/elasticPeriod)
+}
+
+// BounceIn bounces off the start a few times, with increasing height, before
+// reaching the end.
+func BounceIn(t float32) float32 {
+	return 1 - BounceOut(1-t)
+}
+
+// BounceOut reaches the end quickly, and then bounces off it a few times with
+// decreasing height.
+func BounceOut(t float32) float32 {
+	switch {
+	case t < bounceThreshold:
+		return 7.5625 * t * t
+	case t < 2*bounceThreshold:
+		t -= 1.5 * bounceThreshold
+		return 7.5625*t*t + 0.75
+	case t < 2.5*bounceThreshold:
+		t -= 2.25 * bounceThreshold
+		return 7.5625*t*t + 0.9375
+	default:
+		t -= 2.625 * bounceThreshold
+		return 7.5625*t*t + 0.984375
+	}
+}
+
+var (
+	// QuadOut decelerates to zero velocity, following t^2.
+	QuadOut = easeOut(QuadIn)
+	// QuadInOut accelerates until halfway, and then decelerates, following t^2.
+	QuadInOut = easeInOut(QuadIn)
+
+	// CubicOut decelerates to zero velocity, following t^3.
+	CubicOut = easeOut(CubicIn)
+	// CubicInOut accelerates until halfway, and then decelerates, following
+	// t^3.
+	CubicInOut = easeInOut(CubicIn)
+
+	// QuartOut decelerates to zero velocity, following t^4.
+	QuartOut = easeOut(QuartIn)
+	// QuartInOut accelerates until halfway, and then decelerates, following
+	// t^4.
+	QuartInOut = easeInOut(QuartIn)
+
+	// QuintOut decelerates to zero velocity, following t^5.
+	QuintOut = easeOut(QuintIn)
+	// QuintInOut accelerates until halfway, and then decelerates, following
+	// t^5.
+	QuintInOut = easeInOut(QuintIn)
+
+	// SineOut decelerates to zero velocity, following a quarter of a sine wave.
+	SineOut = easeOut(SineIn)
+	// SineInOut accelerates until halfway, and then decelerates, following half
+	// a sine wave.
+	SineInOut = easeInOut(SineIn)
+
+	// ExpoOut decelerates to zero velocity exponentially.
+	ExpoOut = easeOut(ExpoIn)
+	// ExpoInOut accelerates exponentially until halfway, and then decelerates
+	// exponentially.
+	ExpoInOut = easeInOut(ExpoIn)
+
+	// CircOut decelerates to zero velocity, following a quarter of a circle.
+	CircOut = easeOut(CircIn)
+	// CircInOut accelerates until halfway, and then decelerates, following a
+	// quarter of a circle each.
+	CircInOut = easeInOut(CircIn)
+
+	// BackOut overshoots the end a little, before settling on it.
+	BackOut = easeOut(BackIn)
+	// BackInOut pulls back a little at the start, and overshoots the end a
+	// little.
+	BackInOut = easeInOut(BackIn)
+
+	// ElasticOut snaps past the end, and then wobbles around it with decreasing
+	// amplitude.
+	ElasticOut = easeOut(ElasticIn)
+	// ElasticInOut wobbles around the start, and then around the end.
+	ElasticInOut = easeInOut(ElasticIn)
+
+	// BounceInOut bounces off the start, and then off the end.
+	BounceInOut = easeInOut(BounceIn)
+)
@@ -0,0 +1,67 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEasingEndpoints(t *testing.T) {
+	easings := map[string]EaseFunc{
+		"Linear":       Linear,
+		"QuadIn":       QuadIn,
+		"QuadOut":      QuadOut,
+		"QuadInOut":    QuadInOut,
+		"CubicIn":      CubicIn,
+		"CubicOut":     CubicOut,
+		"CubicInOut":   CubicInOut,
+		"QuartIn":      QuartIn,
+		"QuartOut":     QuartOut,
+		"QuartInOut":   QuartInOut,
+		"QuintIn":      QuintIn,
+		"QuintOut":     QuintOut,
+		"QuintInOut":   QuintInOut,
+		"SineIn":       SineIn,
+		"SineOut":      SineOut,
+		"SineInOut":    SineInOut,
+		"ExpoIn":       ExpoIn,
+		"ExpoOut":      ExpoOut,
+		"ExpoInOut":    ExpoInOut,
+		"CircIn":       CircIn,
+		"CircOut":      CircOut,
+		"CircInOut":    CircInOut,
+		"BackIn":       BackIn,
+		"BackOut":      BackOut,
+		"BackInOut":    BackInOut,
+		"ElasticIn":    ElasticIn,
+		"ElasticOut":   ElasticOut,
+		"ElasticInOut": ElasticInOut,
+		"BounceIn":     BounceIn,
+		"BounceOut":    BounceOut,
+		"BounceInOut":  BounceInOut,
+	}
+
+	for name, ease := range easings {
+		assert.InDelta(t, 0, ease(0), 0.001, name+" should start at 0")
+		assert.InDelta(t, 1, ease(1), 0.001, name+" should end at 1")
+	}
+}
+
+func TestEasingValues(t *testing.T) {
+	assert.InDelta(t, 0.25, QuadIn(0.5), 0.0001)
+	assert.InDelta(t, 0.75, QuadOut(0.5), 0.0001)
+	assert.InDelta(t, 0.5, QuadInOut(0.5), 0.0001)
+	assert.InDelta(t, 0.125, CubicIn(0.5), 0.0001)
+	assert.InDelta(t, 0.5, SineInOut(0.5), 0.0001)
+
+	// Back pulls back below 0, and overshoots above 1
+	assert.True(t, BackIn(0.2) < 0)
+	assert.True(t, BackOut(0.8) > 1)
+
+	// Bounce never leaves the range, and touches the end at the threshold
+	for i := 0; i <= 100; i++ {
+		v := BounceOut(float32(i) / 100)
+		assert.True(t, v >= 0 && v <= 1.0001, "BounceOut should stay within [0, 1]")
+	}
+	assert.InDelta(t, 1, BounceOut(bounceThreshold), 0.0001)
+}
@@ -72,6 +72,10 @@ func (*FPSSystem) Add() {}
 // Remove doesn't do anything since New creates the only entity used
 func (*FPSSystem) Remove(b ecs.BasicEntity) {}
 
+// Unscaled makes the FPSSystem measure the actual time between frames,
+// regardless of the time scale. It implements the engo.Unscaler interface.
+func (*FPSSystem) Unscaled() bool { return true }
+
 // Update changes the dipslayed text and prints to the terminal every second
 // to report the FPS
 func (f *FPSSystem) Update(dt float32) {
@@ -0,0 +1,385 @@
+package common
+
+import (
+	"sort"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/math"
+)
+
+// GestureSystemPriority is the priority of the GestureSystem. It runs before
+// the camera systems, so they can act upon the gestures in the same frame.
+const GestureSystemPriority = 150
+
+const (
+	// DefaultTapMaxDuration is the longest a touch can last to count as a tap,
+	// in seconds.
+	DefaultTapMaxDuration float32 = 0.25
+	// DefaultTapMaxDistance is the farthest a touch can move to count as a tap,
+	// or as a long press.
+	DefaultTapMaxDistance float32 = 10
+	// DefaultDoubleTapInterval is the longest time between two taps of a double
+	// tap, in seconds.
+	DefaultDoubleTapInterval float32 = 0.3
+	// DefaultLongPressDuration is how long a touch has to stay in place to
+	// count as a long press, in seconds.
+	DefaultLongPressDuration float32 = 0.5
+	// DefaultSwipeMinDistance is the shortest distance a touch has to move to
+	// count as a swipe.
+	DefaultSwipeMinDistance float32 = 50
+	// DefaultSwipeMinVelocity is the lowest average speed of a swipe, in units
+	// per second.
+	DefaultSwipeMinVelocity float32 = 300
+)
+
+// TapMessage is dispatched when a touch is released quickly, without moving.
+type TapMessage struct {
+	Position engo.Point
+}
+
+// Type implements the engo.Message interface.
+func (TapMessage) Type() string {
+	return "TapMessage"
+}
+
+// DoubleTapMessage is dispatched when a tap quickly follows another tap at
+// about the same position. The TapMessage of the second tap is not dispatched.
+type DoubleTapMessage struct {
+	Position engo.Point
+}
+
+// Type implements the engo.Message interface.
+func (DoubleTapMessage) Type() string {
+	return "DoubleTapMessage"
+}
+
+// LongPressMessage is dispatched once a touch has been held in place for long
+// enough. Releasing it afterwards doesn't cause a TapMessage.
+type LongPressMessage struct {
+	Position engo.Point
+}
+
+// Type implements the engo.Message interface.
+func (LongPressMessage) Type() string {
+	return "LongPressMessage"
+}
+
+// SwipeMessage is dispatched when a touch is released after moving quickly over
+// a long enough distance.
+type SwipeMessage struct {
+	// Start and End are the positions where the touch began and was released
+	Start, End engo.Point
+	// Velocity is the average velocity of the swipe, in units per second
+	Velocity engo.Point
+	// Duration of the swipe, in seconds
+	Duration float32
+}
+
+// Type implements the engo.Message interface.
+func (SwipeMessage) Type() string {
+	return "SwipeMessage"
+}
+
+// DragMessage is dispatched during every frame in which a single touch moves,
+// once it has moved farther than a tap could.
+type DragMessage struct {
+	// Position is the current position of the touch
+	Position engo.Point
+	// Delta is the movement since the previous frame
+	Delta engo.Point
+	// Velocity is the movement since the previous frame, in units per second
+	Velocity engo.Point
+}
+
+// Type implements the engo.Message interface.
+func (DragMessage) Type() string {
+	return "DragMessage"
+}
+
+// PinchMessage is dispatched during every frame in which the distance between
+// two touches changes.
+type PinchMessage struct {
+	// Center is the point in between both touches
+	Center engo.Point
+	// Scale is the distance between the touches divided by their distance
+	// during the previous frame, which is greater than 1 while spreading them
+	Scale float32
+	// TotalScale is the distance between the touches divided by their distance
+	// when the pinch started
+	TotalScale float32
+}
+
+// Type implements the engo.Message interface.
+func (PinchMessage) Type() string {
+	return "PinchMessage"
+}
+
+// RotateMessage is dispatched during every frame in which the angle between two
+// touches changes.
+type RotateMessage struct {
+	// Center is the point in between both touches
+	Center engo.Point
+	// Rotation is the change in angle since the previous frame, in degrees,
+	// clockwise on screen
+	Rotation float32
+	// TotalRotation is the change in angle since the rotation started, in
+	// degrees
+	TotalRotation float32
+}
+
+// Type implements the engo.Message interface.
+func (RotateMessage) Type() string {
+	return "RotateMessage"
+}
+
+// gestureTouch is a touch which is being tracked by the GestureSystem
+type gestureTouch struct {
+	start, position engo.Point
+	began           float32
+	moved           bool
+	longPressed     bool
+}
+
+// GestureSystem recognizes gestures in the Touches of engo.Input, and
+// dispatches a message for each of them, such as a TapMessage or a
+// PinchMessage. Optionally, it pans, zooms and rotates the camera using the
+// gestures. The zero value uses the default thresholds.
+type GestureSystem struct {
+	// TapMaxDuration, TapMaxDistance, DoubleTapInterval, LongPressDuration,
+	// SwipeMinDistance and SwipeMinVelocity tune the recognition of gestures.
+	// Zero values are replaced by their defaults.
+	TapMaxDuration    float32
+	TapMaxDistance    float32
+	DoubleTapInterval float32
+	LongPressDuration float32
+	SwipeMinDistance  float32
+	SwipeMinVelocity  float32
+
+	// CameraPan moves the camera along while dragging a single touch
+	CameraPan bool
+	// CameraZoom zooms the camera in and out while pinching
+	CameraZoom bool
+	// CameraRotate rotates the camera along while rotating two touches
+	CameraRotate bool
+
+	camera  *CameraSystem
+	now     float32
+	touches map[int]*gestureTouch
+	// multi is set once more than one touch is down, which stops single touch
+	// gestures until all touches are released
+	multi bool
+
+	// lastTap is the position and time of the last tap which could start a
+	// double tap
+	lastTap     engo.Point
+	lastTapTime float32
+	tapped      bool
+
+	// pinching is set while two touches are down, starting at the distance and
+	// angle between them
+	pinching                  bool
+	pinchDistance, pinchAngle float32
+	startDistance, startAngle float32
+}
+
+// New finds the CameraSystem of the World, which is used to pan, zoom and
+// rotate the camera.
+func (g *GestureSystem) New(w *ecs.World) {
+	for _, sys := range w.Systems() {
+		if cam, ok := sys.(*CameraSystem); ok {
+			g.camera = cam
+		}
+	}
+	g.setDefaults()
+}
+
+func (g *GestureSystem) setDefaults() {
+	if g.touches == nil {
+		g.touches = make(map[int]*gestureTouch)
+	}
+	if g.TapMaxDuration == 0 {
+		g.TapMaxDuration = DefaultTapMaxDuration
+	}
+	if g.TapMaxDistance == 0 {
+		g.TapMaxDistance = DefaultTapMaxDistance
+	}
+	if g.DoubleTapInterval == 0 {
+		g.DoubleTapInterval = DefaultDoubleTapInterval
+	}
+	if g.LongPressDuration == 0 {
+		g.LongPressDuration = DefaultLongPressDuration
+	}
+	if g.SwipeMinDistance == 0 {
+		g.SwipeMinDistance = DefaultSwipeMinDistance
+	}
+	if g.SwipeMinVelocity == 0 {
+		g.SwipeMinVelocity = DefaultSwipeMinVelocity
+	}
+}
+
+// Priority implements the ecs.Prioritizer interface.
+func (*GestureSystem) Priority() int { return GestureSystemPriority }
+
+// Remove does nothing because the GestureSystem has no entities. It implements
+// the ecs.System interface.
+func (*GestureSystem) Remove(ecs.BasicEntity) {}
+
+// Update compares the Touches with the ones of the previous frame, and
+// dispatches the gestures they make.
+func (g *GestureSystem) Update(dt float32) {
+	g.setDefaults()
+	g.now += dt
+
+	current := engo.Input.Touches
+	for id, touch := range g.touches {
+		if _, ok := current[id]; !ok {
+			g.release(touch)
+			delete(g.touches, id)
+		}
+	}
+
+	ids := make([]int, 0, len(current))
+	for id := range current {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		position := current[id]
+		touch, ok := g.touches[id]
+		if !ok {
+			g.touches[id] = &gestureTouch{start: position, position: position, began: g.now}
+			continue
+		}
+
+		delta := position
+		delta.Subtract(touch.position)
+		touch.position = position
+		if !touch.moved && touch.start.PointDistance(position) > g.TapMaxDistance {
+			touch.moved = true
+		}
+
+		if len(ids) == 1 && !g.multi && touch.moved && (delta.X != 0 || delta.Y != 0) {
+			g.drag(position, delta, dt)
+		}
+	}
+
+	if len(ids) > 1 {
+		g.multi = true
+		g.twoFingers(g.touches[ids[0]].position, g.touches[ids[1]].position)
+	} else {
+		g.pinching = false
+		if len(ids) == 0 {
+			g.multi = false
+		}
+	}
+
+	if len(ids) == 1 && !g.multi {
+		touch := g.touches[ids[0]]
+		if !touch.moved && !touch.longPressed && g.now-touch.began >= g.LongPressDuration {
+			touch.longPressed = true
+			engo.Mailbox.Dispatch(LongPressMessage{Position: touch.position})
+		}
+	}
+}
+
+// release dispatches the tap, double tap or swipe made by a touch which was
+// just released
+func (g *GestureSystem) release(touch *gestureTouch) {
+	if g.multi || touch.longPressed {
+		return
+	}
+	duration := g.now - touch.began
+
+	if !touch.moved {
+		if duration > g.TapMaxDuration {
+			return
+		}
+		if g.tapped && g.now-g.lastTapTime <= g.DoubleTapInterval && g.lastTap.PointDistance(touch.position) <= 2*g.TapMaxDistance {
+			g.tapped = false
+			engo.Mailbox.Dispatch(DoubleTapMessage{Position: touch.position})
+			return
+		}
+		g.tapped = true
+		g.lastTap = touch.position
+		g.lastTapTime = g.now
+		engo.Mailbox.Dispatch(TapMessage{Position: touch.position})
+		return
+	}
+
+	distance := touch.start.PointDistance(touch.position)
+	if duration <= 0 || distance < g.SwipeMinDistance || distance/duration < g.SwipeMinVelocity {
+		return
+	}
+	velocity := touch.position
+	velocity.Subtract(touch.start)
+	velocity.MultiplyScalar(1 / duration)
+	engo.Mailbox.Dispatch(SwipeMessage{
+		Start:    touch.start,
+		End:      touch.position,
+		Velocity: velocity,
+		Duration: duration,
+	})
+}
+
+// drag dispatches the DragMessage of a single moving touch, and pans the camera
+func (g *GestureSystem) drag(position, delta engo.Point, dt float32) {
+	velocity := delta
+	if dt > 0 {
+		velocity.MultiplyScalar(1 / dt)
+	}
+	engo.Mailbox.Dispatch(DragMessage{Position: position, Delta: delta, Velocity: velocity})
+
+	if g.CameraPan && g.camera != nil {
+		// The world follows the finger, so the camera moves the other way
+		z := g.camera.Z()
+		engo.Mailbox.Dispatch(CameraMessage{Axis: XAxis, Value: -delta.X * z, Incremental: true})
+		engo.Mailbox.Dispatch(CameraMessage{Axis: YAxis, Value: -delta.Y * z, Incremental: true})
+	}
+}
+
+// twoFingers dispatches the pinch and rotation made by the first two touches,
+// and zooms and rotates the camera
+func (g *GestureSystem) twoFingers(a, b engo.Point) {
+	center := engo.Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+	distance := a.PointDistance(b)
+	angle := math.Atan2(b.Y-a.Y, b.X-a.X) * engo.RadToDeg
+
+	if !g.pinching {
+		g.pinching = true
+		g.pinchDistance, g.startDistance = distance, distance
+		g.pinchAngle, g.startAngle = angle, angle
+		return
+	}
+
+	if distance != g.pinchDistance && g.pinchDistance > 0 && g.startDistance > 0 {
+		scale := distance / g.pinchDistance
+		engo.Mailbox.Dispatch(PinchMessage{Center: center, Scale: scale, TotalScale: distance / g.startDistance})
+		if g.CameraZoom && g.camera != nil {
+			// Spreading the touches brings the camera closer
+			engo.Mailbox.Dispatch(CameraMessage{Axis: ZAxis, Value: g.camera.Z() / scale})
+		}
+	}
+	g.pinchDistance = distance
+
+	if rotation := normalizeDegrees(angle - g.pinchAngle); rotation != 0 {
+		engo.Mailbox.Dispatch(RotateMessage{Center: center, Rotation: rotation, TotalRotation: normalizeDegrees(angle - g.startAngle)})
+		if g.CameraRotate && g.camera != nil {
+			// The world follows the touches, so the camera turns the other way
+			engo.Mailbox.Dispatch(CameraMessage{Axis: Angle, Value: -rotation, Incremental: true})
+		}
+	}
+	g.pinchAngle = angle
+}
+
+// normalizeDegrees returns the angle within the range of -180 to 180 degrees
+func normalizeDegrees(angle float32) float32 {
+	for angle > 180 {
+		angle -= 360
+	}
+	for angle <= -180 {
+		angle += 360
+	}
+	return angle
+}
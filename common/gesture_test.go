@@ -0,0 +1,152 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/stretchr/testify/assert"
+)
+
+// gestureRecorder collects the gesture messages which were dispatched
+type gestureRecorder struct {
+	taps, doubleTaps, longPresses []engo.Point
+	swipes                        []SwipeMessage
+	drags                         []DragMessage
+	pinches                       []PinchMessage
+	rotations                     []RotateMessage
+}
+
+func setupGestureTest(t *testing.T) (*GestureSystem, *gestureRecorder) {
+	mailbox, input := engo.Mailbox, engo.Input
+	t.Cleanup(func() { engo.Mailbox, engo.Input = mailbox, input })
+	engo.Mailbox = &engo.MessageManager{}
+	engo.Input = engo.NewInputManager()
+
+	r := &gestureRecorder{}
+	engo.ListenFor(engo.Mailbox, func(msg TapMessage) { r.taps = append(r.taps, msg.Position) })
+	engo.ListenFor(engo.Mailbox, func(msg DoubleTapMessage) { r.doubleTaps = append(r.doubleTaps, msg.Position) })
+	engo.ListenFor(engo.Mailbox, func(msg LongPressMessage) { r.longPresses = append(r.longPresses, msg.Position) })
+	engo.ListenFor(engo.Mailbox, func(msg SwipeMessage) { r.swipes = append(r.swipes, msg) })
+	engo.ListenFor(engo.Mailbox, func(msg DragMessage) { r.drags = append(r.drags, msg) })
+	engo.ListenFor(engo.Mailbox, func(msg PinchMessage) { r.pinches = append(r.pinches, msg) })
+	engo.ListenFor(engo.Mailbox, func(msg RotateMessage) { r.rotations = append(r.rotations, msg) })
+
+	return &GestureSystem{}, r
+}
+
+// touchFrame replaces the touches and updates the GestureSystem
+func touchFrame(g *GestureSystem, dt float32, touches map[int]engo.Point) {
+	engo.Input.Touches = touches
+	g.Update(dt)
+}
+
+func TestGestureTap(t *testing.T) {
+	g, r := setupGestureTest(t)
+
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 100, Y: 100}})
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 103, Y: 101}})
+	touchFrame(g, 0.05, map[int]engo.Point{})
+	assert.Equal(t, []engo.Point{{X: 103, Y: 101}}, r.taps, "A short touch should have been a tap")
+
+	touchFrame(g, 0.1, map[int]engo.Point{1: {X: 105, Y: 100}})
+	touchFrame(g, 0.05, map[int]engo.Point{})
+	assert.Len(t, r.taps, 1, "The second tap should not have been a tap of its own")
+	assert.Equal(t, []engo.Point{{X: 105, Y: 100}}, r.doubleTaps, "Two quick taps should have been a double tap")
+
+	// Too slow for a double tap
+	touchFrame(g, 1, map[int]engo.Point{2: {X: 100, Y: 100}})
+	touchFrame(g, 0.05, map[int]engo.Point{})
+	touchFrame(g, 0.5, map[int]engo.Point{3: {X: 100, Y: 100}})
+	touchFrame(g, 0.05, map[int]engo.Point{})
+	assert.Len(t, r.taps, 3, "Slow taps should have been separate taps")
+	assert.Len(t, r.doubleTaps, 1, "Slow taps should not have been a double tap")
+}
+
+func TestGestureLongPress(t *testing.T) {
+	g, r := setupGestureTest(t)
+
+	touchFrame(g, 0.1, map[int]engo.Point{0: {X: 10, Y: 10}})
+	touchFrame(g, 0.3, map[int]engo.Point{0: {X: 10, Y: 10}})
+	assert.Empty(t, r.longPresses, "Long press should not have happened yet")
+	touchFrame(g, 0.3, map[int]engo.Point{0: {X: 12, Y: 10}})
+	assert.Equal(t, []engo.Point{{X: 12, Y: 10}}, r.longPresses, "Holding a touch should have been a long press")
+	touchFrame(g, 0.3, map[int]engo.Point{0: {X: 12, Y: 10}})
+	touchFrame(g, 0.05, map[int]engo.Point{})
+	assert.Len(t, r.longPresses, 1, "Long press should have been dispatched once")
+	assert.Empty(t, r.taps, "Releasing a long press should not have been a tap")
+}
+
+func TestGestureSwipeAndDrag(t *testing.T) {
+	g, r := setupGestureTest(t)
+
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 0, Y: 0}})
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 50, Y: 0}})
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 100, Y: 0}})
+	touchFrame(g, 0.05, map[int]engo.Point{})
+
+	if assert.Len(t, r.swipes, 1, "A fast movement should have been a swipe") {
+		assert.Equal(t, engo.Point{X: 100, Y: 0}, r.swipes[0].End)
+		assert.InDelta(t, 666.7, r.swipes[0].Velocity.X, 0.1, "Swipe should have moved 100 units in 0.15 seconds")
+	}
+	if assert.Len(t, r.drags, 2, "Each movement should have been a drag") {
+		assert.Equal(t, engo.Point{X: 50, Y: 0}, r.drags[1].Delta)
+	}
+
+	// Moving slowly is only a drag
+	touchFrame(g, 0.05, map[int]engo.Point{1: {X: 0, Y: 0}})
+	touchFrame(g, 1, map[int]engo.Point{1: {X: 100, Y: 0}})
+	touchFrame(g, 0.05, map[int]engo.Point{})
+	assert.Len(t, r.swipes, 1, "A slow movement should not have been a swipe")
+	assert.Len(t, r.drags, 3)
+}
+
+func TestGesturePinchAndRotate(t *testing.T) {
+	g, r := setupGestureTest(t)
+
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 90, Y: 100}, 1: {X: 110, Y: 100}})
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 90, Y: 100}, 1: {X: 110, Y: 100}})
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 80, Y: 100}, 1: {X: 120, Y: 100}})
+	if assert.Len(t, r.pinches, 1, "Spreading the touches should have been a pinch") {
+		assert.InDelta(t, 2, r.pinches[0].Scale, 0.001)
+		assert.Equal(t, engo.Point{X: 100, Y: 100}, r.pinches[0].Center)
+	}
+	assert.Empty(t, r.rotations, "Spreading the touches should not have been a rotation")
+
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 100, Y: 80}, 1: {X: 100, Y: 120}})
+	if assert.Len(t, r.rotations, 1, "Turning the touches should have been a rotation") {
+		assert.InDelta(t, 90, r.rotations[0].Rotation, 0.001)
+	}
+
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 100, Y: 80}})
+	touchFrame(g, 0.05, map[int]engo.Point{})
+	assert.Empty(t, r.taps, "Lifting the touches of a pinch should not have been a tap")
+	assert.Empty(t, r.drags, "Two touches should not have been a drag")
+}
+
+func TestGestureCamera(t *testing.T) {
+	g, _ := setupGestureTest(t)
+	CameraBounds = engo.AABB{Max: engo.Point{X: 300, Y: 300}}
+	engo.SetGlobalScale(engo.Point{X: 1, Y: 1})
+	w := &ecs.World{}
+	w.AddSystem(&CameraSystem{})
+	g.CameraPan, g.CameraZoom = true, true
+	w.AddSystem(g)
+
+	var camera *CameraSystem
+	for _, sys := range w.Systems() {
+		if cam, ok := sys.(*CameraSystem); ok {
+			camera = cam
+		}
+	}
+	x := camera.X()
+
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 100, Y: 100}})
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 120, Y: 100}})
+	assert.Equal(t, x-20, camera.X(), "Dragging right should have moved the camera left")
+	touchFrame(g, 0.05, map[int]engo.Point{})
+
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 90, Y: 100}, 1: {X: 110, Y: 100}})
+	touchFrame(g, 0.05, map[int]engo.Point{0: {X: 95, Y: 100}, 1: {X: 105, Y: 100}})
+	assert.Equal(t, float32(2), camera.Z(), "Pinching the touches together should have zoomed out")
+}
@@ -83,6 +83,10 @@ type RenderComponent struct {
 	// screen. Higher z-indices are drawn on top of lower ones. Beware that you must use `SetZIndex` function to change
 	// the Z-Index.
 	StartZIndex float32
+	// Interpolate smooths out the movement of the entity when its SpaceComponent is moved during
+	// fixed-step updates (see engo.FixedUpdater), by drawing it in between its positions of the last two
+	// ticks. Entities that are moved during the regular Update should leave this off.
+	Interpolate bool
 
 	magFilter, minFilter ZoomFilter
 
@@ -147,6 +151,24 @@ type renderEntity struct {
 	*ecs.BasicEntity
 	*RenderComponent
 	*SpaceComponent
+
+	// previous and current are the positions of the entity after the last two fixed-step ticks
+	previous, current engo.Point
+}
+
+// interpolated returns the SpaceComponent to draw the entity at, which lies between its positions of the
+// last two fixed-step ticks if it's being interpolated.
+func (e *renderEntity) interpolated(alpha float32) *SpaceComponent {
+	if !e.RenderComponent.Interpolate || engo.FixedTickRate() == 0 || e.Position != e.current {
+		return e.SpaceComponent
+	}
+
+	space := *e.SpaceComponent
+	space.Position = engo.Point{
+		X: e.previous.X + (e.current.X-e.previous.X)*alpha,
+		Y: e.previous.Y + (e.current.Y-e.previous.Y)*alpha,
+	}
+	return &space
 }
 
 type renderEntityList []renderEntity
@@ -239,7 +261,12 @@ func (rs *RenderSystem) New(w *ecs.World) {
 	})
 }
 
-var cameraInitMutex sync.Mutex
+var (
+	cameraInitMutex sync.Mutex
+
+	// renderedWorld is the World that was drawn most recently, so the shaders know when to switch cameras
+	renderedWorld *ecs.World
+)
 
 func addCameraSystemOnce(w *ecs.World) {
 	cameraInitMutex.Lock()
@@ -298,7 +325,13 @@ func (rs *RenderSystem) Add(basic *ecs.BasicEntity, render *RenderComponent, spa
 		render.zIndex = render.StartZIndex
 	}
 
-	rs.entities = append(rs.entities, renderEntity{basic, render, space})
+	rs.entities = append(rs.entities, renderEntity{
+		BasicEntity:     basic,
+		RenderComponent: render,
+		SpaceComponent:  space,
+		previous:        space.Position,
+		current:         space.Position,
+	})
 	rs.sortingNeeded = true
 }
 
@@ -329,9 +362,19 @@ func (rs *RenderSystem) Remove(basic ecs.BasicEntity) {
 	delete(rs.ids, basic.ID())
 }
 
+// FixedUpdate keeps track of the positions of the entities after each fixed-step tick, so they can be
+// interpolated when drawn. As the RenderSystem has the lowest priority, this happens after all other
+// Systems have moved them. It implements the engo.FixedUpdater interface.
+func (rs *RenderSystem) FixedUpdate(dt float32) {
+	for i := range rs.entities {
+		rs.entities[i].previous = rs.entities[i].current
+		rs.entities[i].current = rs.entities[i].Position
+	}
+}
+
 // Update draws the entities in the RenderSystem to the OpenGL Surface.
 func (rs *RenderSystem) Update(dt float32) {
-	if engo.Headless() {
+	if engo.Headless() || !engo.SceneVisible() {
 		return
 	}
 
@@ -340,12 +383,18 @@ func (rs *RenderSystem) Update(dt float32) {
 		rs.sortingNeeded = false
 	}
 
-	if rs.newCamera {
+	// Multiple Worlds can be drawn each frame when Scenes are stacked, each one with its own camera
+	if rs.newCamera || renderedWorld != rs.world {
 		newCamera(rs.world)
 		rs.newCamera = false
+		renderedWorld = rs.world
 	}
 
-	engo.Gl.Clear(engo.Gl.COLOR_BUFFER_BIT)
+	if !engo.DrawingOverlay() {
+		engo.Gl.Clear(engo.Gl.COLOR_BUFFER_BIT)
+	}
+
+	alpha := engo.InterpolationAlpha()
 
 	preparedCullingShaders := make(map[CullingShader]struct{})
 	var cullingShader CullingShader // current culling shader
@@ -353,11 +402,14 @@ func (rs *RenderSystem) Update(dt float32) {
 	var currentShader Shader        // currently "active" shader
 
 	// TODO: it's linear for now, but that might very well be a bad idea
-	for _, e := range rs.entities {
+	for i := range rs.entities {
+		e := &rs.entities[i]
 		if e.RenderComponent.Hidden {
 			continue // with other entities
 		}
 
+		space := e.interpolated(alpha)
+
 		// Retrieve a shader, may be the default one -- then use it if we aren't already using it
 		shader := e.RenderComponent.shader
 
@@ -375,7 +427,7 @@ func (rs *RenderSystem) Update(dt float32) {
 			}
 		}
 
-		if cullingShader != nil && !cullingShader.ShouldDraw(e.RenderComponent, e.SpaceComponent) {
+		if cullingShader != nil && !cullingShader.ShouldDraw(e.RenderComponent, space) {
 			continue
 		}
 
@@ -398,7 +450,7 @@ func (rs *RenderSystem) Update(dt float32) {
 			e.RenderComponent.Color = color.White
 		}
 
-		currentShader.Draw(e.RenderComponent, e.SpaceComponent)
+		currentShader.Draw(e.RenderComponent, space)
 	}
 
 	if currentShader != nil {
@@ -44,12 +44,30 @@ type imageLoader struct {
 	images map[string]TextureResource
 }
 
+// decodedImage is an image decoded by the imageLoader, which has not been uploaded to the GPU yet
+type decodedImage struct {
+	img *image.NRGBA
+
+	// frameWidth and frameHeight are the size of a single frame of a .gif, which is turned into a Spritesheet
+	frameWidth, frameHeight int
+}
+
 func (i *imageLoader) Load(url string, data io.Reader) error {
-	var res TextureResource
+	decoded, err := i.Decode(url, data)
+	if err != nil {
+		return err
+	}
+
+	return i.Finish(url, decoded)
+}
+
+// Decode decodes the image without uploading it to the GPU, so it can be used from any goroutine. It
+// implements the engo.AsyncFileLoader interface.
+func (i *imageLoader) Decode(url string, data io.Reader) (interface{}, error) {
 	if getExt(url) == ".svg" {
 		icon, err := oksvg.ReadIconStream(data, oksvg.WarnErrorMode)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		w, h := int(icon.ViewBox.W), int(icon.ViewBox.H)
 		img := image.NewRGBA(image.Rect(0, 0, w, h))
@@ -59,11 +77,11 @@ func (i *imageLoader) Load(url string, data io.Reader) error {
 		b := img.Bounds()
 		newm := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
 		draw.Draw(newm, newm.Bounds(), img, b.Min, draw.Src)
-		res = NewTextureResource(&ImageObject{newm})
+		return decodedImage{img: newm}, nil
 	} else if getExt(url) == ".gif" {
 		img, err := gif.DecodeAll(data)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		l := int(math.Ceil(math.Sqrt(float32(len(img.Image)))))
 		w, h := img.Config.Width, img.Config.Height
@@ -76,20 +94,32 @@ func (i *imageLoader) Load(url string, data io.Reader) error {
 				draw.Draw(newm, image.Rect(i*w, j*h, (i+1)*w, (j+1)*h), img.Image[i*l+j], image.Pt(0, 0), draw.Src)
 			}
 		}
-		res = NewTextureResource(&ImageObject{newm})
-		res.url = url
-		NewSpritesheetFromTexture(&res, w, h)
-	} else {
-		img, _, err := image.Decode(data)
-		if err != nil {
-			return err
-		}
-		b := img.Bounds()
-		newm := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
-		draw.Draw(newm, newm.Bounds(), img, b.Min, draw.Src)
-		res = NewTextureResource(&ImageObject{newm})
+		return decodedImage{img: newm, frameWidth: w, frameHeight: h}, nil
+	}
+
+	img, _, err := image.Decode(data)
+	if err != nil {
+		return nil, err
 	}
+	b := img.Bounds()
+	newm := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(newm, newm.Bounds(), img, b.Min, draw.Src)
+	return decodedImage{img: newm}, nil
+}
+
+// Finish uploads an image returned by Decode to the GPU, and stores the resulting TextureResource. It
+// implements the engo.AsyncFileLoader interface.
+func (i *imageLoader) Finish(url string, decoded interface{}) error {
+	d, ok := decoded.(decodedImage)
+	if !ok {
+		return fmt.Errorf("image not decoded by the imageLoader: %q", url)
+	}
+
+	res := NewTextureResource(&ImageObject{d.img})
 	res.url = url
+	if d.frameWidth > 0 && d.frameHeight > 0 {
+		NewSpritesheetFromTexture(&res, d.frameWidth, d.frameHeight)
+	}
 	i.images[url] = res
 
 	return nil
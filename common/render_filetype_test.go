@@ -0,0 +1,55 @@
+package common
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/EngoEngine/engo"
+)
+
+type imageLoaderTestScene struct{}
+
+func (*imageLoaderTestScene) Preload() {}
+
+func (*imageLoaderTestScene) Setup(engo.Updater) {}
+
+func (*imageLoaderTestScene) Type() string { return "imageLoaderTestScene" }
+
+func TestImageLoaderDecodeFinish(t *testing.T) {
+	engo.Run(engo.RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, &imageLoaderTestScene{})
+
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, image.NewNRGBA(image.Rect(0, 0, 4, 2))); err != nil {
+		t.Fatalf("Unable to encode test image: %v", err)
+	}
+
+	var loader engo.AsyncFileLoader = imgLoader
+	decoded, err := loader.Decode("decoded.png", buf)
+	if err != nil {
+		t.Fatalf("Unable to decode image: %v", err)
+	}
+	if _, err = loader.Resource("decoded.png"); err == nil {
+		t.Error("Decoded image was available before it was finished")
+	}
+
+	if err = loader.Finish("decoded.png", decoded); err != nil {
+		t.Fatalf("Unable to finish image: %v", err)
+	}
+	res, err := loader.Resource("decoded.png")
+	if err != nil {
+		t.Fatalf("Finished image was not available: %v", err)
+	}
+	tex := res.(TextureResource)
+	if tex.Width != 4 || tex.Height != 2 || tex.URL() != "decoded.png" {
+		t.Errorf("Finished image was not correct, got: %v x %v at %q", tex.Width, tex.Height, tex.URL())
+	}
+
+	if err = loader.Finish("other.png", "not an image"); err == nil {
+		t.Error("Finishing something that wasn't decoded by the loader did not fail")
+	}
+}
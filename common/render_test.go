@@ -0,0 +1,62 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+)
+
+type fixedStepTestScene struct{}
+
+func (*fixedStepTestScene) Preload() {}
+
+func (*fixedStepTestScene) Setup(engo.Updater) {}
+
+func (*fixedStepTestScene) Type() string { return "fixedStepTestScene" }
+
+func TestRenderSystemInterpolation(t *testing.T) {
+	engo.Run(engo.RunOptions{
+		NoRun:         true,
+		HeadlessMode:  true,
+		FixedTickRate: 10,
+	}, &fixedStepTestScene{})
+	defer engo.SetFixedTickRate(0)
+
+	rs := &RenderSystem{}
+	rs.New(&ecs.World{})
+
+	basic := ecs.NewBasic()
+	render := &RenderComponent{Drawable: Rectangle{}, Interpolate: true}
+	space := &SpaceComponent{Position: engo.Point{X: 10, Y: 20}}
+	rs.Add(&basic, render, space)
+	e := &rs.entities[0]
+
+	if got := e.interpolated(0.5).Position; got != space.Position {
+		t.Errorf("Entity which did not move yet was not drawn in place, got: %v", got)
+	}
+
+	space.Position = engo.Point{X: 20, Y: 40}
+	rs.FixedUpdate(engo.FixedDelta())
+
+	if got := e.interpolated(0.5).Position; got != (engo.Point{X: 15, Y: 30}) {
+		t.Errorf("Entity was not drawn halfway between ticks, got: %v", got)
+	}
+	if got := e.interpolated(1).Position; got != space.Position {
+		t.Errorf("Entity was not drawn at its current position at the end of the tick, got: %v", got)
+	}
+	if space.Position != (engo.Point{X: 20, Y: 40}) {
+		t.Error("Interpolating changed the position of the entity")
+	}
+
+	space.Position = engo.Point{X: 100, Y: 100}
+	if got := e.interpolated(0.5).Position; got != space.Position {
+		t.Errorf("Entity moved outside of a tick should be drawn in place, got: %v", got)
+	}
+
+	render.Interpolate = false
+	space.Position = engo.Point{X: 20, Y: 40}
+	if got := e.interpolated(0.5).Position; got != space.Position {
+		t.Errorf("Entity which isn't interpolated should be drawn in place, got: %v", got)
+	}
+}
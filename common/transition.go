@@ -0,0 +1,285 @@
+package common
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/gl"
+)
+
+// SlideDirection is the direction in which the Scenes move during a slide
+// transition.
+type SlideDirection uint8
+
+const (
+	// SlideLeft moves the outgoing Scene out to the left, and the incoming
+	// Scene in from the right.
+	SlideLeft SlideDirection = iota
+	// SlideRight moves the outgoing Scene out to the right, and the incoming
+	// Scene in from the left.
+	SlideRight
+	// SlideUp moves the outgoing Scene out to the top, and the incoming Scene
+	// in from the bottom.
+	SlideUp
+	// SlideDown moves the outgoing Scene out to the bottom, and the incoming
+	// Scene in from the top.
+	SlideDown
+)
+
+const (
+	transitionVertexShader = `
+	attribute vec2 in_Position;
+
+	varying vec2 var_TexCoords;
+
+	void main() {
+	  var_TexCoords = (in_Position + 1.0) / 2.0;
+	  gl_Position = vec4(in_Position, 0, 1);
+	}
+`
+
+	transitionShaderHeader = `
+	#ifdef GL_ES
+	#define LOWP lowp
+	precision mediump float;
+	#else
+	#define LOWP
+	#endif
+
+	varying vec2 var_TexCoords;
+
+	uniform sampler2D uf_From;
+	uniform sampler2D uf_To;
+	uniform float uf_Progress;
+	uniform vec4 uf_Color;
+	uniform vec2 uf_Direction;
+`
+
+	// FadeFragmentShader fades the outgoing Scene to uf_Color during the first
+	// half of the transition, and fades from uf_Color to the incoming Scene
+	// during the second half.
+	FadeFragmentShader = transitionShaderHeader + `
+	void main (void) {
+	  if (uf_Progress < 0.5) {
+	    gl_FragColor = mix(texture2D(uf_From, var_TexCoords), uf_Color, uf_Progress * 2.0);
+	  } else {
+	    gl_FragColor = mix(uf_Color, texture2D(uf_To, var_TexCoords), uf_Progress * 2.0 - 1.0);
+	  }
+	}
+`
+
+	// CrossfadeFragmentShader blends the outgoing Scene into the incoming
+	// Scene.
+	CrossfadeFragmentShader = transitionShaderHeader + `
+	void main (void) {
+	  gl_FragColor = mix(texture2D(uf_From, var_TexCoords), texture2D(uf_To, var_TexCoords), uf_Progress);
+	}
+`
+
+	// SlideFragmentShader moves the outgoing Scene out of the screen towards
+	// uf_Direction, while the incoming Scene moves in right behind it.
+	SlideFragmentShader = transitionShaderHeader + `
+	void main (void) {
+	  vec2 coords = var_TexCoords - uf_Direction * uf_Progress;
+	  if (coords.x >= 0.0 && coords.x <= 1.0 && coords.y >= 0.0 && coords.y <= 1.0) {
+	    gl_FragColor = texture2D(uf_From, coords);
+	  } else {
+	    gl_FragColor = texture2D(uf_To, coords + uf_Direction);
+	  }
+	}
+`
+)
+
+// SceneTransition is an engo.Transition which draws the outgoing and incoming
+// Scenes to RenderTextures, and then combines both textures on the screen using
+// a fragment shader.
+//
+// The fragment shader has access to the texture coordinates as `varying vec2
+// var_TexCoords`, and to the following uniforms:
+//
+//	uniform sampler2D uf_From;   // the outgoing Scene
+//	uniform sampler2D uf_To;     // the incoming Scene
+//	uniform float uf_Progress;   // goes from 0 to 1 during the transition
+//	uniform vec4 uf_Color;       // the Color of the SceneTransition
+//	uniform vec2 uf_Direction;   // the Direction of the SceneTransition
+type SceneTransition struct {
+	// Length is how long the transition takes
+	Length time.Duration
+	// FragmentShader is the source of the fragment shader used to combine both
+	// Scenes
+	FragmentShader string
+	// Color is passed to the fragment shader as uf_Color, defaults to black
+	Color color.Color
+	// Direction is passed to the fragment shader as uf_Direction
+	Direction engo.Point
+
+	program      *gl.Program
+	vertexBuffer *gl.Buffer
+	inPosition   int
+
+	ufFrom, ufTo, ufProgress, ufColor, ufDirection *gl.UniformLocation
+
+	framebuffer *Framebuffer
+	from, to    *RenderTexture
+}
+
+// NewFadeTransition creates a SceneTransition which fades the outgoing Scene to
+// the given color, and then fades from that color to the incoming Scene.
+func NewFadeTransition(length time.Duration, c color.Color) *SceneTransition {
+	return &SceneTransition{
+		Length:         length,
+		FragmentShader: FadeFragmentShader,
+		Color:          c,
+	}
+}
+
+// NewCrossfadeTransition creates a SceneTransition which blends the outgoing
+// Scene into the incoming Scene.
+func NewCrossfadeTransition(length time.Duration) *SceneTransition {
+	return &SceneTransition{
+		Length:         length,
+		FragmentShader: CrossfadeFragmentShader,
+	}
+}
+
+// NewSlideTransition creates a SceneTransition which moves the outgoing Scene
+// out of the screen in the given direction, with the incoming Scene following
+// right behind it.
+func NewSlideTransition(length time.Duration, dir SlideDirection) *SceneTransition {
+	t := &SceneTransition{
+		Length:         length,
+		FragmentShader: SlideFragmentShader,
+	}
+
+	switch dir {
+	case SlideLeft:
+		t.Direction = engo.Point{X: -1, Y: 0}
+	case SlideRight:
+		t.Direction = engo.Point{X: 1, Y: 0}
+	case SlideUp:
+		t.Direction = engo.Point{X: 0, Y: 1}
+	case SlideDown:
+		t.Direction = engo.Point{X: 0, Y: -1}
+	}
+
+	return t
+}
+
+// Duration returns how long the transition takes. It implements the
+// engo.Transition interface.
+func (t *SceneTransition) Duration() time.Duration {
+	return t.Length
+}
+
+// Begin compiles the shader if needed, and creates the RenderTextures both
+// Scenes are drawn to. It implements the engo.Transition interface.
+func (t *SceneTransition) Begin() {
+	if engo.Headless() {
+		return
+	}
+
+	if t.program == nil {
+		if err := t.setup(); err != nil {
+			panic(err)
+		}
+	}
+
+	width, height := int(engo.CanvasWidth()), int(engo.CanvasHeight())
+	t.framebuffer = CreateFramebuffer()
+	t.from = CreateRenderTexture(width, height, false)
+	t.to = CreateRenderTexture(width, height, false)
+
+	// Non power of two textures can't be repeated in WebGL
+	for _, tex := range []*RenderTexture{t.from, t.to} {
+		engo.Gl.BindTexture(engo.Gl.TEXTURE_2D, tex.Texture())
+		engo.Gl.TexParameteri(engo.Gl.TEXTURE_2D, engo.Gl.TEXTURE_WRAP_S, engo.Gl.CLAMP_TO_EDGE)
+		engo.Gl.TexParameteri(engo.Gl.TEXTURE_2D, engo.Gl.TEXTURE_WRAP_T, engo.Gl.CLAMP_TO_EDGE)
+	}
+	engo.Gl.BindTexture(engo.Gl.TEXTURE_2D, nil)
+}
+
+func (t *SceneTransition) setup() error {
+	var err error
+	t.program, err = LoadShader(transitionVertexShader, t.FragmentShader)
+	if err != nil {
+		return err
+	}
+
+	t.inPosition = engo.Gl.GetAttribLocation(t.program, "in_Position")
+	t.ufFrom = engo.Gl.GetUniformLocation(t.program, "uf_From")
+	t.ufTo = engo.Gl.GetUniformLocation(t.program, "uf_To")
+	t.ufProgress = engo.Gl.GetUniformLocation(t.program, "uf_Progress")
+	t.ufColor = engo.Gl.GetUniformLocation(t.program, "uf_Color")
+	t.ufDirection = engo.Gl.GetUniformLocation(t.program, "uf_Direction")
+
+	// A quad covering the entire screen
+	t.vertexBuffer = engo.Gl.CreateBuffer()
+	engo.Gl.BindBuffer(engo.Gl.ARRAY_BUFFER, t.vertexBuffer)
+	engo.Gl.BufferData(engo.Gl.ARRAY_BUFFER, []float32{-1, -1, 1, -1, -1, 1, 1, 1}, engo.Gl.STATIC_DRAW)
+	engo.Gl.BindBuffer(engo.Gl.ARRAY_BUFFER, nil)
+
+	return nil
+}
+
+// Draw draws both Scenes to their RenderTexture, and combines them on the
+// screen. It implements the engo.Transition interface.
+func (t *SceneTransition) Draw(progress float32, drawOut, drawIn func()) {
+	if engo.Headless() {
+		drawOut()
+		drawIn()
+		return
+	}
+
+	t.framebuffer.Open(int(t.from.Width()), int(t.from.Height()))
+	t.from.Bind()
+	drawOut()
+	t.to.Bind()
+	drawIn()
+	t.framebuffer.Close()
+
+	c := t.Color
+	if c == nil {
+		c = color.Black
+	}
+	r, g, b, a := c.RGBA()
+
+	engo.Gl.Clear(engo.Gl.COLOR_BUFFER_BIT)
+	engo.Gl.UseProgram(t.program)
+
+	engo.Gl.ActiveTexture(engo.Gl.TEXTURE1)
+	engo.Gl.BindTexture(engo.Gl.TEXTURE_2D, t.to.Texture())
+	engo.Gl.ActiveTexture(engo.Gl.TEXTURE0)
+	engo.Gl.BindTexture(engo.Gl.TEXTURE_2D, t.from.Texture())
+
+	engo.Gl.Uniform1i(t.ufFrom, 0)
+	engo.Gl.Uniform1i(t.ufTo, 1)
+	engo.Gl.Uniform1f(t.ufProgress, progress)
+	engo.Gl.Uniform4f(t.ufColor, float32(r)/0xffff, float32(g)/0xffff, float32(b)/0xffff, float32(a)/0xffff)
+	engo.Gl.Uniform2f(t.ufDirection, t.Direction.X, t.Direction.Y)
+
+	engo.Gl.BindBuffer(engo.Gl.ARRAY_BUFFER, t.vertexBuffer)
+	engo.Gl.EnableVertexAttribArray(t.inPosition)
+	engo.Gl.VertexAttribPointer(t.inPosition, 2, engo.Gl.FLOAT, false, 8, 0)
+	engo.Gl.DrawArrays(engo.Gl.TRIANGLE_STRIP, 0, 4)
+
+	// Cleanup
+	engo.Gl.DisableVertexAttribArray(t.inPosition)
+	engo.Gl.BindBuffer(engo.Gl.ARRAY_BUFFER, nil)
+	engo.Gl.ActiveTexture(engo.Gl.TEXTURE1)
+	engo.Gl.BindTexture(engo.Gl.TEXTURE_2D, nil)
+	engo.Gl.ActiveTexture(engo.Gl.TEXTURE0)
+	engo.Gl.BindTexture(engo.Gl.TEXTURE_2D, nil)
+}
+
+// End releases the RenderTextures both Scenes were drawn to. It implements the
+// engo.Transition interface.
+func (t *SceneTransition) End() {
+	if engo.Headless() {
+		return
+	}
+
+	t.from.Close()
+	t.to.Close()
+	t.framebuffer.Destroy()
+}
@@ -0,0 +1,76 @@
+package common
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/EngoEngine/engo"
+)
+
+func TestNewSlideTransition(t *testing.T) {
+	data := []struct {
+		dir SlideDirection
+		exp engo.Point
+	}{
+		{SlideLeft, engo.Point{X: -1, Y: 0}},
+		{SlideRight, engo.Point{X: 1, Y: 0}},
+		{SlideUp, engo.Point{X: 0, Y: 1}},
+		{SlideDown, engo.Point{X: 0, Y: -1}},
+	}
+
+	for _, d := range data {
+		tr := NewSlideTransition(time.Second, d.dir)
+		if tr.Direction != d.exp {
+			t.Errorf("Slide transition had the wrong direction. Wanted: %v, got: %v", d.exp, tr.Direction)
+		}
+		if tr.FragmentShader != SlideFragmentShader {
+			t.Error("Slide transition did not use the slide fragment shader")
+		}
+		if tr.Duration() != time.Second {
+			t.Errorf("Slide transition had the wrong duration. Wanted: %v, got: %v", time.Second, tr.Duration())
+		}
+	}
+}
+
+func TestNewFadeTransitions(t *testing.T) {
+	fade := NewFadeTransition(time.Second, color.White)
+	if fade.FragmentShader != FadeFragmentShader || fade.Color != color.White {
+		t.Error("Fade transition was not created properly")
+	}
+
+	crossfade := NewCrossfadeTransition(2 * time.Second)
+	if crossfade.FragmentShader != CrossfadeFragmentShader || crossfade.Duration() != 2*time.Second {
+		t.Error("Crossfade transition was not created properly")
+	}
+}
+
+type transitionTestScene struct{}
+
+func (*transitionTestScene) Preload() {}
+
+func (*transitionTestScene) Setup(engo.Updater) {}
+
+func (*transitionTestScene) Type() string { return "transitionTestScene" }
+
+func TestSceneTransitionHeadless(t *testing.T) {
+	engo.Run(engo.RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, &transitionTestScene{})
+
+	tr := NewCrossfadeTransition(time.Second)
+	tr.Begin()
+
+	var drawn []string
+	tr.Draw(0.5, func() {
+		drawn = append(drawn, "out")
+	}, func() {
+		drawn = append(drawn, "in")
+	})
+	tr.End()
+
+	if len(drawn) != 2 || drawn[0] != "out" || drawn[1] != "in" {
+		t.Errorf("Both scenes were not drawn in order, got: %v", drawn)
+	}
+}
@@ -0,0 +1,472 @@
+package common
+
+import (
+	"image/color"
+	"log"
+	"time"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/math"
+)
+
+// RepeatForever makes a Tweener repeat until it is stopped, when used as its
+// Repeat.
+const RepeatForever = -1
+
+// Tweener is something the TweenSystem can play: a Tween, a TweenSequence or a
+// TweenGroup.
+type Tweener interface {
+	// Length returns how long it takes to play, including delays and
+	// repetitions, or a negative duration if it repeats forever.
+	Length() time.Duration
+
+	// length is the Length in seconds
+	length() float32
+	// seek sets the animated values to where they are t seconds after starting
+	seek(t float32)
+	// reset forgets about the values the Tweener started from, so it can be
+	// played again
+	reset()
+}
+
+// TweenTiming holds the settings shared by all Tweeners, describing when and
+// how often they play.
+type TweenTiming struct {
+	// Delay is how long to wait before starting
+	Delay time.Duration
+	// Repeat is the number of times to play again after the first time, or
+	// RepeatForever
+	Repeat int
+	// Yoyo plays every other repetition backwards
+	Yoyo bool
+}
+
+// total returns the length of the Tweener in seconds, given the length of a
+// single cycle
+func (tt *TweenTiming) total(cycle float32) float32 {
+	if tt.Repeat < 0 || cycle < 0 {
+		return -1
+	}
+	return float32(tt.Delay.Seconds()) + cycle*float32(tt.Repeat+1)
+}
+
+// local converts t seconds after starting to the time within the current cycle,
+// and reports whether the delay has passed.
+func (tt *TweenTiming) local(t, cycle float32) (float32, bool) {
+	u := t - float32(tt.Delay.Seconds())
+	if u < 0 {
+		return 0, false
+	}
+	if cycle < 0 {
+		return u, true
+	}
+	if cycle == 0 {
+		return 0, true
+	}
+
+	n := int(u / cycle)
+	local := u - float32(n)*cycle
+	if tt.Repeat >= 0 && n > tt.Repeat {
+		n = tt.Repeat
+		local = cycle
+	}
+	if tt.Yoyo && n%2 == 1 {
+		local = cycle - local
+	}
+	return local, true
+}
+
+// Tween animates one or more float32 values from where they are when it starts,
+// to the values in To.
+type Tween struct {
+	TweenTiming
+
+	// Duration is how long it takes to go from the start to the end once
+	Duration time.Duration
+	// Easing controls the progress over time, defaults to Linear
+	Easing EaseFunc
+	// From are the values to start from. Leaving it nil starts from the values
+	// at the moment the Tween starts
+	From []float32
+	// To are the values to end up with
+	To []float32
+
+	get func() []float32
+	set func([]float32)
+
+	from    []float32
+	started bool
+}
+
+// NewTween creates a Tween which uses get to read the values it starts from,
+// and set to update the values while it's playing, going towards to. Use this
+// to animate values for which there isn't a more specific constructor.
+func NewTween(get func() []float32, set func([]float32), to []float32, d time.Duration) *Tween {
+	return &Tween{
+		Duration: d,
+		To:       to,
+		get:      get,
+		set:      set,
+	}
+}
+
+// NewFloatTween creates a Tween which animates the float32 at the given
+// pointer.
+func NewFloatTween(value *float32, to float32, d time.Duration) *Tween {
+	return NewFloatsTween([]*float32{value}, []float32{to}, d)
+}
+
+// NewFloatsTween creates a Tween which animates the float32s at the given
+// pointers all at once.
+func NewFloatsTween(values []*float32, to []float32, d time.Duration) *Tween {
+	return NewTween(func() []float32 {
+		res := make([]float32, len(values))
+		for i, v := range values {
+			res[i] = *v
+		}
+		return res
+	}, func(res []float32) {
+		for i, v := range values {
+			*v = res[i]
+		}
+	}, to, d)
+}
+
+// NewPositionTween creates a Tween which moves the SpaceComponent to the given
+// position.
+func NewPositionTween(space *SpaceComponent, to engo.Point, d time.Duration) *Tween {
+	return NewFloatsTween([]*float32{&space.Position.X, &space.Position.Y}, []float32{to.X, to.Y}, d)
+}
+
+// NewRotationTween creates a Tween which rotates the SpaceComponent to the
+// given angle, in degrees.
+func NewRotationTween(space *SpaceComponent, to float32, d time.Duration) *Tween {
+	return NewFloatTween(&space.Rotation, to, d)
+}
+
+// NewSizeTween creates a Tween which resizes the SpaceComponent to the given
+// width and height.
+func NewSizeTween(space *SpaceComponent, width, height float32, d time.Duration) *Tween {
+	return NewFloatsTween([]*float32{&space.Width, &space.Height}, []float32{width, height}, d)
+}
+
+// NewScaleTween creates a Tween which scales the RenderComponent to the given
+// scale.
+func NewScaleTween(render *RenderComponent, to engo.Point, d time.Duration) *Tween {
+	return NewFloatsTween([]*float32{&render.Scale.X, &render.Scale.Y}, []float32{to.X, to.Y}, d)
+}
+
+// NewColorTween creates a Tween which changes the Color of the RenderComponent
+// to the given color. The components of the colors are animated separately,
+// without their alpha premultiplied.
+func NewColorTween(render *RenderComponent, to color.Color, d time.Duration) *Tween {
+	return NewTween(func() []float32 {
+		c := render.Color
+		if c == nil {
+			c = color.White
+		}
+		return nrgbaToFloats(c)
+	}, func(res []float32) {
+		render.Color = color.NRGBA{
+			R: floatToColor(res[0]),
+			G: floatToColor(res[1]),
+			B: floatToColor(res[2]),
+			A: floatToColor(res[3]),
+		}
+	}, nrgbaToFloats(to), d)
+}
+
+func nrgbaToFloats(c color.Color) []float32 {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return []float32{float32(n.R), float32(n.G), float32(n.B), float32(n.A)}
+}
+
+func floatToColor(f float32) uint8 {
+	return uint8(math.Clamp(f+0.5, 0, 255))
+}
+
+// Length returns how long the Tween takes to play. It implements the Tweener
+// interface.
+func (tw *Tween) Length() time.Duration {
+	return secondsToDuration(tw.length())
+}
+
+func (tw *Tween) length() float32 {
+	return tw.total(float32(tw.Duration.Seconds()))
+}
+
+func (tw *Tween) seek(t float32) {
+	cycle := float32(tw.Duration.Seconds())
+	local, ok := tw.local(t, cycle)
+	if !ok {
+		// Going back to before the start, e.g. while a TweenSequence plays
+		// backwards
+		if tw.started {
+			tw.apply(0)
+		}
+		return
+	}
+
+	if !tw.started {
+		tw.from = tw.From
+		if tw.from == nil {
+			tw.from = tw.get()
+		}
+		tw.started = true
+		if len(tw.from) != len(tw.To) {
+			log.Printf("Unable to play Tween, it starts from %d values but goes to %d values. Skipping it.", len(tw.from), len(tw.To))
+		}
+	}
+
+	progress := float32(1)
+	if cycle > 0 {
+		progress = local / cycle
+	}
+	tw.apply(progress)
+}
+
+func (tw *Tween) apply(progress float32) {
+	if len(tw.from) != len(tw.To) {
+		return
+	}
+
+	ease := tw.Easing
+	if ease == nil {
+		ease = Linear
+	}
+	eased := ease(progress)
+
+	res := make([]float32, len(tw.To))
+	for i, to := range tw.To {
+		res[i] = tw.from[i] + (to-tw.from[i])*eased
+	}
+	tw.set(res)
+}
+
+func (tw *Tween) reset() {
+	tw.started = false
+	tw.from = nil
+}
+
+// TweenSequence plays its Tweeners one after another.
+type TweenSequence struct {
+	TweenTiming
+
+	// Tweens are played in order. Any Tweeners following one that repeats
+	// forever are never played.
+	Tweens []Tweener
+}
+
+// NewTweenSequence creates a TweenSequence which plays the given Tweeners one
+// after another.
+func NewTweenSequence(tweens ...Tweener) *TweenSequence {
+	return &TweenSequence{Tweens: tweens}
+}
+
+// Length returns how long the TweenSequence takes to play. It implements the
+// Tweener interface.
+func (ts *TweenSequence) Length() time.Duration {
+	return secondsToDuration(ts.length())
+}
+
+func (ts *TweenSequence) cycle() float32 {
+	var cycle float32
+	for _, tw := range ts.Tweens {
+		l := tw.length()
+		if l < 0 {
+			return -1
+		}
+		cycle += l
+	}
+	return cycle
+}
+
+func (ts *TweenSequence) length() float32 {
+	return ts.total(ts.cycle())
+}
+
+func (ts *TweenSequence) seek(t float32) {
+	local, ok := ts.local(t, ts.cycle())
+	if !ok {
+		local = -1
+	}
+
+	// Find the Tweener that is playing at the moment
+	offsets := make([]float32, len(ts.Tweens))
+	active := len(ts.Tweens) - 1
+	var offset float32
+	for i, tw := range ts.Tweens {
+		offsets[i] = offset
+		l := tw.length()
+		if l < 0 || local < offset+l {
+			active = i
+			break
+		}
+		offset += l
+	}
+
+	// The one that is playing goes last, so it wins when they share a value
+	for i := 0; i < active; i++ {
+		ts.Tweens[i].seek(local - offsets[i])
+	}
+	for i := len(ts.Tweens) - 1; i > active; i-- {
+		ts.Tweens[i].seek(-1)
+	}
+	if active >= 0 {
+		ts.Tweens[active].seek(local - offsets[active])
+	}
+}
+
+func (ts *TweenSequence) reset() {
+	for _, tw := range ts.Tweens {
+		tw.reset()
+	}
+}
+
+// TweenGroup plays its Tweeners all at once, and finishes when all of them have
+// finished.
+type TweenGroup struct {
+	TweenTiming
+
+	// Tweens are played at the same time
+	Tweens []Tweener
+}
+
+// NewTweenGroup creates a TweenGroup which plays the given Tweeners all at
+// once.
+func NewTweenGroup(tweens ...Tweener) *TweenGroup {
+	return &TweenGroup{Tweens: tweens}
+}
+
+// Length returns how long the TweenGroup takes to play. It implements the
+// Tweener interface.
+func (tg *TweenGroup) Length() time.Duration {
+	return secondsToDuration(tg.length())
+}
+
+func (tg *TweenGroup) cycle() float32 {
+	var cycle float32
+	for _, tw := range tg.Tweens {
+		l := tw.length()
+		if l < 0 {
+			return -1
+		}
+		if l > cycle {
+			cycle = l
+		}
+	}
+	return cycle
+}
+
+func (tg *TweenGroup) length() float32 {
+	return tg.total(tg.cycle())
+}
+
+func (tg *TweenGroup) seek(t float32) {
+	local, ok := tg.local(t, tg.cycle())
+	if !ok {
+		local = -1
+	}
+
+	for _, tw := range tg.Tweens {
+		tw.seek(local)
+	}
+}
+
+func (tg *TweenGroup) reset() {
+	for _, tw := range tg.Tweens {
+		tw.reset()
+	}
+}
+
+func secondsToDuration(s float32) time.Duration {
+	if s < 0 {
+		return -1
+	}
+	return time.Duration(float64(s) * float64(time.Second))
+}
+
+// TweenCompleteMessage is sent whenever a Tweener played by the TweenSystem has
+// finished.
+type TweenCompleteMessage struct {
+	Tween Tweener
+}
+
+// Type implements the engo.Message interface
+func (TweenCompleteMessage) Type() string { return "TweenCompleteMessage" }
+
+type playingTween struct {
+	tween   Tweener
+	elapsed float32
+}
+
+// TweenSystem plays Tweeners, animating the values they target over time.
+// Tweeners aren't tied to entities, so stop the ones targeting an entity before
+// removing it.
+type TweenSystem struct {
+	playing []*playingTween
+}
+
+// Remove does nothing, as the TweenSystem does not keep track of entities. It
+// is here to implement the ecs.System interface.
+func (*TweenSystem) Remove(ecs.BasicEntity) {}
+
+// Play starts playing the Tweener from the start. Playing a Tweener that is
+// already playing restarts it.
+func (ts *TweenSystem) Play(t Tweener) {
+	ts.Stop(t)
+	t.reset()
+	ts.playing = append(ts.playing, &playingTween{tween: t})
+}
+
+// Stop stops playing the Tweener, leaving the values it animates where they
+// are. No TweenCompleteMessage is sent.
+func (ts *TweenSystem) Stop(t Tweener) {
+	for i, p := range ts.playing {
+		if p.tween == t {
+			ts.playing = append(ts.playing[:i], ts.playing[i+1:]...)
+			return
+		}
+	}
+}
+
+// StopAll stops playing all Tweeners.
+func (ts *TweenSystem) StopAll() {
+	ts.playing = nil
+}
+
+// Playing reports whether the Tweener is being played.
+func (ts *TweenSystem) Playing(t Tweener) bool {
+	for _, p := range ts.playing {
+		if p.tween == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Update advances all Tweeners that are playing, and sends a
+// TweenCompleteMessage for each one that finished.
+func (ts *TweenSystem) Update(dt float32) {
+	var finished []Tweener
+	playing := ts.playing[:0]
+	for _, p := range ts.playing {
+		p.elapsed += dt
+		p.tween.seek(p.elapsed)
+
+		if l := p.tween.length(); l >= 0 && p.elapsed >= l {
+			finished = append(finished, p.tween)
+		} else {
+			playing = append(playing, p)
+		}
+	}
+	for i := len(playing); i < len(ts.playing); i++ {
+		ts.playing[i] = nil
+	}
+	ts.playing = playing
+
+	for _, t := range finished {
+		engo.Mailbox.Dispatch(TweenCompleteMessage{Tween: t})
+	}
+}
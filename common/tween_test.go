@@ -0,0 +1,235 @@
+package common
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/EngoEngine/engo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTweenFloat(t *testing.T) {
+	value := float32(10)
+	tw := NewFloatTween(&value, 20, time.Second)
+
+	ts := &TweenSystem{}
+	ts.Play(tw)
+	assert.True(t, ts.Playing(tw))
+
+	ts.Update(0.5)
+	assert.InDelta(t, 15, value, 0.001)
+
+	ts.Update(0.75)
+	assert.InDelta(t, 20, value, 0.001, "a Tween should end exactly at its target")
+	assert.False(t, ts.Playing(tw))
+	assert.Equal(t, time.Second, tw.Length())
+}
+
+func TestTweenEasing(t *testing.T) {
+	value := float32(0)
+	tw := NewFloatTween(&value, 100, time.Second)
+	tw.Easing = QuadIn
+
+	ts := &TweenSystem{}
+	ts.Play(tw)
+	ts.Update(0.5)
+	assert.InDelta(t, 25, value, 0.001)
+}
+
+func TestTweenFrom(t *testing.T) {
+	value := float32(50)
+	tw := NewFloatTween(&value, 100, time.Second)
+	tw.From = []float32{0}
+
+	ts := &TweenSystem{}
+	ts.Play(tw)
+	ts.Update(0.25)
+	assert.InDelta(t, 25, value, 0.001)
+}
+
+func TestTweenFromMismatch(t *testing.T) {
+	x, y := float32(50), float32(60)
+	tw := NewFloatsTween([]*float32{&x, &y}, []float32{100, 100}, time.Second)
+	tw.From = []float32{0}
+
+	ts := &TweenSystem{}
+	ts.Play(tw)
+	ts.Update(0.5)
+	assert.Equal(t, float32(50), x, "a Tween with too few values to start from should be skipped")
+	assert.Equal(t, float32(60), y)
+	ts.Update(0.75)
+	assert.False(t, ts.Playing(tw), "a skipped Tween should still finish")
+}
+
+func TestTweenDelay(t *testing.T) {
+	value := float32(0)
+	tw := NewFloatTween(&value, 10, time.Second)
+	tw.Delay = time.Second
+	assert.Equal(t, 2*time.Second, tw.Length())
+
+	ts := &TweenSystem{}
+	ts.Play(tw)
+	ts.Update(0.5)
+	assert.Equal(t, float32(0), value, "nothing should change during the delay")
+
+	// The start value is captured once the delay has passed
+	value = 5
+	ts.Update(1)
+	assert.InDelta(t, 7.5, value, 0.001)
+}
+
+func TestTweenRepeatYoyo(t *testing.T) {
+	value := float32(0)
+	tw := NewFloatTween(&value, 10, time.Second)
+	tw.Repeat = 1
+	tw.Yoyo = true
+	assert.Equal(t, 2*time.Second, tw.Length())
+
+	ts := &TweenSystem{}
+	ts.Play(tw)
+	ts.Update(0.5)
+	assert.InDelta(t, 5, value, 0.001)
+	ts.Update(0.5)
+	assert.InDelta(t, 10, value, 0.001)
+	ts.Update(0.25)
+	assert.InDelta(t, 7.5, value, 0.001, "the second repetition should play backwards")
+	ts.Update(1)
+	assert.InDelta(t, 0, value, 0.001)
+	assert.False(t, ts.Playing(tw))
+}
+
+func TestTweenRepeatForever(t *testing.T) {
+	value := float32(0)
+	tw := NewFloatTween(&value, 10, time.Second)
+	tw.Repeat = RepeatForever
+	assert.True(t, tw.Length() < 0)
+
+	ts := &TweenSystem{}
+	ts.Play(tw)
+	for i := 0; i < 10; i++ {
+		ts.Update(0.75)
+	}
+	assert.True(t, ts.Playing(tw))
+	assert.InDelta(t, 5, value, 0.001)
+
+	ts.Stop(tw)
+	assert.False(t, ts.Playing(tw))
+}
+
+func TestTweenSequence(t *testing.T) {
+	space := &SpaceComponent{}
+	seq := NewTweenSequence(
+		NewPositionTween(space, engo.Point{X: 10, Y: 0}, time.Second),
+		NewPositionTween(space, engo.Point{X: 10, Y: 20}, time.Second),
+	)
+	assert.Equal(t, 2*time.Second, seq.Length())
+
+	ts := &TweenSystem{}
+	ts.Play(seq)
+	ts.Update(0.5)
+	assert.InDelta(t, 5, space.Position.X, 0.001)
+	assert.InDelta(t, 0, space.Position.Y, 0.001)
+
+	// Skipping past the first Tween still finishes it before starting the
+	// second
+	ts.Update(1)
+	assert.InDelta(t, 10, space.Position.X, 0.001)
+	assert.InDelta(t, 10, space.Position.Y, 0.001)
+
+	ts.Update(1)
+	assert.Equal(t, engo.Point{X: 10, Y: 20}, space.Position)
+	assert.False(t, ts.Playing(seq))
+}
+
+func TestTweenSequenceYoyo(t *testing.T) {
+	value := float32(0)
+	seq := NewTweenSequence(
+		NewFloatTween(&value, 10, time.Second),
+		NewFloatTween(&value, 30, time.Second),
+	)
+	seq.Repeat = 1
+	seq.Yoyo = true
+
+	ts := &TweenSystem{}
+	ts.Play(seq)
+	ts.Update(2)
+	assert.InDelta(t, 30, value, 0.001)
+	ts.Update(0.5)
+	assert.InDelta(t, 20, value, 0.001)
+	ts.Update(1)
+	assert.InDelta(t, 5, value, 0.001, "playing backwards should return through the first Tween")
+	ts.Update(0.5)
+	assert.InDelta(t, 0, value, 0.001)
+}
+
+func TestTweenGroup(t *testing.T) {
+	space := &SpaceComponent{Width: 10, Height: 10}
+	render := &RenderComponent{Scale: engo.Point{X: 1, Y: 1}}
+	group := NewTweenGroup(
+		NewRotationTween(space, 90, time.Second),
+		NewSizeTween(space, 20, 30, 2*time.Second),
+		NewScaleTween(render, engo.Point{X: 2, Y: 3}, time.Second),
+		NewColorTween(render, color.NRGBA{R: 0, G: 0, B: 0, A: 0}, time.Second),
+	)
+	assert.Equal(t, 2*time.Second, group.Length())
+
+	ts := &TweenSystem{}
+	ts.Play(group)
+	ts.Update(1)
+	assert.InDelta(t, 90, space.Rotation, 0.001)
+	assert.InDelta(t, 15, space.Width, 0.001)
+	assert.InDelta(t, 20, space.Height, 0.001)
+	assert.Equal(t, engo.Point{X: 2, Y: 3}, render.Scale)
+	assert.Equal(t, color.NRGBA{}, render.Color)
+	assert.True(t, ts.Playing(group))
+
+	ts.Update(1)
+	assert.InDelta(t, 20, space.Width, 0.001)
+	assert.InDelta(t, 30, space.Height, 0.001)
+	assert.False(t, ts.Playing(group))
+}
+
+func TestTweenColor(t *testing.T) {
+	render := &RenderComponent{}
+	tw := NewColorTween(render, color.NRGBA{R: 0, G: 100, B: 255, A: 255}, time.Second)
+
+	ts := &TweenSystem{}
+	ts.Play(tw)
+	ts.Update(0.5)
+	assert.Equal(t, color.NRGBA{R: 128, G: 178, B: 255, A: 255}, render.Color, "a nil Color should start from white")
+}
+
+func TestTweenCompleteMessage(t *testing.T) {
+	engo.Mailbox = &engo.MessageManager{}
+
+	value := float32(0)
+	tw := NewFloatTween(&value, 1, time.Second)
+
+	ts := &TweenSystem{}
+	var completed []Tweener
+	engo.Mailbox.Listen("TweenCompleteMessage", func(msg engo.Message) {
+		completed = append(completed, msg.(TweenCompleteMessage).Tween)
+		// Playing it again from the handler should start over
+		if len(completed) == 1 {
+			ts.Play(tw)
+		}
+	})
+
+	ts.Play(tw)
+	ts.Update(0.5)
+	assert.Empty(t, completed)
+	ts.Update(0.5)
+	assert.Equal(t, []Tweener{tw}, completed)
+	assert.True(t, ts.Playing(tw))
+
+	ts.Update(1)
+	assert.Len(t, completed, 2)
+	assert.False(t, ts.Playing(tw))
+
+	// Stopped Tweeners don't complete
+	ts.Play(tw)
+	ts.StopAll()
+	ts.Update(1)
+	assert.Len(t, completed, 2)
+}
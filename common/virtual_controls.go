@@ -0,0 +1,526 @@
+package common
+
+import (
+	"image/color"
+	"sort"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+)
+
+// VirtualControlSystemPriority is the priority of the VirtualControlSystem. It
+// runs before the GestureSystem and the systems of the game, so they see the
+// state of the virtual controls in the same frame.
+const VirtualControlSystemPriority = 160
+
+// VirtualControlZIndex is the z-index at which the virtual controls are drawn,
+// on top of the rest of the HUD.
+const VirtualControlZIndex float32 = 1000
+
+var (
+	// DefaultVirtualControlColor is the color of the base of a VirtualJoystick
+	// and of a VirtualButton which isn't pressed.
+	DefaultVirtualControlColor = color.RGBA{R: 255, G: 255, B: 255, A: 64}
+	// DefaultVirtualControlActiveColor is the color of the knob of a
+	// VirtualJoystick and of a VirtualButton which is pressed.
+	DefaultVirtualControlActiveColor = color.RGBA{R: 255, G: 255, B: 255, A: 160}
+)
+
+// virtualShape is a circle which draws a part of a virtual control on the HUD
+type virtualShape struct {
+	ecs.BasicEntity
+	RenderComponent
+	SpaceComponent
+}
+
+func newVirtualShape(c color.Color) *virtualShape {
+	return &virtualShape{
+		BasicEntity:     ecs.NewBasic(),
+		RenderComponent: RenderComponent{Drawable: Circle{}, Color: c, StartZIndex: VirtualControlZIndex},
+	}
+}
+
+// place moves the circle so it is centered around the given position
+func (s *virtualShape) place(center engo.Point, radius float32) {
+	s.Position = engo.Point{X: center.X - radius, Y: center.Y - radius}
+	s.Width, s.Height = 2*radius, 2*radius
+}
+
+// VirtualJoystick is an on-screen joystick, which is moved by dragging its
+// knob. It feeds the Axes with the names Horizontal and Vertical, so it can
+// drive the same Axes as the keyboard or a Gamepad. All positions are in the
+// coordinates of the HUD, like the Touches of engo.Input.
+type VirtualJoystick struct {
+	// Center is the position of the center of the joystick
+	Center engo.Point
+	// Radius is how far the knob can be moved away from the center, which tilts
+	// the joystick all the way
+	Radius float32
+	// TouchRadius is how far away from the center a touch can begin to grab the
+	// joystick. It is 1.5 times the Radius when zero.
+	TouchRadius float32
+	// DeadZone is the part of the Radius, from 0 to 1, within which the
+	// joystick reports nothing
+	DeadZone float32
+	// Floating moves the joystick to where a touch begins, as long as it begins
+	// within the Area, and back to the Center once the touch is released.
+	Floating bool
+	Area     engo.AABB
+	// Horizontal and Vertical are the names of the Axes which are fed. Moving
+	// the knob right and down gives positive values, like the sticks of a
+	// Gamepad.
+	Horizontal, Vertical string
+	// Color and KnobColor are the colors of the base and the knob, which use
+	// the default colors when nil
+	Color, KnobColor color.Color
+
+	center     engo.Point
+	x, y       float32
+	held       bool
+	active     bool
+	bound      bool
+	updated    uint64
+	base, knob *virtualShape
+}
+
+// Value returns how far the joystick is tilted along both axes, from -1 to 1.
+func (j *VirtualJoystick) Value() (x, y float32) {
+	if !j.active || !readable(j.updated) {
+		return 0, 0
+	}
+	return j.x, j.y
+}
+
+// Held reports whether a touch is holding the joystick.
+func (j *VirtualJoystick) Held() bool {
+	return j.held
+}
+
+func (j *VirtualJoystick) touchRadius() float32 {
+	if j.TouchRadius > 0 {
+		return j.TouchRadius
+	}
+	return 1.5 * j.Radius
+}
+
+// grab lets the touch hold the joystick, if it began close enough to it
+func (j *VirtualJoystick) grab(p engo.Point) bool {
+	if j.held {
+		return false
+	}
+	switch {
+	case j.Floating && p.X >= j.Area.Min.X && p.X <= j.Area.Max.X && p.Y >= j.Area.Min.Y && p.Y <= j.Area.Max.Y:
+		j.center = p
+	case j.Center.PointDistance(p) <= j.touchRadius():
+		j.center = j.Center
+	default:
+		return false
+	}
+	j.held = true
+	j.move(p)
+	return true
+}
+
+// move tilts the joystick toward the position of the touch
+func (j *VirtualJoystick) move(p engo.Point) {
+	if j.Radius <= 0 {
+		return
+	}
+	x, y := (p.X-j.center.X)/j.Radius, (p.Y-j.center.Y)/j.Radius
+	filter := engo.AxisFilter{InnerDeadZone: j.DeadZone, Mode: engo.DeadZoneRadial}
+	j.x, j.y = filter.ApplyRadial(x, y, 0, 0)
+}
+
+func (j *VirtualJoystick) release() {
+	j.held = false
+	j.x, j.y = 0, 0
+	j.center = j.Center
+}
+
+// draw places the base and the knob, which is clamped to the edge of the base
+func (j *VirtualJoystick) draw() {
+	if j.base == nil {
+		j.base = newVirtualShape(virtualColor(j.Color, DefaultVirtualControlColor))
+		j.knob = newVirtualShape(virtualColor(j.KnobColor, DefaultVirtualControlActiveColor))
+		j.knob.StartZIndex++
+	}
+	if !j.held {
+		j.center = j.Center
+	}
+	j.base.place(j.center, j.Radius)
+	tip := engo.Point{X: j.center.X + j.x*j.Radius, Y: j.center.Y + j.y*j.Radius}
+	j.knob.place(tip, j.Radius/2)
+}
+
+// virtualAxis feeds one axis of a VirtualJoystick into an engo.Axis
+type virtualAxis struct {
+	joystick *VirtualJoystick
+	vertical bool
+}
+
+// Value returns the value of the axis of the joystick. It implements the
+// engo.AxisPair interface.
+func (a virtualAxis) Value() float32 {
+	x, y := a.joystick.Value()
+	if a.vertical {
+		return y
+	}
+	return x
+}
+
+// VirtualButton is an on-screen button, which is pressed by touching it. It
+// implements the engo.ButtonTrigger interface, and presses the engo.Button with
+// the name Button. All positions are in the coordinates of the HUD, like the
+// Touches of engo.Input.
+type VirtualButton struct {
+	// Center is the position of the center of the button
+	Center engo.Point
+	// Radius is the size of the button, within which a touch presses it
+	Radius float32
+	// Button is the name of the engo.Button which is pressed
+	Button string
+	// Color and PressedColor are the colors of the button, which use the
+	// default colors when nil
+	Color, PressedColor color.Color
+
+	held          bool
+	active        bool
+	bound         bool
+	updated       uint64
+	last, current bool
+	shape         *virtualShape
+}
+
+// JustPressed reports whether the button was pressed in the current frame. It
+// implements the engo.ButtonTrigger interface.
+func (b *VirtualButton) JustPressed() bool {
+	return b.active && readable(b.updated) && !b.last && b.current
+}
+
+// JustReleased reports whether the button was released in the current frame. It
+// implements the engo.ButtonTrigger interface.
+func (b *VirtualButton) JustReleased() bool {
+	return b.active && readable(b.updated) && b.last && !b.current
+}
+
+// Down reports whether the button is being held down. It implements the
+// engo.ButtonTrigger interface.
+func (b *VirtualButton) Down() bool {
+	return b.active && readable(b.updated) && b.last && b.current
+}
+
+// grab lets the touch press the button, if it began on it
+func (b *VirtualButton) grab(p engo.Point) bool {
+	if b.held || b.Center.PointDistance(p) > b.Radius {
+		return false
+	}
+	b.held = true
+	return true
+}
+
+func (b *VirtualButton) release() {
+	b.held = false
+}
+
+func (b *VirtualButton) draw() {
+	if b.shape == nil {
+		b.shape = newVirtualShape(nil)
+	}
+	b.shape.Color = virtualColor(b.Color, DefaultVirtualControlColor)
+	if b.current {
+		b.shape.Color = virtualColor(b.PressedColor, DefaultVirtualControlActiveColor)
+	}
+	b.shape.place(b.Center, b.Radius)
+}
+
+// currentFrame returns the number of frames engo has run
+func currentFrame() uint64 {
+	if engo.Time == nil {
+		return 0
+	}
+	return engo.Time.Frames()
+}
+
+// readable reports whether a control which was last updated in the given
+// frame can be read. It can't when the Scene being updated doesn't get to
+// see the input, or when its VirtualControlSystem wasn't updated this frame.
+func readable(updated uint64) bool {
+	if engo.Input != nil && engo.Input.Blocked() {
+		return false
+	}
+	return currentFrame() == updated
+}
+
+func virtualColor(c, fallback color.Color) color.Color {
+	if c == nil {
+		return fallback
+	}
+	return c
+}
+
+// virtualControl is a VirtualJoystick or a VirtualButton, which can be held by
+// a touch
+type virtualControl interface {
+	grab(p engo.Point) bool
+	release()
+}
+
+// VirtualControlSystem draws on-screen joysticks and buttons on the HUD, which
+// feed named Axes and Buttons of engo.Input using its Touches. Each touch is
+// owned by the control it began on until it is released. Add it after the
+// RenderSystem. The controls are released while the Scene doesn't see the
+// input.
+type VirtualControlSystem struct {
+	joysticks []*VirtualJoystick
+	buttons   []*VirtualButton
+	render    *RenderSystem
+	// owners holds the control which owns each touch, which is nil for touches
+	// that didn't begin on a control
+	owners map[int]virtualControl
+	// updated is the frame in which the system last saw the touches, and stale
+	// is set when it didn't see them since
+	updated uint64
+	stale   bool
+}
+
+// New finds the RenderSystem of the World, which draws the controls.
+func (v *VirtualControlSystem) New(w *ecs.World) {
+	for _, sys := range w.Systems() {
+		if render, ok := sys.(*RenderSystem); ok {
+			v.render = render
+		}
+	}
+	for _, j := range v.joysticks {
+		j.draw()
+		v.show(j.base, j.knob)
+	}
+	for _, b := range v.buttons {
+		b.draw()
+		v.show(b.shape)
+	}
+}
+
+// Priority implements the ecs.Prioritizer interface.
+func (*VirtualControlSystem) Priority() int { return VirtualControlSystemPriority }
+
+// AddJoystick adds the joystick, which starts feeding the Axes named Horizontal
+// and Vertical, next to the pairs they already have.
+func (v *VirtualControlSystem) AddJoystick(j *VirtualJoystick) {
+	for _, other := range v.joysticks {
+		if other == j {
+			return
+		}
+	}
+	if !j.bound && engo.Input != nil {
+		if j.Horizontal != "" {
+			engo.Input.AddAxisPairs(j.Horizontal, virtualAxis{joystick: j})
+		}
+		if j.Vertical != "" {
+			engo.Input.AddAxisPairs(j.Vertical, virtualAxis{joystick: j, vertical: true})
+		}
+		j.bound = true
+	}
+	j.active = true
+	j.updated = currentFrame()
+	j.release()
+	v.joysticks = append(v.joysticks, j)
+	j.draw()
+	v.show(j.base, j.knob)
+}
+
+// AddButton adds the button, which starts pressing the engo.Button named
+// Button, next to the triggers it already has.
+func (v *VirtualControlSystem) AddButton(b *VirtualButton) {
+	for _, other := range v.buttons {
+		if other == b {
+			return
+		}
+	}
+	if !b.bound && engo.Input != nil && b.Button != "" {
+		engo.Input.AddButtonTriggers(b.Button, b)
+		b.bound = true
+	}
+	b.active = true
+	b.updated = currentFrame()
+	b.release()
+	v.buttons = append(v.buttons, b)
+	b.draw()
+	v.show(b.shape)
+}
+
+// RemoveJoystick removes the joystick, which stops drawing it and lets its Axes
+// report nothing.
+func (v *VirtualControlSystem) RemoveJoystick(j *VirtualJoystick) {
+	for i, other := range v.joysticks {
+		if other == j {
+			v.joysticks = append(v.joysticks[:i], v.joysticks[i+1:]...)
+			v.disown(j)
+			j.release()
+			j.active = false
+			v.hide(j.base, j.knob)
+			return
+		}
+	}
+}
+
+// RemoveButton removes the button, which stops drawing it and releases its
+// engo.Button.
+func (v *VirtualControlSystem) RemoveButton(b *VirtualButton) {
+	for i, other := range v.buttons {
+		if other == b {
+			v.buttons = append(v.buttons[:i], v.buttons[i+1:]...)
+			v.disown(b)
+			b.release()
+			b.active, b.last, b.current = false, false, false
+			v.hide(b.shape)
+			return
+		}
+	}
+}
+
+// Remove does nothing, because the controls are removed using RemoveJoystick
+// and RemoveButton. It implements the ecs.System interface.
+func (*VirtualControlSystem) Remove(ecs.BasicEntity) {}
+
+// Owns reports whether the touch with the given id is owned by one of the
+// controls, so other systems can ignore it.
+func (v *VirtualControlSystem) Owns(id int) bool {
+	return v.owners[id] != nil
+}
+
+// Update lets the touches which just began grab the control they began on,
+// moves the controls using the touches which own them, and releases the
+// controls of which the touch was released.
+func (v *VirtualControlSystem) Update(float32) {
+	if v.owners == nil {
+		v.owners = make(map[int]virtualControl)
+	}
+
+	if engo.Input.Blocked() {
+		// The Scene doesn't see the touches, e.g. below an Overlay
+		v.releaseAll(nil)
+		v.stale = true
+		v.draw()
+		return
+	}
+
+	current := engo.Input.Touches
+	frame := currentFrame()
+	if v.stale || frame > v.updated+1 {
+		// The touches which held the controls before are stale
+		v.releaseAll(current)
+		v.stale = false
+	}
+	v.updated = frame
+
+	for id, owner := range v.owners {
+		if _, ok := current[id]; !ok {
+			if owner != nil {
+				owner.release()
+			}
+			delete(v.owners, id)
+		}
+	}
+
+	ids := make([]int, 0, len(current))
+	for id := range current {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		position := current[id]
+		owner, ok := v.owners[id]
+		if !ok {
+			v.owners[id] = v.grab(position)
+			continue
+		}
+		if j, isJoystick := owner.(*VirtualJoystick); isJoystick {
+			j.move(position)
+		}
+	}
+
+	for _, b := range v.buttons {
+		b.last, b.current = b.current, b.held
+		b.updated = frame
+	}
+	for _, j := range v.joysticks {
+		j.updated = frame
+	}
+	v.draw()
+}
+
+// draw places the shapes of all controls
+func (v *VirtualControlSystem) draw() {
+	for _, b := range v.buttons {
+		b.draw()
+	}
+	for _, j := range v.joysticks {
+		j.draw()
+	}
+}
+
+// releaseAll releases all controls, and keeps the touches which are down from
+// grabbing anything until they are released
+func (v *VirtualControlSystem) releaseAll(touches map[int]engo.Point) {
+	for id := range v.owners {
+		delete(v.owners, id)
+	}
+	for id := range touches {
+		v.owners[id] = nil
+	}
+	for _, b := range v.buttons {
+		b.release()
+		b.last, b.current = false, false
+	}
+	for _, j := range v.joysticks {
+		j.release()
+	}
+}
+
+// grab returns the control the touch began on, the buttons being tried before
+// the joysticks, or nil if there is none
+func (v *VirtualControlSystem) grab(p engo.Point) virtualControl {
+	for _, b := range v.buttons {
+		if b.grab(p) {
+			return b
+		}
+	}
+	for _, j := range v.joysticks {
+		if j.grab(p) {
+			return j
+		}
+	}
+	return nil
+}
+
+// disown forgets the touch which owns the control, so it doesn't grab anything
+// else until it is released
+func (v *VirtualControlSystem) disown(c virtualControl) {
+	for id, owner := range v.owners {
+		if owner == c {
+			v.owners[id] = nil
+		}
+	}
+}
+
+// show adds the shapes to the RenderSystem, if there is one
+func (v *VirtualControlSystem) show(shapes ...*virtualShape) {
+	if v.render == nil {
+		return
+	}
+	for _, s := range shapes {
+		s.SetShader(HUDShader)
+		v.render.Add(&s.BasicEntity, &s.RenderComponent, &s.SpaceComponent)
+	}
+}
+
+// hide removes the shapes from the RenderSystem, if there is one
+func (v *VirtualControlSystem) hide(shapes ...*virtualShape) {
+	if v.render == nil {
+		return
+	}
+	for _, s := range shapes {
+		v.render.Remove(s.BasicEntity)
+	}
+}
@@ -0,0 +1,197 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupVirtualControlTest(t *testing.T) (*VirtualControlSystem, *VirtualJoystick, *VirtualButton) {
+	input := engo.Input
+	t.Cleanup(func() { engo.Input = input })
+	engo.Input = engo.NewInputManager()
+	engo.Input.RegisterAxis("horizontal", engo.AxisKeyPair{Min: engo.KeyA, Max: engo.KeyD})
+	engo.Input.RegisterButton("jump", engo.KeySpace)
+
+	v := &VirtualControlSystem{}
+	stick := &VirtualJoystick{
+		Center:     engo.Point{X: 100, Y: 400},
+		Radius:     50,
+		DeadZone:   0.1,
+		Horizontal: "horizontal",
+		Vertical:   "vertical",
+	}
+	jump := &VirtualButton{Center: engo.Point{X: 700, Y: 400}, Radius: 40, Button: "jump"}
+	v.AddJoystick(stick)
+	v.AddButton(jump)
+	return v, stick, jump
+}
+
+// virtualFrame replaces the touches and updates the VirtualControlSystem
+func virtualFrame(v *VirtualControlSystem, touches map[int]engo.Point) {
+	engo.Input.Touches = touches
+	v.Update(1.0 / 60)
+}
+
+func TestVirtualJoystick(t *testing.T) {
+	v, stick, _ := setupVirtualControlTest(t)
+
+	virtualFrame(v, map[int]engo.Point{1: {X: 125, Y: 400}})
+	assert.True(t, v.Owns(1), "touch beginning on the joystick should have been owned by it")
+	assert.InDelta(t, 0.4444, engo.Input.Axis("horizontal").Value(), 0.001, "half the radius minus the dead zone")
+	assert.Equal(t, float32(0), engo.Input.Axis("vertical").Value())
+
+	// The knob is clamped to the edge of the base
+	virtualFrame(v, map[int]engo.Point{1: {X: 100, Y: 600}})
+	x, y := stick.Value()
+	assert.Equal(t, float32(0), x)
+	assert.Equal(t, float32(1), y)
+	assert.Equal(t, float32(1), engo.Input.Axis("vertical").Value())
+	assert.Equal(t, engo.Point{X: 75, Y: 425}, stick.knob.Position, "knob should have been drawn at the edge")
+
+	virtualFrame(v, map[int]engo.Point{})
+	assert.False(t, stick.Held())
+	assert.False(t, v.Owns(1))
+	assert.Equal(t, float32(0), engo.Input.Axis("vertical").Value(), "releasing should have centered the joystick")
+
+	assert.Len(t, engo.Input.Axis("horizontal").Pairs, 2, "joystick should have been added next to the keys")
+
+	v.RemoveJoystick(stick)
+	virtualFrame(v, map[int]engo.Point{2: {X: 140, Y: 400}})
+	assert.False(t, v.Owns(2), "removed joystick shouldn't have owned the touch")
+	assert.Equal(t, float32(0), engo.Input.Axis("horizontal").Value())
+}
+
+func TestVirtualJoystickFloating(t *testing.T) {
+	v, stick, _ := setupVirtualControlTest(t)
+	stick.Floating = true
+	stick.Area = engo.AABB{Min: engo.Point{X: 0, Y: 200}, Max: engo.Point{X: 300, Y: 600}}
+
+	virtualFrame(v, map[int]engo.Point{1: {X: 250, Y: 250}})
+	assert.True(t, v.Owns(1), "touch within the Area should have grabbed the floating joystick")
+	assert.Equal(t, engo.Point{X: 200, Y: 200}, stick.base.Position, "joystick should have moved to the touch")
+	virtualFrame(v, map[int]engo.Point{1: {X: 275, Y: 250}})
+	assert.InDelta(t, 0.4444, engo.Input.Axis("horizontal").Value(), 0.001)
+
+	virtualFrame(v, map[int]engo.Point{})
+	assert.Equal(t, engo.Point{X: 50, Y: 350}, stick.base.Position, "joystick should have moved back to its Center")
+}
+
+func TestVirtualButton(t *testing.T) {
+	v, stick, jump := setupVirtualControlTest(t)
+	button := engo.Input.Button("jump")
+
+	// A touch which began elsewhere doesn't press the button when sliding onto
+	// it
+	virtualFrame(v, map[int]engo.Point{1: {X: 400, Y: 400}})
+	virtualFrame(v, map[int]engo.Point{1: {X: 700, Y: 400}})
+	assert.False(t, v.Owns(1))
+	assert.False(t, button.JustPressed())
+
+	virtualFrame(v, map[int]engo.Point{1: {X: 700, Y: 400}, 2: {X: 710, Y: 410}, 3: {X: 100, Y: 400}})
+	assert.True(t, button.JustPressed())
+	assert.True(t, stick.Held(), "joystick should have been held by another touch at the same time")
+	assert.Equal(t, DefaultVirtualControlActiveColor, jump.shape.Color)
+
+	// Sliding off the button keeps it pressed until the touch is released
+	virtualFrame(v, map[int]engo.Point{2: {X: 500, Y: 100}})
+	assert.True(t, button.Down())
+	virtualFrame(v, map[int]engo.Point{})
+	assert.True(t, button.JustReleased())
+	virtualFrame(v, map[int]engo.Point{})
+	assert.False(t, button.Down())
+
+	assert.Len(t, engo.Input.Button("jump").Extra, 1, "button should have been added next to the keys")
+
+	v.RemoveButton(jump)
+	virtualFrame(v, map[int]engo.Point{4: {X: 700, Y: 400}})
+	assert.False(t, button.JustPressed(), "removed button shouldn't have been pressed")
+}
+
+type virtualControlScene struct {
+	name        string
+	system      *VirtualControlSystem
+	updateBelow bool
+	renderBelow bool
+	pressed     []bool
+}
+
+func (*virtualControlScene) Preload() {}
+
+func (s *virtualControlScene) Setup(u engo.Updater) {
+	w := u.(*ecs.World)
+	if s.system != nil {
+		w.AddSystem(s.system)
+	}
+	w.AddSystem(&virtualControlProbe{scene: s})
+}
+
+func (s *virtualControlScene) Type() string { return s.name }
+
+type virtualControlOverlay struct {
+	*virtualControlScene
+}
+
+func (o virtualControlOverlay) UpdateBelow() bool { return o.updateBelow }
+
+func (o virtualControlOverlay) RenderBelow() bool { return o.renderBelow }
+
+// virtualControlProbe records whether the Scene sees the jump Button pressed
+type virtualControlProbe struct {
+	scene *virtualControlScene
+}
+
+func (*virtualControlProbe) Remove(ecs.BasicEntity) {}
+
+func (p *virtualControlProbe) Update(float32) {
+	jump := engo.Input.Button("jump")
+	p.scene.pressed = append(p.scene.pressed, jump.JustPressed() || jump.Down())
+}
+
+func TestVirtualControlsBelowOverlay(t *testing.T) {
+	input := engo.Input
+	t.Cleanup(func() { engo.Input = input })
+
+	// The Scene below an Overlay doesn't see the controls of the Overlay
+	game := &virtualControlScene{name: "virtualControlsGame"}
+	menu := virtualControlOverlay{&virtualControlScene{
+		name:        "virtualControlsMenu",
+		system:      &VirtualControlSystem{},
+		updateBelow: true,
+		renderBelow: true,
+	}}
+	engo.Run(engo.RunOptions{NoRun: true, HeadlessMode: true}, game)
+	engo.Input.RegisterButton("jump")
+	assert.NoError(t, engo.PushScene(menu, true))
+	menu.system.AddButton(&VirtualButton{Center: engo.Point{X: 100, Y: 100}, Radius: 40, Button: "jump"})
+	engo.Input.Touches = map[int]engo.Point{1: {X: 100, Y: 100}}
+	assert.NoError(t, engo.Step(3, time.Second/60))
+	assert.Equal(t, []bool{true, true}, menu.pressed[1:], "Overlay should have seen its button")
+	assert.Equal(t, []bool{false, false, false}, game.pressed, "Scene below should not have seen the button")
+
+	// The controls of a Scene below an Overlay are released, whether it is
+	// drawn or not
+	for _, renderBelow := range []bool{false, true} {
+		name := fmt.Sprintf("virtualControlsFrozen%v", renderBelow)
+		game = &virtualControlScene{name: name + "Game", system: &VirtualControlSystem{}}
+		menu = virtualControlOverlay{&virtualControlScene{name: name + "Menu", renderBelow: renderBelow}}
+		engo.Run(engo.RunOptions{NoRun: true, HeadlessMode: true}, game)
+		engo.Input.RegisterButton("jump")
+		game.system.AddButton(&VirtualButton{Center: engo.Point{X: 100, Y: 100}, Radius: 40, Button: "jump"})
+		engo.Input.Touches = map[int]engo.Point{1: {X: 100, Y: 100}}
+		assert.NoError(t, engo.Step(2, time.Second/60))
+		assert.True(t, game.pressed[1], "button should have been pressed")
+
+		assert.NoError(t, engo.PushScene(menu, true))
+		assert.NoError(t, engo.Step(2, time.Second/60))
+		assert.Equal(t, []bool{false, false}, menu.pressed, "button of the Scene below should have been released")
+
+		assert.NoError(t, engo.PopScene())
+		assert.NoError(t, engo.Step(1, time.Second/60))
+		assert.False(t, game.pressed[len(game.pressed)-1], "touch from before the Overlay should not press the button")
+	}
+}
@@ -1,9 +1,11 @@
 package engo
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/EngoEngine/ecs"
 )
@@ -108,6 +110,15 @@ type RunOptions struct {
 	// FPSLimit indicates the maximum number of frames per second
 	FPSLimit int
 
+	// FixedTickRate is the number of fixed-step ticks per second. When set, Systems implementing FixedUpdater are
+	// updated at this rate regardless of the frame rate, which keeps physics and collisions deterministic. Leaving
+	// it at zero disables fixed-step updates.
+	FixedTickRate int
+
+	// MaxFixedSteps is the maximum number of fixed-step ticks run in a single frame. Whenever more ticks are needed
+	// to catch up, the remaining time is dropped and the game slows down instead. Defaults to 5.
+	MaxFixedSteps int
+
 	// OverrideCloseAction indicates that (when true) engo will never close whenever the gamer wants to close the
 	// game - that will be your responsibility
 	OverrideCloseAction bool
@@ -181,7 +192,17 @@ func Run(o RunOptions, defaultScene Scene) {
 		o.GlobalScale = Point{X: 1, Y: 1}
 	}
 
+	if o.FixedTickRate < 0 {
+		panic("FixedTickRate has to be greater or equal to 0")
+	}
+
+	if o.MaxFixedSteps <= 0 {
+		o.MaxFixedSteps = defaultMaxFixedSteps
+	}
+
 	opts = o
+	resetFixedSteps()
+	stepTime = nil
 
 	// Create input
 	Input = NewInputManager()
@@ -300,6 +321,44 @@ func runHeadless(defaultScene Scene) {
 	runLoop(defaultScene, true)
 }
 
+// stepTime is the time of the Clock used by Step
+var stepTime *ManualTime
+
+// Step runs n frames of the current Scene, as if exactly dt had passed between each of them, without waiting for
+// any actual time to pass. It can only be used in HeadlessMode, and is meant for tests: call `engo.Run` with
+// `NoRun` set to true, and then Step through the game to assert on the state of the World after exactly n frames.
+// The first call replaces Time with a Clock driven by a ManualTime, so Time.Delta() always returns dt.
+func Step(n int, dt time.Duration) error {
+	if !opts.HeadlessMode {
+		return errors.New("unable to step outside of HeadlessMode")
+	}
+	if n < 0 || dt < 0 {
+		return fmt.Errorf("unable to step %d frames of %v, both have to be >= 0", n, dt)
+	}
+	if currentScene == nil {
+		return errors.New("unable to step without a scene, call engo.Run first")
+	}
+
+	if stepTime == nil || Time == nil || Time.source != stepTime {
+		stepTime = &ManualTime{}
+		Time = NewClockWithSource(stepTime)
+	}
+
+	for i := 0; i < n; i++ {
+		stepTime.Advance(dt)
+		Time.Tick()
+		Input.update()
+
+		updateScenes(Time.Delta(), Time.UnscaledDelta())
+
+		// reset values to avoid catching the same "signal" twice
+		Input.Mouse.ScrollX, Input.Mouse.ScrollY = 0, 0
+		Input.Mouse.Action = Neutral
+	}
+
+	return nil
+}
+
 // GetGlobalScale returns the GlobalScale factor set in the RunOptions or via
 // SetGlobalScale()
 func GetGlobalScale() Point {
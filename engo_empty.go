@@ -44,7 +44,7 @@ func SetTitle(title string) {
 // RunIteration runs one iteration per frame
 func RunIteration() {
 	Time.Tick()
-	currentUpdater.Update(Time.Delta())
+	updateScenes(Time.Delta(), Time.UnscaledDelta())
 }
 
 // RunPreparation is called automatically when calling Open. It should only be called once.
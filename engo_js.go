@@ -153,7 +153,9 @@ func CreateWindow(title string, width, height int, fullscreen bool, msaa int) {
 		mmX, mmY := event.Get("clientX").Int(), event.Get("clientY").Int()
 		Input.Mouse.X = float32(mmX) / opts.GlobalScale.X
 		Input.Mouse.Y = float32(mmY) / opts.GlobalScale.Y
+		Input.Mouse.Button = jsMouseButton(event.Get("button").Int())
 		Input.Mouse.Action = Press
+		Input.mouseButtons.Set(Input.Mouse.Button, true)
 		return nil
 	}))
 
@@ -162,7 +164,9 @@ func CreateWindow(title string, width, height int, fullscreen bool, msaa int) {
 		mmX, mmY := event.Get("clientX").Int(), event.Get("clientY").Int()
 		Input.Mouse.X = float32(mmX) / opts.GlobalScale.X
 		Input.Mouse.Y = float32(mmY) / opts.GlobalScale.Y
+		Input.Mouse.Button = jsMouseButton(event.Get("button").Int())
 		Input.Mouse.Action = Release
+		Input.mouseButtons.Set(Input.Mouse.Button, false)
 		return nil
 	}))
 
@@ -299,7 +303,7 @@ func RunIteration() {
 	Time.Tick()
 	Input.update()
 	jsPollKeys()
-	currentUpdater.Update(Time.Delta())
+	updateScenes(Time.Delta(), Time.UnscaledDelta())
 	Input.Mouse.Action = Neutral
 	// TODO: this may not work, and sky-rocket the FPS
 	//  requestAnimationFrame(func(dt float32) {
@@ -330,6 +334,18 @@ func jsPollKeys() {
 
 }
 
+// jsMouseButton returns the MouseButton of the button property of a mouse
+// event, which numbers the middle and the right button the other way around
+func jsMouseButton(button int) MouseButton {
+	switch button {
+	case 1:
+		return MouseButtonMiddle
+	case 2:
+		return MouseButtonRight
+	}
+	return MouseButton(button)
+}
+
 func checkModifiers(event js.Value) {
 	mod = 0
 	for str, modifier := range jsStrToMod {
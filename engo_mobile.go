@@ -148,6 +148,7 @@ func runLoop(defaultScene Scene, headless bool) {
 				switch e.Type {
 				case touch.TypeBegin:
 					Input.Mouse.Action = Press
+					Input.mouseButtons.Set(MouseButtonLeft, true)
 					Input.Touches[id] = Point{
 						X: float32(e.X) / opts.GlobalScale.X,
 						Y: float32(e.Y) / opts.GlobalScale.Y,
@@ -161,6 +162,11 @@ func runLoop(defaultScene Scene, headless bool) {
 				case touch.TypeEnd:
 					Input.Mouse.Action = Release
 					delete(Input.Touches, id)
+					// the left button stays down while other fingers touch the
+					// screen
+					if len(Input.Touches) == 0 {
+						Input.mouseButtons.Set(MouseButtonLeft, false)
+					}
 				}
 			}
 		}
@@ -183,7 +189,7 @@ func RunIteration() {
 	}
 
 	// Then update the world and all Systems
-	currentUpdater.Update(Time.Delta())
+	updateScenes(Time.Delta(), Time.UnscaledDelta())
 }
 
 // SetCursor changes the cursor - not yet implemented
@@ -164,7 +164,7 @@ func mobileDraw(defaultScene Scene) {
 		Input.update()
 	}
 	// Then update the world and all Systems
-	currentUpdater.Update(Time.Delta())
+	updateScenes(Time.Delta(), Time.UnscaledDelta())
 	Input.Mouse.Action = Neutral
 }
 
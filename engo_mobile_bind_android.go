@@ -9,6 +9,7 @@ func TouchEvent(x, y, id, action int) {
 	switch action {
 	case 0, 5:
 		Input.Mouse.Action = Press
+		Input.mouseButtons.Set(MouseButtonLeft, true)
 		Input.Touches[id] = Point{
 			X: float32(x) / opts.GlobalScale.X,
 			Y: float32(y) / opts.GlobalScale.Y,
@@ -16,6 +17,10 @@ func TouchEvent(x, y, id, action int) {
 	case 1, 6:
 		Input.Mouse.Action = Release
 		delete(Input.Touches, id)
+		// the left button stays down while other fingers touch the screen
+		if len(Input.Touches) == 0 {
+			Input.mouseButtons.Set(MouseButtonLeft, false)
+		}
 	case 2:
 		Input.Mouse.Action = Move
 		Input.Touches[id] = Point{
@@ -16,6 +16,7 @@ func TouchEvent(x, y, id, action int) {
 	switch action {
 	case C.UITouchPhaseBegan, C.UITouchPhaseStationary:
 		Input.Mouse.Action = Press
+		Input.mouseButtons.Set(MouseButtonLeft, true)
 		Input.Touches[id] = Point{
 			X: float32(x) / opts.GlobalScale.X,
 			Y: float32(y) / opts.GlobalScale.Y,
@@ -23,6 +24,10 @@ func TouchEvent(x, y, id, action int) {
 	case C.UITouchPhaseEnded, C.UITouchPhaseCancelled:
 		Input.Mouse.Action = Release
 		delete(Input.Touches, id)
+		// the left button stays down while other fingers touch the screen
+		if len(Input.Touches) == 0 {
+			Input.mouseButtons.Set(MouseButtonLeft, false)
+		}
 	case C.UITouchPhaseMoved:
 		Input.Mouse.Action = Move
 		Input.Touches[id] = Point{
@@ -179,6 +179,7 @@ func RunIteration() {
 				} else {
 					Input.Mouse.Action = Release
 				}
+				Input.mouseButtons.Set(Input.Mouse.Button, e.State == sdl.PRESSED)
 			case *sdl.MouseMotionEvent:
 				Input.Mouse.X, Input.Mouse.Y = float32(e.X)/opts.GlobalScale.X, float32(e.Y)/opts.GlobalScale.Y
 				if Input.Mouse.Action != Release && Input.Mouse.Action != Press {
@@ -228,7 +229,7 @@ func RunIteration() {
 	}
 
 	// Then update the world and all Systems
-	currentUpdater.Update(Time.Delta())
+	updateScenes(Time.Delta(), Time.UnscaledDelta())
 
 	// Lastly, forget keypresses and swap buffers
 	if !opts.HeadlessMode {
@@ -3,6 +3,7 @@ package engo
 import (
 	"bytes"
 	"log"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -349,3 +350,243 @@ func TestGetApplicationVersion(t *testing.T) {
 		t.Errorf("Application version did not match. Wanted: %v.%v.%v \n Got: %v.%v.%v\n", 1, 2, 3, ver[0], ver[1], ver[2])
 	}
 }
+
+type testStackScene struct {
+	name    string
+	updates []float32
+	input   []bool
+	visible []bool
+	overlay []bool
+	mailbox *MessageManager
+	hidden  int
+
+	updateBelow, renderBelow, isOverlay bool
+}
+
+func (*testStackScene) Preload() {}
+
+func (t *testStackScene) Setup(u Updater) {
+	t.mailbox = Mailbox
+	w, _ := u.(*ecs.World)
+	w.AddSystem(&testStackSystem{scene: t})
+}
+
+func (t *testStackScene) Type() string { return t.name }
+
+func (t *testStackScene) Hide() { t.hidden++ }
+
+type testStackOverlay struct {
+	*testStackScene
+}
+
+func (t testStackOverlay) UpdateBelow() bool { return t.updateBelow }
+
+func (t testStackOverlay) RenderBelow() bool { return t.renderBelow }
+
+type testStackSystem struct {
+	scene *testStackScene
+}
+
+func (*testStackSystem) Remove(ecs.BasicEntity) {}
+
+func (t *testStackSystem) Update(dt float32) {
+	t.scene.updates = append(t.scene.updates, dt)
+	t.scene.input = append(t.scene.input, Input.Button("jump").Down())
+	t.scene.visible = append(t.scene.visible, SceneVisible())
+	t.scene.overlay = append(t.scene.overlay, DrawingOverlay())
+	if Mailbox != t.scene.mailbox {
+		panic("scene was updated with the wrong mailbox")
+	}
+}
+
+// resetScenes forgets about all registered Scenes, so a test can set up its
+// Scenes again when it is run more than once
+func resetScenes() {
+	sceneMutex.Lock()
+	scenes = make(map[string]*sceneWrapper)
+	sceneMutex.Unlock()
+}
+
+func TestPushPopScene(t *testing.T) {
+	data := []struct {
+		updateBelow, renderBelow bool
+		expUpdates               []float32
+		expVisible               []bool
+	}{
+		{false, false, nil, nil},
+		{false, true, []float32{0}, []bool{true}},
+		{true, false, []float32{0.5}, []bool{false}},
+		{true, true, []float32{0.5}, []bool{true}},
+	}
+
+	for _, d := range data {
+		game := &testStackScene{name: "testStackGame"}
+		menu := testStackOverlay{&testStackScene{
+			name:        "testStackMenu",
+			updateBelow: d.updateBelow,
+			renderBelow: d.renderBelow,
+		}}
+		Run(RunOptions{
+			NoRun:        true,
+			HeadlessMode: true,
+		}, game)
+		Input.RegisterButton("jump", KeySpace)
+		Input.keys.Set(KeySpace, true)
+		Input.keys.Set(KeySpace, true)
+
+		if err := PushScene(menu, true); err != nil {
+			t.Fatalf("Unable to push scene: %v", err)
+		}
+		if CurrentScene() != menu {
+			t.Errorf("CurrentScene was not the pushed scene, was: %v", CurrentScene().Type())
+		}
+		if Mailbox != menu.mailbox {
+			t.Error("Mailbox was not set to the pushed scene's mailbox")
+		}
+
+		updateScenes(0.5, 0.5)
+
+		if !reflect.DeepEqual(game.updates, d.expUpdates) {
+			t.Errorf("Scene below was not updated properly. Wanted: %v, got: %v", d.expUpdates, game.updates)
+		}
+		if !reflect.DeepEqual(game.visible, d.expVisible) {
+			t.Errorf("Scene below had the wrong visibility. Wanted: %v, got: %v", d.expVisible, game.visible)
+		}
+		for _, down := range game.input {
+			if down {
+				t.Error("Scene below received input from the scene on top")
+			}
+		}
+		if len(menu.updates) != 1 || menu.updates[0] != 0.5 {
+			t.Errorf("Scene on top was not updated properly, got: %v", menu.updates)
+		}
+		if !menu.input[0] {
+			t.Error("Scene on top did not receive input")
+		}
+		if menu.overlay[0] != d.renderBelow {
+			t.Errorf("Scene on top should be drawn as an overlay: %v, was: %v", d.renderBelow, menu.overlay[0])
+		}
+		if Mailbox != menu.mailbox {
+			t.Error("Mailbox was not set back to the top scene's mailbox after updating")
+		}
+
+		if err := PopScene(); err != nil {
+			t.Fatalf("Unable to pop scene: %v", err)
+		}
+		if CurrentScene() != game {
+			t.Errorf("CurrentScene was not set back after popping, was: %v", CurrentScene().Type())
+		}
+		if menu.hidden != 1 {
+			t.Error("Popped scene was not hidden")
+		}
+		if game.hidden != 0 {
+			t.Error("Scene below was hidden while a scene was pushed and popped")
+		}
+		if Mailbox != game.mailbox {
+			t.Error("Mailbox was not set back after popping")
+		}
+
+		updateScenes(0.25, 0.25)
+		if game.updates[len(game.updates)-1] != 0.25 || !game.input[len(game.input)-1] {
+			t.Error("Scene was not updated properly after the scene on top was popped")
+		}
+	}
+}
+
+func TestBlockedInput(t *testing.T) {
+	im := NewInputManager()
+	im.gamepads.gamepads["player1"] = &Gamepad{}
+	blocked := im.blocked()
+	if im.blocked() != blocked {
+		t.Error("Blocked InputManager should be reused")
+	}
+	if blocked.Gamepad("player1") == nil {
+		t.Error("Blocked InputManager should have the same gamepads")
+	}
+
+	delete(im.gamepads.gamepads, "player1")
+	im.gamepads.gamepads["player2"] = &Gamepad{}
+	im.gamepads.gamepads["player2"].A.set(true)
+	if blocked = im.blocked(); blocked.Gamepad("player1") != nil || blocked.Gamepad("player2") == nil {
+		t.Error("Gamepads of the blocked InputManager were not refreshed")
+	}
+	if blocked.Gamepad("player2").A.JustPressed() {
+		t.Error("Gamepads of the blocked InputManager should not be pressed")
+	}
+}
+
+func TestPushSceneErrors(t *testing.T) {
+	resetScenes()
+	game := &testStackScene{name: "testStackErrorGame"}
+	Run(RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, game)
+
+	if err := PopScene(); err == nil {
+		t.Error("No error when popping the only scene on the stack")
+	}
+	if err := PushScene(game, false); err == nil {
+		t.Error("No error when pushing a scene that is already on the stack")
+	}
+	if err := PushSceneByName("doesNotExistScene", false); err == nil {
+		t.Error("No error when pushing a scene that doesn't exist")
+	}
+
+	menu := &testStackScene{name: "testStackErrorMenu"}
+	RegisterScene(menu)
+	if err := PushSceneByName("testStackErrorMenu", false); err != nil {
+		t.Errorf("Unable to push registered scene: %v", err)
+	}
+
+	SetScene(game, false)
+	if menu.hidden != 1 || game.hidden != 1 {
+		t.Error("SetScene did not hide all of the scenes on the stack")
+	}
+	if err := PopScene(); err == nil {
+		t.Error("SetScene did not clear the stack")
+	}
+}
+
+func TestStep(t *testing.T) {
+	resetScenes()
+
+	scene := &testStackScene{name: "testStep"}
+	Run(RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, scene)
+	Input.RegisterButton("jump", KeySpace)
+
+	if err := Step(3, 20*time.Millisecond); err != nil {
+		t.Fatalf("Unable to step: %v", err)
+	}
+	if !reflect.DeepEqual(scene.updates, []float32{0.02, 0.02, 0.02}) {
+		t.Errorf("Scene was not stepped 3 frames of 0.02 seconds, got: %v", scene.updates)
+	}
+	if Time.Frames() != 3 || Time.Time() != 0.06 {
+		t.Errorf("Time did not advance by 3 frames of 0.02 seconds, got %v frames and %v seconds", Time.Frames(), Time.Time())
+	}
+
+	Input.keys.Set(KeySpace, true)
+	if err := Step(2, 20*time.Millisecond); err != nil {
+		t.Fatalf("Unable to step: %v", err)
+	}
+	if !reflect.DeepEqual(scene.input, []bool{false, false, false, true, true}) {
+		t.Errorf("Input was not updated while stepping, got: %v", scene.input)
+	}
+	if Time.Frames() != 5 {
+		t.Errorf("Time was reset while stepping again, got %v frames", Time.Frames())
+	}
+
+	if err := Step(-1, time.Second); err == nil {
+		t.Error("Stepping a negative amount of frames did not fail")
+	}
+
+	opts.HeadlessMode = false
+	err := Step(1, time.Second)
+	opts.HeadlessMode = true
+	if err == nil {
+		t.Error("Stepping outside of HeadlessMode did not fail")
+	}
+}
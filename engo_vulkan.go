@@ -153,6 +153,7 @@ func CreateWindow(title string, width, height int, fullscreen bool, msaa int) {
 		} else {
 			Input.Mouse.Action = Release
 		}
+		Input.mouseButtons.Set(Input.Mouse.Button, a == glfw.Press)
 	})
 
 	Window.SetScrollCallback(func(Window *glfw.Window, xoff, yoff float64) {
@@ -225,7 +226,7 @@ func RunIteration() {
 	}
 
 	// Then update the world and all Systems
-	currentUpdater.Update(Time.Delta())
+	updateScenes(Time.Delta(), Time.UnscaledDelta())
 
 	// Lastly, forget keypresses and swap buffers
 	if !opts.HeadlessMode {
@@ -0,0 +1,139 @@
+package engo
+
+import (
+	"fmt"
+
+	"github.com/EngoEngine/ecs"
+)
+
+// defaultMaxFixedSteps is the maximum number of fixed-step ticks per frame,
+// used when RunOptions.MaxFixedSteps isn't set
+const defaultMaxFixedSteps = 5
+
+// FixedUpdater is an optional interface an Updater, or a System within an
+// *ecs.World, can implement to be updated at RunOptions.FixedTickRate. Each
+// frame, FixedUpdate is called as many times as needed to catch up, before the
+// regular Update of the Scene.
+type FixedUpdater interface {
+	FixedUpdate(dt float32)
+}
+
+// fixedStep keeps track of the time that has to be simulated by fixed-step
+// ticks. Each Scene has its own, as they can run at different time scales.
+type fixedStep struct {
+	// accumulator is the time that has passed, but was not yet simulated by a
+	// fixed-step tick
+	accumulator float32
+	// alpha is how far along the current frame is between the last tick and the
+	// next one
+	alpha float32
+}
+
+var (
+	// defaultFixedStep is used when running the Updater without any Scenes on
+	// the stack
+	defaultFixedStep = &fixedStep{}
+	// interpolationAlpha is the alpha of the Scene that is being updated
+	interpolationAlpha float32 = 1
+)
+
+// SetFixedTickRate can be used to change the value in the given `RunOpts` after
+// already having called `engo.Run`. Setting it to zero disables fixed-step
+// updates.
+func SetFixedTickRate(rate int) error {
+	if rate < 0 {
+		return fmt.Errorf("fixed tick rate out of bounds. Requires >= 0")
+	}
+	opts.FixedTickRate = rate
+	resetFixedSteps()
+	return nil
+}
+
+// resetFixedSteps forgets about the time that was not yet simulated by any of
+// the Scenes
+func resetFixedSteps() {
+	sceneMutex.RLock()
+	for _, wrapper := range scenes {
+		wrapper.fixed = fixedStep{}
+	}
+	sceneMutex.RUnlock()
+
+	*defaultFixedStep = fixedStep{}
+	interpolationAlpha = 1
+}
+
+// FixedTickRate returns the number of fixed-step ticks per second, or zero if
+// fixed-step updates are disabled.
+func FixedTickRate() int {
+	return opts.FixedTickRate
+}
+
+// FixedDelta returns the delta, in seconds, FixedUpdate is called with, or zero
+// if fixed-step updates are disabled.
+func FixedDelta() float32 {
+	if opts.FixedTickRate <= 0 {
+		return 0
+	}
+	return 1 / float32(opts.FixedTickRate)
+}
+
+// InterpolationAlpha returns how far the current frame is between the last
+// fixed-step tick and the next one, from 0 to 1, to draw in between them. It is
+// always 1 if fixed-step updates are disabled.
+func InterpolationAlpha() float32 {
+	return interpolationAlpha
+}
+
+// advance adds dt to the time that has to be simulated, and returns the number
+// of fixed-step ticks to run this frame. Ticks exceeding
+// RunOptions.MaxFixedSteps are dropped, so the game slows down instead of
+// spending more and more time catching up.
+func (f *fixedStep) advance(dt float32) int {
+	step := FixedDelta()
+	if step == 0 {
+		f.alpha = 1
+		return 0
+	}
+
+	maxSteps := opts.MaxFixedSteps
+	if maxSteps <= 0 {
+		maxSteps = defaultMaxFixedSteps
+	}
+
+	f.accumulator += dt
+	steps := int(f.accumulator / step)
+	f.accumulator -= float32(steps) * step
+	if steps > maxSteps {
+		steps = maxSteps
+	}
+
+	f.alpha = f.accumulator / step
+	return steps
+}
+
+// fixedUpdate runs the given number of fixed-step ticks on the Updater, and on
+// the Systems within it if it's an *ecs.World.
+func fixedUpdate(u Updater, steps int) {
+	if steps == 0 {
+		return
+	}
+
+	var updaters []FixedUpdater
+	if f, ok := u.(FixedUpdater); ok {
+		updaters = append(updaters, f)
+	}
+	if w, ok := u.(*ecs.World); ok {
+		for _, system := range w.Systems() {
+			if f, ok := system.(FixedUpdater); ok {
+				updaters = append(updaters, f)
+			}
+		}
+	}
+
+	dt := FixedDelta()
+	for i := 0; i < steps; i++ {
+		for _, f := range updaters {
+			f.FixedUpdate(dt)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package engo
+
+import (
+	"testing"
+
+	"github.com/EngoEngine/ecs"
+)
+
+type testFixedScene struct {
+	system *testFixedSystem
+}
+
+func (*testFixedScene) Preload() {}
+
+func (s *testFixedScene) Setup(u Updater) {
+	w, _ := u.(*ecs.World)
+	w.AddSystem(s.system)
+}
+
+func (*testFixedScene) Type() string { return "testFixedScene" }
+
+type testFixedSystem struct {
+	ticks   []float32
+	updates []float32
+	alphas  []float32
+}
+
+func (*testFixedSystem) Remove(ecs.BasicEntity) {}
+
+func (s *testFixedSystem) Update(dt float32) {
+	s.updates = append(s.updates, dt)
+	s.alphas = append(s.alphas, InterpolationAlpha())
+}
+
+func (s *testFixedSystem) FixedUpdate(dt float32) {
+	for len(s.ticks) <= len(s.updates) {
+		s.ticks = append(s.ticks, 0)
+	}
+	s.ticks[len(s.updates)] += dt
+}
+
+func TestAdvanceFixedStep(t *testing.T) {
+	Run(RunOptions{
+		NoRun:         true,
+		HeadlessMode:  true,
+		FixedTickRate: 4,
+	}, &testFixedScene{system: &testFixedSystem{}})
+
+	if FixedTickRate() != 4 || FixedDelta() != 0.25 {
+		t.Errorf("Fixed tick rate was not set, got: %v ticks of %v", FixedTickRate(), FixedDelta())
+	}
+
+	data := []struct {
+		dt       float32
+		expSteps int
+		expAlpha float32
+	}{
+		{0.125, 0, 0.5},
+		{0.25, 1, 0.5},
+		{0.125, 1, 0},
+		{0.5625, 2, 0.25},
+		{10, 5, 0.25},
+	}
+
+	fixed := &fixedStep{}
+	for i, d := range data {
+		steps := fixed.advance(d.dt)
+		if steps != d.expSteps {
+			t.Errorf("Frame %d did not run the right amount of ticks, want: %d, got: %d", i, d.expSteps, steps)
+		}
+		if fixed.alpha != d.expAlpha {
+			t.Errorf("Frame %d did not have the right interpolation alpha, want: %v, got: %v", i, d.expAlpha, fixed.alpha)
+		}
+	}
+
+	if err := SetFixedTickRate(0); err != nil {
+		t.Errorf("Unable to disable fixed tick rate: %v", err)
+	}
+	if steps := fixed.advance(1); steps != 0 || fixed.alpha != 1 || FixedDelta() != 0 {
+		t.Errorf("Fixed-step ticks were run while disabled, got: %d steps with an alpha of %v", steps, fixed.alpha)
+	}
+
+	if err := SetFixedTickRate(-1); err == nil {
+		t.Error("Setting a negative fixed tick rate did not fail")
+	}
+}
+
+func TestFixedUpdateSystems(t *testing.T) {
+	resetScenes()
+
+	system := &testFixedSystem{}
+	Run(RunOptions{
+		NoRun:         true,
+		HeadlessMode:  true,
+		FixedTickRate: 4,
+		MaxFixedSteps: 2,
+	}, &testFixedScene{system: system})
+
+	for _, dt := range []float32{0.5, 0.125, 0.25, 1} {
+		updateScenes(dt, dt)
+	}
+
+	expTicks := []float32{0.5, 0, 0.25, 0.5}
+	expUpdates := []float32{0.5, 0.125, 0.25, 1}
+	expAlphas := []float32{0, 0.5, 0.5, 0.5}
+	for i := range expUpdates {
+		var ticks float32
+		if i < len(system.ticks) {
+			ticks = system.ticks[i]
+		}
+		if ticks != expTicks[i] {
+			t.Errorf("Frame %d did not tick the right amount of time, want: %v, got: %v", i, expTicks[i], ticks)
+		}
+		if i >= len(system.updates) || system.updates[i] != expUpdates[i] || system.alphas[i] != expAlphas[i] {
+			t.Errorf("Frame %d was not updated properly, got: %v with alphas %v", i, system.updates, system.alphas)
+		}
+	}
+
+	SetFixedTickRate(0)
+}
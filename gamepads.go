@@ -51,6 +51,15 @@ func (b GamepadButton) Down() bool {
 type GamepadManager struct {
 	mutex    sync.RWMutex
 	gamepads map[string]*Gamepad
+	// mappings are the mappings that have been added, by GUID
+	mappings map[string]GamepadMapping
+	// idMappings caches the mappings of the joysticks a backend only knows by
+	// the id it reports for them, which is nil for the ones without a mapping
+	idMappings map[string]*GamepadMapping
+	hotplug    hotplug
+	// messages are the messages dispatched during the last update, which are
+	// recorded along with the input
+	messages []Message
 }
 
 // NewGamepadManager creates a new GamepadManager
@@ -60,17 +69,99 @@ func NewGamepadManager() *GamepadManager {
 	}
 }
 
-// RegisterGamepad registers the gamepad with the given name. It can return an
-// error if no suitable gamepads are located.
-func (gm *GamepadManager) Register(name string) error {
-	return gm.registerGamepadImpl(name)
-}
-
 // GetGamepad returns the gamepad previously registered with name.
 func (gm *GamepadManager) GetGamepad(name string) *Gamepad {
 	return gm.gamepads[name]
 }
 
 func (gm *GamepadManager) update() {
+	messages := gm.refresh(gm.devicesImpl())
 	gm.updateImpl()
+
+	gm.mutex.Lock()
+	for _, gamepad := range gm.gamepads {
+		gamepad.applyFilters()
+	}
+	gm.mutex.Unlock()
+
+	messages = append(messages, gm.updateJoins()...)
+	gm.messages = messages
+	dispatchGamepadMessages(messages)
+}
+
+// gamepadButtonNames are the names of the buttons of a Gamepad, in the order
+// they are checked in
+var gamepadButtonNames = []string{
+	"A", "B", "X", "Y",
+	"Back", "Start", "Guide",
+	"DpadUp", "DpadRight", "DpadDown", "DpadLeft",
+	"LeftBumper", "RightBumper",
+	"LeftThumb", "RightThumb",
+}
+
+// Button returns the button of the Gamepad with the given name, which is the
+// name of its field, such as "A" or "DpadUp". It returns nil if the Gamepad has
+// no button with that name.
+func (g *Gamepad) Button(name string) *GamepadButton {
+	switch name {
+	case "A":
+		return &g.A
+	case "B":
+		return &g.B
+	case "X":
+		return &g.X
+	case "Y":
+		return &g.Y
+	case "Back":
+		return &g.Back
+	case "Start":
+		return &g.Start
+	case "Guide":
+		return &g.Guide
+	case "DpadUp":
+		return &g.DpadUp
+	case "DpadRight":
+		return &g.DpadRight
+	case "DpadDown":
+		return &g.DpadDown
+	case "DpadLeft":
+		return &g.DpadLeft
+	case "LeftBumper":
+		return &g.LeftBumper
+	case "RightBumper":
+		return &g.RightBumper
+	case "LeftThumb":
+		return &g.LeftThumb
+	case "RightThumb":
+		return &g.RightThumb
+	}
+	return nil
+}
+
+// gamepadAxisNames are the names of the axes of a Gamepad
+var gamepadAxisNames = []string{
+	"LeftX", "LeftY",
+	"RightX", "RightY",
+	"LeftTrigger", "RightTrigger",
+}
+
+// Axis returns the axis of the Gamepad with the given name, which is the name
+// of its field, such as "LeftX" or "RightTrigger". It returns nil if the
+// Gamepad has no axis with that name.
+func (g *Gamepad) Axis(name string) *AxisGamepad {
+	switch name {
+	case "LeftX":
+		return &g.LeftX
+	case "LeftY":
+		return &g.LeftY
+	case "RightX":
+		return &g.RightX
+	case "RightY":
+		return &g.RightY
+	case "LeftTrigger":
+		return &g.LeftTrigger
+	case "RightTrigger":
+		return &g.RightTrigger
+	}
+	return nil
 }
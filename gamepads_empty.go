@@ -2,8 +2,6 @@
 
 package engo
 
-import "errors"
-
 // Gampad is a configuration of a joystick that is able to be mapped to the
 // SDL_GameControllerDB.
 // For more info See https://www.glfw.org/docs/3.3/input_guide.html#gamepad_mapping
@@ -20,8 +18,12 @@ type Gamepad struct {
 	LeftTrigger, RightTrigger             AxisGamepad
 }
 
-func (gm *GamepadManager) registerGamepadImpl(name string) error {
-	return errors.New("Gamepads are not available on this platform!")
-}
+func (gm *GamepadManager) devicesImpl() []gamepadDevice { return nil }
+
+func attachImpl(*Gamepad, gamepadDevice) {}
+
+func detachImpl(*Gamepad) {}
 
 func (gm *GamepadManager) updateImpl() {}
+
+func (gm *GamepadManager) updateMappingsImpl(string) error { return nil }
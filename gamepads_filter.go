@@ -0,0 +1,147 @@
+package engo
+
+import "github.com/EngoEngine/engo/math"
+
+// DeadZoneMode decides how the dead zones of an AxisFilter are applied to a
+// stick.
+type DeadZoneMode uint
+
+const (
+	// DeadZoneAxial applies the dead zones to each axis on its own. This makes
+	// it easy to move in a straight line, but snaps diagonal movement near the
+	// axes.
+	DeadZoneAxial DeadZoneMode = iota
+	// DeadZoneRadial applies the dead zones to the distance the stick is
+	// tilted, using both of its axes, which keeps the direction intact. The
+	// triggers, which have no partner axis, are filtered axially.
+	DeadZoneRadial
+)
+
+// ResponseCurve maps how far an axis is tilted, from 0 to 1 once the dead zones
+// have been applied, to the value it reports, which should be from 0 to 1 as
+// well.
+type ResponseCurve func(float32) float32
+
+// CurveLinear is a ResponseCurve which reports the value as is.
+func CurveLinear(v float32) float32 {
+	return v
+}
+
+// CurveQuadratic is a ResponseCurve which gives finer control over small
+// movements.
+func CurveQuadratic(v float32) float32 {
+	return v * v
+}
+
+// CurveCubic is a ResponseCurve which gives even finer control over small
+// movements than CurveQuadratic.
+func CurveCubic(v float32) float32 {
+	return v * v * v
+}
+
+// AxisFilter turns the raw values of a gamepad axis into the values the game
+// sees. It removes the drift of a stick which doesn't return to the center,
+// shapes the response using a curve, and can invert and smooth the values.
+type AxisFilter struct {
+	// InnerDeadZone is how far the axis can be tilted, from 0 to 1, before it
+	// reports anything. The values beyond it are scaled, so they still start at
+	// 0.
+	InnerDeadZone float32
+	// OuterDeadZone is the part at the edge of the range, from 0 to 1, which
+	// reports the maximum value, for sticks that never quite reach it.
+	OuterDeadZone float32
+	// Mode decides whether the dead zones apply to each axis on its own, or to
+	// the distance the stick is tilted. When using DeadZoneRadial, the Filter
+	// of the X axis of a stick is used for both of its axes.
+	Mode DeadZoneMode
+	// Curve shapes the response of the axis, and is CurveLinear when nil.
+	Curve ResponseCurve
+	// Invert flips the direction of the axis, e.g. for an inverted look.
+	Invert bool
+	// Smoothing is the part of the previous value, from 0 to 1, which is kept
+	// during each update. Zero disables it, while values close to 1 respond
+	// slowly.
+	Smoothing float32
+}
+
+// scale applies the dead zones and the curve to how far the axis is tilted
+func (f *AxisFilter) scale(magnitude float32) float32 {
+	if magnitude <= f.InnerDeadZone {
+		return 0
+	}
+	end := 1 - f.OuterDeadZone
+	if magnitude >= end || end <= f.InnerDeadZone {
+		magnitude = 1
+	} else {
+		magnitude = (magnitude - f.InnerDeadZone) / (end - f.InnerDeadZone)
+	}
+
+	if f.Curve != nil {
+		magnitude = f.Curve(magnitude)
+	}
+	return math.Clamp(magnitude, 0, 1)
+}
+
+// Apply returns the filtered value of a single axis. The previous value is used
+// for smoothing.
+func (f *AxisFilter) Apply(raw, previous float32) float32 {
+	v := f.scale(math.Abs(raw))
+	if raw < 0 {
+		v = -v
+	}
+	return f.finish(v, previous)
+}
+
+// ApplyRadial returns the filtered values of both axes of a stick, applying the
+// dead zones to the distance the stick is tilted. The previous values are used
+// for smoothing.
+func (f *AxisFilter) ApplyRadial(rawX, rawY, previousX, previousY float32) (x, y float32) {
+	magnitude := math.Sqrt(rawX*rawX + rawY*rawY)
+	if magnitude > 0 {
+		factor := f.scale(math.Min(magnitude, 1)) / magnitude
+		x, y = rawX*factor, rawY*factor
+	}
+	return f.finish(x, previousX), f.finish(y, previousY)
+}
+
+// finish inverts and smooths the value
+func (f *AxisFilter) finish(v, previous float32) float32 {
+	if f.Invert {
+		v = -v
+	}
+	if f.Smoothing > 0 {
+		v = previous + (v-previous)*(1-math.Clamp(f.Smoothing, 0, 1))
+	}
+	return v
+}
+
+// SetFilter sets the Filter of all axes of the Gamepad. Pass nil to report the
+// raw values again.
+func (g *Gamepad) SetFilter(f *AxisFilter) {
+	for _, name := range gamepadAxisNames {
+		g.Axis(name).Filter = f
+	}
+}
+
+// applyFilters replaces the values of the axes by their filtered raw values
+func (g *Gamepad) applyFilters() {
+	g.applyStickFilters(&g.LeftX, &g.LeftY)
+	g.applyStickFilters(&g.RightX, &g.RightY)
+	g.LeftTrigger.applyFilter()
+	g.RightTrigger.applyFilter()
+}
+
+func (g *Gamepad) applyStickFilters(x, y *AxisGamepad) {
+	if x.Filter != nil && x.Filter.Mode == DeadZoneRadial {
+		x.value, y.value = x.Filter.ApplyRadial(x.raw, y.raw, x.last, y.last)
+		return
+	}
+	x.applyFilter()
+	y.applyFilter()
+}
+
+func (ag *AxisGamepad) applyFilter() {
+	if ag.Filter != nil {
+		ag.value = ag.Filter.Apply(ag.raw, ag.last)
+	}
+}
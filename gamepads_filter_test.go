@@ -0,0 +1,93 @@
+package engo
+
+import (
+	"testing"
+
+	"github.com/EngoEngine/engo/math"
+)
+
+func assertAxis(t *testing.T, name string, expected, actual float32) {
+	t.Helper()
+	if math.Abs(expected-actual) > 0.0001 {
+		t.Errorf("Expected %s to be %v, got %v", name, expected, actual)
+	}
+}
+
+func TestAxisFilterDeadZones(t *testing.T) {
+	f := &AxisFilter{InnerDeadZone: 0.2, OuterDeadZone: 0.1}
+	assertAxis(t, "drift", 0, f.Apply(0.15, 0))
+	assertAxis(t, "negative drift", 0, f.Apply(-0.2, 0))
+	assertAxis(t, "halfway", 0.5, f.Apply(0.55, 0))
+	assertAxis(t, "negative halfway", -0.5, f.Apply(-0.55, 0))
+	assertAxis(t, "outer dead zone", 1, f.Apply(0.95, 0))
+	assertAxis(t, "beyond the range", -1, f.Apply(-1.2, 0))
+}
+
+func TestAxisFilterCurves(t *testing.T) {
+	f := &AxisFilter{Curve: CurveQuadratic}
+	assertAxis(t, "quadratic", 0.25, f.Apply(0.5, 0))
+	assertAxis(t, "negative quadratic", -0.25, f.Apply(-0.5, 0))
+
+	f.Curve = CurveCubic
+	assertAxis(t, "cubic", 0.125, f.Apply(0.5, 0))
+
+	f.Curve = func(v float32) float32 { return 1 - v }
+	assertAxis(t, "custom", 0.75, f.Apply(0.25, 0))
+
+	f = &AxisFilter{Invert: true}
+	assertAxis(t, "inverted", -0.5, f.Apply(0.5, 0))
+}
+
+func TestAxisFilterRadial(t *testing.T) {
+	f := &AxisFilter{InnerDeadZone: 0.2, Mode: DeadZoneRadial}
+
+	// The dead zone applies to the distance the stick is tilted, using both
+	// axes
+	x, y := f.ApplyRadial(0.12, 0.12, 0, 0)
+	if x != 0 || y != 0 {
+		t.Errorf("Stick within the radial dead zone should have been neutral, got %v, %v", x, y)
+	}
+	x, y = f.ApplyRadial(0.3, 0.3, 0, 0)
+	if x <= 0 || y <= 0 {
+		t.Errorf("Stick outside the radial dead zone should have been tilted, got %v, %v", x, y)
+	}
+	assertAxis(t, "direction", x, y)
+
+	// The direction is kept when the magnitude is rescaled
+	x, y = f.ApplyRadial(0.6, 0, 0, 0)
+	assertAxis(t, "x", 0.5, x)
+	assertAxis(t, "y", 0, y)
+}
+
+func TestAxisFilterSmoothing(t *testing.T) {
+	f := &AxisFilter{Smoothing: 0.5}
+	v := f.Apply(1, 0)
+	assertAxis(t, "first update", 0.5, v)
+	v = f.Apply(1, v)
+	assertAxis(t, "second update", 0.75, v)
+}
+
+func TestGamepadSetFilter(t *testing.T) {
+	Input = NewInputManager()
+	gamepad := &Gamepad{}
+	Input.gamepads.gamepads["player1"] = gamepad
+	gamepad.SetFilter(&AxisFilter{InnerDeadZone: 0.2, Mode: DeadZoneRadial})
+	gamepad.RightTrigger.Filter = &AxisFilter{Invert: true}
+
+	gamepad.LeftX.set(0.12)
+	gamepad.LeftY.set(0.12)
+	gamepad.RightX.set(0.1)
+	gamepad.RightTrigger.set(0.5)
+	gamepad.applyFilters()
+
+	assertAxis(t, "LeftX", 0, gamepad.LeftX.Value())
+	assertAxis(t, "LeftY", 0, gamepad.LeftY.Value())
+	assertAxis(t, "raw LeftX", 0.12, gamepad.LeftX.Raw())
+	assertAxis(t, "RightX", 0, gamepad.RightX.Value())
+	assertAxis(t, "RightTrigger", -0.5, gamepad.RightTrigger.Value())
+
+	gamepad.SetFilter(nil)
+	gamepad.LeftX.set(0.15)
+	gamepad.applyFilters()
+	assertAxis(t, "unfiltered LeftX", 0.15, gamepad.LeftX.Value())
+}
@@ -40,44 +40,42 @@ var joys = []glfw.Joystick{
 	glfw.Joystick13, glfw.Joystick14, glfw.Joystick15, glfw.Joystick16,
 }
 
-var usedjoys = []glfw.Joystick{}
+// updateMappingsImpl passes the mappings on to GLFW, which uses them to report the
+// state of the joysticks it didn't recognize as gamepads before.
+func (gm *GamepadManager) updateMappingsImpl(mappings string) error {
+	if !glfw.UpdateGamepadMappings(mappings) {
+		return errors.New("unable to update the gamepad mappings of GLFW")
+	}
+	return nil
+}
 
-func (gm *GamepadManager) registerGamepadImpl(name string) error {
-	found := false
-	gm.mutex.Lock()
-	defer gm.mutex.Unlock()
-joyLoop:
+// devicesImpl returns the joysticks which are plugged in and recognized as
+// gamepads by GLFW
+func (gm *GamepadManager) devicesImpl() []gamepadDevice {
+	var devices []gamepadDevice
 	for _, joy := range joys {
-		for _, u := range usedjoys {
-			if joy == u {
-				continue joyLoop
-			}
-		}
-		if joy.IsGamepad() {
-			gm.gamepads[name] = &Gamepad{
-				joystick:  joy,
-				id:        joy.GetGUID(),
-				connected: true,
-			}
-			found = true
-			usedjoys = append(usedjoys, joy)
-			break joyLoop
+		if joy.Present() && joy.IsGamepad() {
+			devices = append(devices, gamepadDevice{index: int(joy), guid: joy.GetGUID()})
 		}
 	}
-	if !found {
-		warning("Unable to locate any usable gamepads.")
-		gm.gamepads[name] = &Gamepad{id: "", connected: false}
-		return errors.New("unable to locate any usable gamepads \ngamepad will be added when a new one is plugged in")
-	}
-	return nil
+	return devices
+}
+
+func attachImpl(g *Gamepad, d gamepadDevice) {
+	g.joystick = glfw.Joystick(d.index)
+	g.id = d.guid
+	g.connected = true
+}
+
+func detachImpl(g *Gamepad) {
+	g.connected = false
 }
 
 func (gm *GamepadManager) updateImpl() {
 	gm.mutex.Lock()
 	defer gm.mutex.Unlock()
-	for name, gamepad := range gm.gamepads {
+	for _, gamepad := range gm.hotplug.pads {
 		if !gamepad.connected {
-			warning("Gamepad " + name + " was not available for update!")
 			continue
 		}
 		if gamepad.joystick.Present() {
@@ -180,8 +178,8 @@ func (gm *GamepadManager) updateImpl() {
 			gamepad.LeftTrigger.set(state.Axes[glfw.AxisLeftTrigger])
 			gamepad.RightTrigger.set(state.Axes[glfw.AxisRightTrigger])
 		} else {
+			// it is released and reported as disconnected during the next update
 			gamepad.connected = false
-			warning("Gamepad " + name + " was not available to update!")
 		}
 	}
 }
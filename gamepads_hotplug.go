@@ -0,0 +1,376 @@
+package engo
+
+import (
+	"errors"
+	"sort"
+)
+
+// GamepadConnectedMessage is dispatched when a joystick is plugged in. Name is
+// the name of the Gamepad it was assigned to, and is empty if no registered
+// Gamepad was waiting for one, in which case it can join using
+// GamepadManager.Join.
+type GamepadConnectedMessage struct {
+	Name string
+	// GUID identifies the kind of joystick
+	GUID string
+}
+
+// Type implements the Message interface.
+func (GamepadConnectedMessage) Type() string {
+	return "GamepadConnectedMessage"
+}
+
+// GamepadDisconnectedMessage is dispatched when a joystick is unplugged. Name
+// is the name of the Gamepad it was assigned to, and is empty if it wasn't
+// assigned to any. The Gamepad stays registered, and is assigned to the next
+// joystick that gets plugged in, preferably one of the same kind.
+type GamepadDisconnectedMessage struct {
+	Name string
+	// GUID identifies the kind of joystick
+	GUID string
+}
+
+// Type implements the Message interface.
+func (GamepadDisconnectedMessage) Type() string {
+	return "GamepadDisconnectedMessage"
+}
+
+// GamepadJoinedMessage is dispatched when a joystick joins as a Gamepad, after
+// GamepadManager.Join.
+type GamepadJoinedMessage struct {
+	Name string
+	// GUID identifies the kind of joystick
+	GUID string
+}
+
+// Type implements the Message interface.
+func (GamepadJoinedMessage) Type() string {
+	return "GamepadJoinedMessage"
+}
+
+// gamepadDevice is a joystick which is plugged in, as reported by the backend
+type gamepadDevice struct {
+	// index is how the backend refers to the joystick
+	index int
+	// guid identifies the kind of joystick
+	guid string
+	// mapping turns the raw state of the joystick into the one of a Gamepad,
+	// for backends which don't do so themselves
+	mapping *GamepadMapping
+}
+
+// gamepadJoin is a player slot which is waiting for a joystick to join
+type gamepadJoin struct {
+	name, button string
+}
+
+// hotplug tracks which joysticks are plugged in, and which Gamepads they are
+// assigned to
+type hotplug struct {
+	// pads holds a Gamepad for each joystick that is plugged in, by its index
+	pads map[int]*Gamepad
+	// devices holds the joysticks that are plugged in, by their index
+	devices map[int]gamepadDevice
+	// owners holds the name of the Gamepad each joystick is assigned to, by its
+	// index
+	owners map[int]string
+	// order holds the names of the registered Gamepads, in the order they were
+	// registered in
+	order []string
+	// lastGUID is the kind of joystick each Gamepad was last assigned to, to
+	// prefer the same kind when reconnecting
+	lastGUID map[string]string
+	joins    []gamepadJoin
+}
+
+func (gm *GamepadManager) initHotplug() {
+	if gm.hotplug.pads == nil {
+		gm.hotplug = hotplug{
+			pads:     make(map[int]*Gamepad),
+			devices:  make(map[int]gamepadDevice),
+			owners:   make(map[int]string),
+			lastGUID: make(map[string]string),
+		}
+	}
+}
+
+// Register registers the gamepad with the given name, and assigns it a joystick
+// which is plugged in and not assigned to another Gamepad yet. If there is
+// none, the Gamepad is assigned to the next joystick that gets plugged in, and
+// an error is returned.
+func (gm *GamepadManager) Register(name string) error {
+	messages := gm.refresh(gm.devicesImpl())
+	defer dispatchGamepadMessages(messages)
+
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+	if gm.index(name) >= 0 {
+		return nil
+	}
+	if _, ok := gm.gamepads[name]; !ok {
+		gm.gamepads[name] = &Gamepad{}
+	}
+	gm.addOrder(name)
+
+	if free := gm.freeIndices(); len(free) > 0 {
+		gm.assign(free[0], name)
+		return nil
+	}
+	warning("Unable to locate any usable gamepads.")
+	return errors.New("unable to locate any usable gamepads \ngamepad will be added when a new one is plugged in")
+}
+
+// Join lets the next joystick which isn't assigned to a Gamepad yet, and of
+// which the button with the given name is pressed, join as the Gamepad with the
+// given name, e.g. to let players press A to join a local multiplayer game. A
+// GamepadJoinedMessage is dispatched once it does. Each call waits for another
+// joystick, in order.
+func (gm *GamepadManager) Join(name, button string) {
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+	gm.initHotplug()
+	gm.hotplug.joins = append(gm.hotplug.joins, gamepadJoin{name: name, button: button})
+}
+
+// CancelJoin stops waiting for a joystick to join as the Gamepad with the given
+// name.
+func (gm *GamepadManager) CancelJoin(name string) {
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+	joins := gm.hotplug.joins[:0]
+	for _, join := range gm.hotplug.joins {
+		if join.name != name {
+			joins = append(joins, join)
+		}
+	}
+	gm.hotplug.joins = joins
+}
+
+// Leave unregisters the Gamepad with the given name, so its joystick can join
+// again, e.g. when a player leaves a local multiplayer game.
+func (gm *GamepadManager) Leave(name string) {
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+	gm.initHotplug()
+	if index := gm.index(name); index >= 0 {
+		gm.unassign(index)
+	}
+	delete(gm.gamepads, name)
+	delete(gm.hotplug.lastGUID, name)
+	order := gm.hotplug.order[:0]
+	for _, other := range gm.hotplug.order {
+		if other != name {
+			order = append(order, other)
+		}
+	}
+	gm.hotplug.order = order
+}
+
+// Connected reports whether the Gamepad with the given name is assigned to a
+// joystick which is plugged in.
+func (gm *GamepadManager) Connected(name string) bool {
+	gm.mutex.RLock()
+	defer gm.mutex.RUnlock()
+	return gm.index(name) >= 0
+}
+
+// Unassigned returns the number of joysticks which are plugged in, but not
+// assigned to a Gamepad.
+func (gm *GamepadManager) Unassigned() int {
+	gm.mutex.RLock()
+	defer gm.mutex.RUnlock()
+	return len(gm.freeIndices())
+}
+
+// index returns the index of the joystick assigned to the Gamepad, or -1 if it
+// has none
+func (gm *GamepadManager) index(name string) int {
+	for index, owner := range gm.hotplug.owners {
+		if owner == name {
+			return index
+		}
+	}
+	return -1
+}
+
+// freeIndices returns the indices of the joysticks which aren't assigned to a
+// Gamepad, in order
+func (gm *GamepadManager) freeIndices() []int {
+	var indices []int
+	for index := range gm.hotplug.pads {
+		if _, ok := gm.hotplug.owners[index]; !ok {
+			indices = append(indices, index)
+		}
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+func (gm *GamepadManager) addOrder(name string) {
+	for _, other := range gm.hotplug.order {
+		if other == name {
+			return
+		}
+	}
+	gm.hotplug.order = append(gm.hotplug.order, name)
+}
+
+// assign assigns the joystick to the Gamepad with the given name, which keeps
+// the Gamepad it was registered with
+func (gm *GamepadManager) assign(index int, name string) {
+	gamepad := gm.gamepads[name]
+	gm.hotplug.pads[index] = gamepad
+	gm.hotplug.owners[index] = name
+	gm.hotplug.lastGUID[name] = gm.hotplug.devices[index].guid
+	attachImpl(gamepad, gm.hotplug.devices[index])
+}
+
+// unassign frees the joystick, which gets a Gamepad of its own so it can join
+// again
+func (gm *GamepadManager) unassign(index int) {
+	delete(gm.hotplug.owners, index)
+	gm.hotplug.pads[index] = &Gamepad{}
+	attachImpl(gm.hotplug.pads[index], gm.hotplug.devices[index])
+}
+
+// waiting returns the name of the first registered Gamepad without a joystick,
+// or an empty string if there is none. If exact is set, only a Gamepad which
+// was last assigned to a joystick of the given kind is returned.
+func (gm *GamepadManager) waiting(guid string, exact bool) string {
+	for _, name := range gm.hotplug.order {
+		if gm.index(name) >= 0 {
+			continue
+		}
+		if !exact || gm.hotplug.lastGUID[name] == guid {
+			return name
+		}
+	}
+	return ""
+}
+
+// refresh compares the joysticks which are plugged in with the ones during the
+// previous update, and returns the messages to dispatch about them
+func (gm *GamepadManager) refresh(present []gamepadDevice) []Message {
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+	gm.initHotplug()
+
+	var messages []Message
+	plugged := make(map[int]gamepadDevice, len(present))
+	for _, d := range present {
+		plugged[d.index] = d
+	}
+
+	var gone []int
+	for index, d := range gm.hotplug.devices {
+		if current, ok := plugged[index]; !ok || current.guid != d.guid {
+			gone = append(gone, index)
+		}
+	}
+	sort.Ints(gone)
+	for _, index := range gone {
+		name := gm.hotplug.owners[index]
+		gamepad := gm.hotplug.pads[index]
+		detachImpl(gamepad)
+		gamepad.release()
+		messages = append(messages, GamepadDisconnectedMessage{Name: name, GUID: gm.hotplug.devices[index].guid})
+		delete(gm.hotplug.owners, index)
+		delete(gm.hotplug.pads, index)
+		delete(gm.hotplug.devices, index)
+	}
+
+	var added []gamepadDevice
+	for _, d := range present {
+		if _, ok := gm.hotplug.devices[d.index]; !ok {
+			gm.hotplug.devices[d.index] = d
+			added = append(added, d)
+		}
+	}
+	// the Gamepads get a joystick of the kind they had before first, and any
+	// other one after that
+	for _, exact := range []bool{true, false} {
+		for _, d := range added {
+			if _, ok := gm.hotplug.owners[d.index]; ok {
+				continue
+			}
+			if name := gm.waiting(d.guid, exact); name != "" {
+				gm.assign(d.index, name)
+			}
+		}
+	}
+	for _, d := range added {
+		if _, ok := gm.hotplug.owners[d.index]; !ok {
+			gm.hotplug.pads[d.index] = &Gamepad{}
+			attachImpl(gm.hotplug.pads[d.index], d)
+		}
+		messages = append(messages, GamepadConnectedMessage{Name: gm.hotplug.owners[d.index], GUID: d.guid})
+	}
+	return messages
+}
+
+// updateJoins lets the joysticks join of which the button is pressed, and
+// returns the messages to dispatch about them
+func (gm *GamepadManager) updateJoins() []Message {
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+
+	var messages []Message
+	for _, index := range gm.freeIndices() {
+		if len(gm.hotplug.joins) == 0 {
+			break
+		}
+		join := gm.hotplug.joins[0]
+		button := gm.hotplug.pads[index].Button(join.button)
+		if button == nil || !button.JustPressed() {
+			continue
+		}
+
+		gm.hotplug.joins = gm.hotplug.joins[1:]
+		if old := gm.index(join.name); old >= 0 {
+			gm.unassign(old)
+		}
+		if gamepad, ok := gm.gamepads[join.name]; ok {
+			// Keep the Gamepad the name was registered with, so it stays valid
+			// for whoever holds on to it, along with its filters
+			gamepad.takeState(gm.hotplug.pads[index])
+		} else {
+			gm.gamepads[join.name] = gm.hotplug.pads[index]
+		}
+		gm.addOrder(join.name)
+		gm.assign(index, join.name)
+		messages = append(messages, GamepadJoinedMessage{Name: join.name, GUID: gm.hotplug.devices[index].guid})
+	}
+	return messages
+}
+
+// takeState sets the buttons and axes of the Gamepad to the ones of other, e.g.
+// when the joystick of other is assigned to it, so a button pressed to join is
+// still JustPressed
+func (g *Gamepad) takeState(other *Gamepad) {
+	for _, name := range gamepadButtonNames {
+		*g.Button(name) = *other.Button(name)
+	}
+	for _, name := range gamepadAxisNames {
+		axis, o := g.Axis(name), other.Axis(name)
+		axis.value, axis.last, axis.raw = o.value, o.last, o.raw
+	}
+}
+
+// release lets go of all buttons and centers all axes of the Gamepad
+func (g *Gamepad) release() {
+	for _, name := range gamepadButtonNames {
+		g.Button(name).set(false)
+	}
+	for _, name := range gamepadAxisNames {
+		g.Axis(name).set(0)
+	}
+}
+
+func dispatchGamepadMessages(messages []Message) {
+	if Mailbox == nil {
+		return
+	}
+	for _, msg := range messages {
+		Mailbox.Dispatch(msg)
+	}
+}
@@ -0,0 +1,158 @@
+package engo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func setupHotplugTest(t *testing.T) (*GamepadManager, *[]Message) {
+	mailbox := Mailbox
+	t.Cleanup(func() { Mailbox = mailbox })
+	Mailbox = &MessageManager{}
+	var messages []Message
+	ListenFor(Mailbox, func(msg GamepadConnectedMessage) {
+		messages = append(messages, msg)
+	})
+	ListenFor(Mailbox, func(msg GamepadDisconnectedMessage) {
+		messages = append(messages, msg)
+	})
+	ListenFor(Mailbox, func(msg GamepadJoinedMessage) {
+		messages = append(messages, msg)
+	})
+	return NewGamepadManager(), &messages
+}
+
+func assertMessages(t *testing.T, messages *[]Message, expected ...Message) {
+	t.Helper()
+	if len(*messages) != len(expected) || (len(expected) > 0 && !reflect.DeepEqual(*messages, expected)) {
+		t.Errorf("Expected messages %v, got %v", expected, *messages)
+	}
+	*messages = nil
+}
+
+func TestGamepadHotplug(t *testing.T) {
+	gm, messages := setupHotplugTest(t)
+
+	if err := gm.Register("player1"); err == nil {
+		t.Error("Registering without any joysticks plugged in should have returned an error")
+	}
+	gamepad := gm.GetGamepad("player1")
+	if gamepad == nil || gm.Connected("player1") {
+		t.Fatal("Gamepad should have been registered without being connected")
+	}
+
+	dispatchGamepadMessages(gm.refresh([]gamepadDevice{{index: 0, guid: "aaaa"}}))
+	assertMessages(t, messages, GamepadConnectedMessage{Name: "player1", GUID: "aaaa"})
+	if !gm.Connected("player1") || gm.GetGamepad("player1") != gamepad {
+		t.Error("Plugging in a joystick should have connected the registered Gamepad")
+	}
+
+	gamepad.A.set(true)
+	gamepad.LeftX.set(0.5)
+	dispatchGamepadMessages(gm.refresh(nil))
+	assertMessages(t, messages, GamepadDisconnectedMessage{Name: "player1", GUID: "aaaa"})
+	if gm.Connected("player1") || gm.GetGamepad("player1") != gamepad {
+		t.Error("Gamepad should have stayed registered after unplugging its joystick")
+	}
+	if gamepad.A.Down() || gamepad.LeftX.Value() != 0 {
+		t.Error("Unplugging the joystick should have released its buttons and centered its axes")
+	}
+
+	// The Gamepad prefers a joystick of the same kind as before
+	dispatchGamepadMessages(gm.refresh([]gamepadDevice{{index: 1, guid: "bbbb"}, {index: 2, guid: "aaaa"}}))
+	assertMessages(t, messages,
+		GamepadConnectedMessage{GUID: "bbbb"},
+		GamepadConnectedMessage{Name: "player1", GUID: "aaaa"},
+	)
+	if gm.hotplug.pads[2] != gamepad || gm.Unassigned() != 1 {
+		t.Error("Gamepad should have been assigned to the joystick of the same kind")
+	}
+
+	// Replacing a joystick at the same index disconnects the old one
+	dispatchGamepadMessages(gm.refresh([]gamepadDevice{{index: 1, guid: "cccc"}, {index: 2, guid: "aaaa"}}))
+	assertMessages(t, messages,
+		GamepadDisconnectedMessage{GUID: "bbbb"},
+		GamepadConnectedMessage{GUID: "cccc"},
+	)
+}
+
+func TestGamepadJoin(t *testing.T) {
+	gm, messages := setupHotplugTest(t)
+
+	devices := []gamepadDevice{{index: 0, guid: "aaaa"}, {index: 1, guid: "bbbb"}}
+	gm.refresh(devices)
+	gm.Join("player1", "A")
+	gm.Join("player2", "A")
+
+	dispatchGamepadMessages(gm.updateJoins())
+	assertMessages(t, messages)
+
+	pad := gm.hotplug.pads[1]
+	pad.A.set(true)
+	dispatchGamepadMessages(gm.updateJoins())
+	assertMessages(t, messages, GamepadJoinedMessage{Name: "player1", GUID: "bbbb"})
+	if gm.GetGamepad("player1") != pad || !gm.Connected("player1") || gm.Unassigned() != 1 {
+		t.Error("The joystick on which A was pressed should have joined as player1")
+	}
+
+	// A joystick which already joined can't take the next slot
+	pad.A.set(true)
+	dispatchGamepadMessages(gm.updateJoins())
+	assertMessages(t, messages)
+
+	gm.CancelJoin("player2")
+	gm.hotplug.pads[0].A.set(true)
+	dispatchGamepadMessages(gm.updateJoins())
+	assertMessages(t, messages)
+
+	gm.Leave("player1")
+	if gm.GetGamepad("player1") != nil || gm.Connected("player1") || gm.Unassigned() != 2 {
+		t.Error("Leaving should have unregistered the Gamepad and freed its joystick")
+	}
+	if gm.hotplug.pads[1] == pad {
+		t.Error("The freed joystick should have gotten a Gamepad of its own")
+	}
+
+	// Unplugging a joystick without a Gamepad is reported without a name
+	dispatchGamepadMessages(gm.refresh(devices[:1]))
+	assertMessages(t, messages, GamepadDisconnectedMessage{GUID: "bbbb"})
+}
+
+func TestGamepadJoinKeepsRegisteredGamepad(t *testing.T) {
+	gm, messages := setupHotplugTest(t)
+
+	gm.Register("player1")
+	gamepad := gm.GetGamepad("player1")
+	filter := &AxisFilter{InnerDeadZone: 0.2}
+	gamepad.SetFilter(filter)
+	dispatchGamepadMessages(gm.refresh([]gamepadDevice{{index: 0, guid: "aaaa"}, {index: 1, guid: "bbbb"}}))
+	assertMessages(t, messages, GamepadConnectedMessage{Name: "player1", GUID: "aaaa"},
+		GamepadConnectedMessage{GUID: "bbbb"})
+
+	// player1 switches to the other joystick
+	gm.Join("player1", "A")
+	gm.hotplug.pads[1].A.set(true)
+	gm.hotplug.pads[1].LeftX.set(0.5)
+	dispatchGamepadMessages(gm.updateJoins())
+	assertMessages(t, messages, GamepadJoinedMessage{Name: "player1", GUID: "bbbb"})
+
+	if gm.GetGamepad("player1") != gamepad || gm.hotplug.pads[1] != gamepad {
+		t.Fatal("The joystick should have been attached to the Gamepad player1 was registered with")
+	}
+	if gamepad.LeftX.Filter != filter {
+		t.Error("The filters of the Gamepad should have been kept")
+	}
+	if !gamepad.A.JustPressed() || gamepad.LeftX.Raw() != 0.5 {
+		t.Error("The Gamepad should have taken over the state of the joystick which joined")
+	}
+	if gm.hotplug.pads[0] == gamepad || gm.Unassigned() != 1 {
+		t.Error("The joystick player1 had before should have been freed")
+	}
+
+	// Input of the joystick now reaches the Gamepad
+	gm.hotplug.pads[1].A.set(true)
+	gamepad.applyFilters()
+	if !gamepad.A.Down() || gamepad.LeftX.Value() == gamepad.LeftX.Raw() {
+		t.Error("The Gamepad should have gotten the input of its new joystick, with its filters applied")
+	}
+}
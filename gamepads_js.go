@@ -1,8 +1,12 @@
-//+build js
+//go:build js
+// +build js
 
 package engo
 
-import "errors"
+import (
+	"strings"
+	"syscall/js"
+)
 
 // Gampad is a configuration of a joystick that is able to be mapped to the
 // SDL_GameControllerDB.
@@ -17,92 +21,151 @@ type Gamepad struct {
 	RightX, RightY                        AxisGamepad
 	LeftTrigger, RightTrigger             AxisGamepad
 
+	index     int
 	id        string
 	connected bool
+	// mapping is used for the gamepads which the browser doesn't map to the
+	// standard layout, and is nil for the others
+	mapping *GamepadMapping
 }
 
-var usedGpds []string
+func init() {
+	mappingPlatform = browserMappingPlatform()
+}
 
-func (gm *GamepadManager) registerGamepadImpl(name string) error {
+// browserMappingPlatform returns the name the SDL_GameControllerDB uses for the
+// platform the browser runs on, as the raw buttons and axes it reports depend
+// on the drivers of that platform
+func browserMappingPlatform() string {
+	if window.IsUndefined() || window.Get("navigator").IsUndefined() {
+		return "" // node for testing
+	}
+	navigator := window.Get("navigator")
+	agent := navigator.Get("userAgent").String()
+	platform := navigator.Get("platform").String()
+	switch {
+	case strings.Contains(agent, "Android"):
+		return "Android"
+	case strings.Contains(agent, "iPhone") || strings.Contains(agent, "iPad"):
+		return "iOS"
+	case strings.HasPrefix(platform, "Win"):
+		return "Windows"
+	case strings.HasPrefix(platform, "Mac"):
+		return "Mac OS X"
+	case strings.HasPrefix(platform, "Linux"):
+		return "Linux"
+	}
+	return ""
+}
+
+// updateMappingsImpl does nothing, as the mappings are looked up when a gamepad
+// the browser doesn't map to the standard layout is connected
+func (gm *GamepadManager) updateMappingsImpl(string) error { return nil }
+
+// devicesImpl returns the gamepads which are connected and either use the
+// standard layout, or have a mapping which was added or bundled, found using
+// the vendor and product the browser reports in their id
+func (gm *GamepadManager) devicesImpl() []gamepadDevice {
+	if window.IsUndefined() || window.Get("navigator").IsUndefined() {
+		return nil // node for testing
+	}
 	gpds := window.Get("navigator").Call("getGamepads")
-	found := false
-	gm.mutex.Lock()
-	defer gm.mutex.Unlock()
+	var devices []gamepadDevice
 	for i := 0; i < gpds.Length(); i++ {
-		if gpds.Index(i).IsNull() {
-			continue
-		}
-		if gpds.Index(i).Get("mapping").String() != "standard" {
+		gpd := gpds.Index(i)
+		if gpd.IsNull() || gpd.IsUndefined() || !gpd.Get("connected").Bool() {
 			continue
 		}
-		gpid := gpds.Index(i).Get("id").String()
-		gm.gamepads[name] = &Gamepad{
-			id:        gpid,
-			connected: true,
+		device := gamepadDevice{index: gpd.Get("index").Int(), guid: gpd.Get("id").String()}
+		if gpd.Get("mapping").String() != "standard" {
+			if device.mapping = gm.mappingForBrowserID(device.guid); device.mapping == nil {
+				continue
+			}
 		}
-		found = true
+		devices = append(devices, device)
 	}
-	if !found {
-		warning("Unable to locate any usable gamepads.")
-		gm.gamepads[name] = &Gamepad{}
-		return errors.New("unable to locate any usable gamepads \ngamepad will be added when a new one is plugged in")
-	}
-	return nil
+	return devices
+}
+
+func attachImpl(g *Gamepad, d gamepadDevice) {
+	g.index = d.index
+	g.id = d.guid
+	g.mapping = d.mapping
+	g.connected = true
+}
+
+func detachImpl(g *Gamepad) {
+	g.connected = false
 }
 
 func (gm *GamepadManager) updateImpl() {
 	if window.IsUndefined() || window.Get("navigator").IsUndefined() {
-               return // node for testing
-        }
+		return // node for testing
+	}
 	gpds := window.Get("navigator").Call("getGamepads")
 	gm.mutex.Lock()
 	defer gm.mutex.Unlock()
-	for name, gamepad := range gm.gamepads {
+	for _, gamepad := range gm.hotplug.pads {
 		if !gamepad.connected {
-			warning("Gamepad " + name + " was not available for update!")
 			continue
 		}
-		for i := 0; i < gpds.Length(); i++ {
-			if gpds.Index(i).IsNull() {
-				continue
-			}
-			gpid := gpds.Index(i).Get("id").String()
-			if gpid == gamepad.id {
-				if gpds.Index(i).Get("connected").Bool() {
-					gamepad.A.set(gpds.Index(i).Get("buttons").Index(0).Get("pressed").Bool())
-					gamepad.B.set(gpds.Index(i).Get("buttons").Index(1).Get("pressed").Bool())
-					gamepad.X.set(gpds.Index(i).Get("buttons").Index(2).Get("pressed").Bool())
-					gamepad.Y.set(gpds.Index(i).Get("buttons").Index(3).Get("pressed").Bool())
-					gamepad.LeftBumper.set(gpds.Index(i).Get("buttons").Index(4).Get("pressed").Bool())
-					gamepad.RightBumper.set(gpds.Index(i).Get("buttons").Index(5).Get("pressed").Bool())
-					if gpds.Index(i).Get("buttons").Index(6).Get("pressed").Bool() {
-						gamepad.LeftTrigger.set(1.0)
-					} else {
-						gamepad.LeftTrigger.set(0.0)
-					}
-					if gpds.Index(i).Get("buttons").Index(7).Get("pressed").Bool() {
-						gamepad.RightTrigger.set(1.0)
-					} else {
-						gamepad.RightTrigger.set(0.0)
-					}
-					gamepad.Back.set(gpds.Index(i).Get("buttons").Index(8).Get("pressed").Bool())
-					gamepad.Start.set(gpds.Index(i).Get("buttons").Index(9).Get("pressed").Bool())
-					gamepad.LeftThumb.set(gpds.Index(i).Get("buttons").Index(10).Get("pressed").Bool())
-					gamepad.RightThumb.set(gpds.Index(i).Get("buttons").Index(11).Get("pressed").Bool())
-					gamepad.DpadUp.set(gpds.Index(i).Get("buttons").Index(12).Get("pressed").Bool())
-					gamepad.DpadDown.set(gpds.Index(i).Get("buttons").Index(13).Get("pressed").Bool())
-					gamepad.DpadLeft.set(gpds.Index(i).Get("buttons").Index(14).Get("pressed").Bool())
-					gamepad.DpadRight.set(gpds.Index(i).Get("buttons").Index(15).Get("pressed").Bool())
-					gamepad.Guide.set(gpds.Index(i).Get("buttons").Index(16).Get("pressed").Bool())
-					gamepad.LeftX.set(float32(gpds.Index(i).Get("axes").Index(0).Float()))
-					gamepad.LeftY.set(float32(gpds.Index(i).Get("axes").Index(1).Float()))
-					gamepad.RightX.set(float32(gpds.Index(i).Get("axes").Index(2).Float()))
-					gamepad.RightY.set(float32(gpds.Index(i).Get("axes").Index(3).Float()))
-				} else {
-					gamepad.connected = false
-					warning("Gamepad " + name + " was not available to update!")
-				}
-			}
+		gpd := gpds.Index(gamepad.index)
+		if gpd.IsNull() || gpd.IsUndefined() || !gpd.Get("connected").Bool() {
+			// it is released and reported as disconnected during the next
+			// update
+			gamepad.connected = false
+			continue
 		}
+		if gamepad.mapping != nil {
+			gamepad.mapping.Apply(browserJoystickState(gpd), gamepad)
+			continue
+		}
+		gamepad.A.set(gpd.Get("buttons").Index(0).Get("pressed").Bool())
+		gamepad.B.set(gpd.Get("buttons").Index(1).Get("pressed").Bool())
+		gamepad.X.set(gpd.Get("buttons").Index(2).Get("pressed").Bool())
+		gamepad.Y.set(gpd.Get("buttons").Index(3).Get("pressed").Bool())
+		gamepad.LeftBumper.set(gpd.Get("buttons").Index(4).Get("pressed").Bool())
+		gamepad.RightBumper.set(gpd.Get("buttons").Index(5).Get("pressed").Bool())
+		if gpd.Get("buttons").Index(6).Get("pressed").Bool() {
+			gamepad.LeftTrigger.set(1.0)
+		} else {
+			gamepad.LeftTrigger.set(0.0)
+		}
+		if gpd.Get("buttons").Index(7).Get("pressed").Bool() {
+			gamepad.RightTrigger.set(1.0)
+		} else {
+			gamepad.RightTrigger.set(0.0)
+		}
+		gamepad.Back.set(gpd.Get("buttons").Index(8).Get("pressed").Bool())
+		gamepad.Start.set(gpd.Get("buttons").Index(9).Get("pressed").Bool())
+		gamepad.LeftThumb.set(gpd.Get("buttons").Index(10).Get("pressed").Bool())
+		gamepad.RightThumb.set(gpd.Get("buttons").Index(11).Get("pressed").Bool())
+		gamepad.DpadUp.set(gpd.Get("buttons").Index(12).Get("pressed").Bool())
+		gamepad.DpadDown.set(gpd.Get("buttons").Index(13).Get("pressed").Bool())
+		gamepad.DpadLeft.set(gpd.Get("buttons").Index(14).Get("pressed").Bool())
+		gamepad.DpadRight.set(gpd.Get("buttons").Index(15).Get("pressed").Bool())
+		gamepad.Guide.set(gpd.Get("buttons").Index(16).Get("pressed").Bool())
+		gamepad.LeftX.set(float32(gpd.Get("axes").Index(0).Float()))
+		gamepad.LeftY.set(float32(gpd.Get("axes").Index(1).Float()))
+		gamepad.RightX.set(float32(gpd.Get("axes").Index(2).Float()))
+		gamepad.RightY.set(float32(gpd.Get("axes").Index(3).Float()))
+	}
+}
+
+// browserJoystickState returns the raw state of a gamepad which the browser
+// doesn't map to the standard layout. Browsers report the hats of a joystick as
+// axes or buttons, so mappings which use hats don't see them.
+func browserJoystickState(gpd js.Value) JoystickState {
+	buttons, axes := gpd.Get("buttons"), gpd.Get("axes")
+	state := JoystickState{
+		Buttons: make([]bool, buttons.Length()),
+		Axes:    make([]float32, axes.Length()),
+	}
+	for i := range state.Buttons {
+		state.Buttons[i] = buttons.Index(i).Get("pressed").Bool()
+	}
+	for i := range state.Axes {
+		state.Axes[i] = float32(axes.Index(i).Float())
 	}
+	return state
 }
@@ -0,0 +1,531 @@
+package engo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultGamepadMappings are the mappings every GamepadManager knows about, in
+// the format of the SDL_GameControllerDB. They cover a few common controllers;
+// use GamepadManager.AddMappings to load the complete database, or mappings of
+// your own.
+const defaultGamepadMappings = `# Bundled default mappings, see https://github.com/gabomdq/SDL_GameControllerDB
+xinput,XInput Controller,a:b0,b:b1,back:b6,dpdown:h0.4,dpleft:h0.8,dpright:h0.2,dpup:h0.1,guide:b10,leftshoulder:b4,leftstick:b8,lefttrigger:a2,leftx:a0,lefty:a1,rightshoulder:b5,rightstick:b9,righttrigger:a5,rightx:a3,righty:a4,start:b7,x:b2,y:b3,
+030000005e0400008e02000014010000,Xbox 360 Controller,a:b0,b:b1,back:b6,dpdown:h0.4,dpleft:h0.8,dpright:h0.2,dpup:h0.1,guide:b8,leftshoulder:b4,leftstick:b9,lefttrigger:a2,leftx:a0,lefty:a1,rightshoulder:b5,rightstick:b10,righttrigger:a5,rightx:a3,righty:a4,start:b7,x:b2,y:b3,platform:Linux,
+030000005e040000ea02000001030000,Xbox One Controller,a:b0,b:b1,back:b6,dpdown:h0.4,dpleft:h0.8,dpright:h0.2,dpup:h0.1,guide:b8,leftshoulder:b4,leftstick:b9,lefttrigger:a2,leftx:a0,lefty:a1,rightshoulder:b5,rightstick:b10,righttrigger:a5,rightx:a3,righty:a4,start:b7,x:b2,y:b3,platform:Linux,
+030000004c050000c405000011810000,PS4 Controller,a:b0,b:b1,back:b8,dpdown:h0.4,dpleft:h0.8,dpright:h0.2,dpup:h0.1,guide:b10,leftshoulder:b4,leftstick:b11,lefttrigger:a2,leftx:a0,lefty:a1,rightshoulder:b5,rightstick:b12,righttrigger:a5,rightx:a3,righty:a4,start:b9,x:b3,y:b2,platform:Linux,
+030000004c050000cc09000011810000,PS4 Controller,a:b0,b:b1,back:b8,dpdown:h0.4,dpleft:h0.8,dpright:h0.2,dpup:h0.1,guide:b10,leftshoulder:b4,leftstick:b11,lefttrigger:a2,leftx:a0,lefty:a1,rightshoulder:b5,rightstick:b12,righttrigger:a5,rightx:a3,righty:a4,start:b9,x:b3,y:b2,platform:Linux,
+030000004c050000e60c000011810000,PS5 Controller,a:b0,b:b1,back:b8,dpdown:h0.4,dpleft:h0.8,dpright:h0.2,dpup:h0.1,guide:b10,leftshoulder:b4,leftstick:b11,lefttrigger:a2,leftx:a0,lefty:a1,rightshoulder:b5,rightstick:b12,righttrigger:a5,rightx:a3,righty:a4,start:b9,x:b3,y:b2,platform:Linux,
+`
+
+var (
+	defaultMappingsOnce sync.Once
+	defaultMappings     map[string]GamepadMapping
+)
+
+// gamepadMappingTargets maps the names used by the SDL_GameControllerDB to the
+// buttons and axes of a Gamepad
+var gamepadMappingTargets = map[string]string{
+	"a":             "A",
+	"b":             "B",
+	"x":             "X",
+	"y":             "Y",
+	"back":          "Back",
+	"start":         "Start",
+	"guide":         "Guide",
+	"dpup":          "DpadUp",
+	"dpright":       "DpadRight",
+	"dpdown":        "DpadDown",
+	"dpleft":        "DpadLeft",
+	"leftshoulder":  "LeftBumper",
+	"rightshoulder": "RightBumper",
+	"leftstick":     "LeftThumb",
+	"rightstick":    "RightThumb",
+	"leftx":         "LeftX",
+	"lefty":         "LeftY",
+	"rightx":        "RightX",
+	"righty":        "RightY",
+	"lefttrigger":   "LeftTrigger",
+	"righttrigger":  "RightTrigger",
+}
+
+// mappingPlatforms maps runtime.GOOS to the platform names used by the
+// SDL_GameControllerDB
+var mappingPlatforms = map[string]string{
+	"linux":   "Linux",
+	"windows": "Windows",
+	"darwin":  "Mac OS X",
+	"android": "Android",
+	"ios":     "iOS",
+}
+
+// MappingInputKind is the kind of input of a joystick which a GamepadBinding
+// reads.
+type MappingInputKind uint8
+
+const (
+	// MappingButton reads a button of the joystick
+	MappingButton MappingInputKind = iota
+	// MappingAxis reads an axis of the joystick
+	MappingAxis
+	// MappingHat reads a direction of a hat of the joystick
+	MappingHat
+)
+
+// MappingInput is a button, axis or hat direction of a joystick, such as "b0",
+// "-a1~" or "h0.4".
+type MappingInput struct {
+	Kind MappingInputKind
+	// Index of the button, axis or hat
+	Index int
+	// HatMask is the direction of the hat, 1 for up, 2 for right, 4 for down
+	// and 8 for left
+	HatMask uint8
+	// Half is 1 or -1 to only use the positive or negative half of an axis, and
+	// 0 to use all of it
+	Half int
+	// Invert flips the axis
+	Invert bool
+}
+
+// GamepadBinding binds an input of a joystick to a button or axis of a Gamepad.
+type GamepadBinding struct {
+	// Target is the name of the button or axis of the Gamepad, such as "A" or
+	// "LeftX", see Gamepad.Button and Gamepad.Axis
+	Target string
+	// TargetHalf is 1 or -1 when the input only drives the positive or negative
+	// half of the target axis
+	TargetHalf int
+	Input      MappingInput
+}
+
+// GamepadMapping maps the buttons, axes and hats of a kind of joystick to the
+// standard layout of a Gamepad.
+type GamepadMapping struct {
+	// GUID identifies the kind of joystick
+	GUID string
+	// Name is the name of the joystick
+	Name string
+	// Platform is the platform the mapping is meant for, or empty if it works
+	// on all platforms
+	Platform string
+	Bindings []GamepadBinding
+}
+
+// JoystickState is the raw state of a joystick, which a GamepadMapping turns
+// into the state of a Gamepad.
+type JoystickState struct {
+	Buttons []bool
+	// Axes are from -1 to 1
+	Axes []float32
+	// Hats hold the directions of each hat, using the bits of
+	// MappingInput.HatMask
+	Hats []uint8
+}
+
+// ParseGamepadMapping parses a mapping in the format of the
+// SDL_GameControllerDB, such as "030000005e0400008e02000014010000,Xbox 360
+// Controller,a:b0,leftx:a0,dpup:h0.1,platform:Linux,". Fields which are not
+// part of the layout of a Gamepad, such as "misc1", are ignored.
+func ParseGamepadMapping(mapping string) (GamepadMapping, error) {
+	fields := strings.Split(strings.TrimSpace(mapping), ",")
+	if len(fields) < 2 || fields[0] == "" {
+		return GamepadMapping{}, fmt.Errorf("unable to parse gamepad mapping %q, expected a GUID and a name", mapping)
+	}
+
+	m := GamepadMapping{GUID: fields[0], Name: fields[1]}
+	for _, field := range fields[2:] {
+		if field == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(field, ":")
+		if !ok {
+			return GamepadMapping{}, fmt.Errorf("unable to parse gamepad mapping %q, field %q has no value", m.GUID, field)
+		}
+		if key == "platform" {
+			m.Platform = value
+			continue
+		}
+
+		var half int
+		switch {
+		case strings.HasPrefix(key, "+"):
+			half, key = 1, key[1:]
+		case strings.HasPrefix(key, "-"):
+			half, key = -1, key[1:]
+		}
+		target, ok := gamepadMappingTargets[key]
+		if !ok {
+			continue
+		}
+
+		input, err := parseMappingInput(value)
+		if err != nil {
+			return GamepadMapping{}, fmt.Errorf("unable to parse gamepad mapping %q, field %q: %w", m.GUID, field, err)
+		}
+		m.Bindings = append(m.Bindings, GamepadBinding{Target: target, TargetHalf: half, Input: input})
+	}
+	return m, nil
+}
+
+// parseMappingInput parses an input of a joystick, such as "b0", "-a1~" or
+// "h0.4"
+func parseMappingInput(value string) (MappingInput, error) {
+	var input MappingInput
+	switch {
+	case strings.HasPrefix(value, "+"):
+		input.Half, value = 1, value[1:]
+	case strings.HasPrefix(value, "-"):
+		input.Half, value = -1, value[1:]
+	}
+	if strings.HasSuffix(value, "~") {
+		input.Invert, value = true, value[:len(value)-1]
+	}
+	if len(value) < 2 {
+		return MappingInput{}, fmt.Errorf("invalid input %q", value)
+	}
+
+	index := value[1:]
+	switch value[0] {
+	case 'b':
+		input.Kind = MappingButton
+	case 'a':
+		input.Kind = MappingAxis
+	case 'h':
+		input.Kind = MappingHat
+		hat, mask, ok := strings.Cut(index, ".")
+		if !ok {
+			return MappingInput{}, fmt.Errorf("hat %q has no direction", value)
+		}
+		m, err := strconv.ParseUint(mask, 10, 8)
+		if err != nil {
+			return MappingInput{}, fmt.Errorf("hat %q has an invalid direction: %w", value, err)
+		}
+		input.HatMask = uint8(m)
+		index = hat
+	default:
+		return MappingInput{}, fmt.Errorf("invalid input %q", value)
+	}
+
+	i, err := strconv.Atoi(index)
+	if err != nil || i < 0 {
+		return MappingInput{}, fmt.Errorf("invalid index in input %q", value)
+	}
+	input.Index = i
+	return input, nil
+}
+
+// ParseGamepadMappings parses the mappings in a file in the format of the
+// SDL_GameControllerDB, one per line. Empty lines and comments starting with #
+// are skipped.
+func ParseGamepadMappings(r io.Reader) ([]GamepadMapping, error) {
+	var mappings []GamepadMapping
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m, err := ParseGamepadMapping(line)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, m)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read gamepad mappings: %w", err)
+	}
+	return mappings, nil
+}
+
+// value returns the value of the input, from -1 to 1 for a full axis, and from
+// 0 to 1 for buttons, hats and half axes
+func (in MappingInput) value(state JoystickState) float32 {
+	switch in.Kind {
+	case MappingButton:
+		if in.Index < len(state.Buttons) && state.Buttons[in.Index] {
+			return 1
+		}
+	case MappingHat:
+		if in.Index < len(state.Hats) && state.Hats[in.Index]&in.HatMask != 0 {
+			return 1
+		}
+	case MappingAxis:
+		if in.Index >= len(state.Axes) {
+			return 0
+		}
+		v := state.Axes[in.Index]
+		if in.Invert {
+			v = -v
+		}
+		switch in.Half {
+		case 1:
+			return clampUnit(v)
+		case -1:
+			return clampUnit(-v)
+		}
+		return v
+	}
+	return 0
+}
+
+// fullRange reports whether the input is an axis which reports values from -1
+// to 1
+func (in MappingInput) fullRange() bool {
+	return in.Kind == MappingAxis && in.Half == 0
+}
+
+func clampUnit(v float32) float32 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// Apply sets the buttons and axes of the Gamepad using the raw state of a
+// joystick, as if the Gamepad was updated by its backend.
+func (m GamepadMapping) Apply(state JoystickState, g *Gamepad) {
+	buttons := make(map[string]bool)
+	axes := make(map[string]float32)
+	for _, binding := range m.Bindings {
+		v := binding.Input.value(state)
+
+		if g.Button(binding.Target) != nil {
+			if binding.Input.fullRange() {
+				v = (v + 1) / 2
+			}
+			buttons[binding.Target] = buttons[binding.Target] || v > 0.5
+			continue
+		}
+
+		switch {
+		case binding.TargetHalf != 0:
+			// Inputs that drive half of an axis report from 0 to 1
+			if binding.Input.fullRange() {
+				v = (v + 1) / 2
+			}
+			v *= float32(binding.TargetHalf)
+		case !binding.Input.fullRange() && !isTrigger(binding.Target):
+			// Buttons and half axes driving a whole stick axis go from one end
+			// to the other
+			v = v*2 - 1
+		case binding.Input.fullRange() && isTrigger(binding.Target):
+			// Triggers report from 0 to 1
+			v = (v + 1) / 2
+		}
+		axes[binding.Target] += v
+	}
+
+	for _, name := range gamepadButtonNames {
+		g.Button(name).set(buttons[name])
+	}
+	for _, name := range gamepadAxisNames {
+		v := axes[name]
+		if v > 1 {
+			v = 1
+		} else if v < -1 {
+			v = -1
+		}
+		g.Axis(name).set(v)
+	}
+}
+
+func isTrigger(target string) bool {
+	return target == "LeftTrigger" || target == "RightTrigger"
+}
+
+// mappingPlatform is the name the SDL_GameControllerDB uses for the current
+// platform. Backends which can't tell the platform from runtime.GOOS, such as
+// browsers, set it themselves.
+var mappingPlatform = mappingPlatforms[runtime.GOOS]
+
+// currentMappingPlatform returns the name the SDL_GameControllerDB uses for the
+// current platform
+func currentMappingPlatform() string {
+	return mappingPlatform
+}
+
+// loadDefaultMappings parses the bundled mappings once
+func loadDefaultMappings() map[string]GamepadMapping {
+	defaultMappingsOnce.Do(func() {
+		defaultMappings = make(map[string]GamepadMapping)
+		mappings, err := ParseGamepadMappings(strings.NewReader(defaultGamepadMappings))
+		if err != nil {
+			panic(err)
+		}
+		for _, m := range mappings {
+			if m.Platform == "" || m.Platform == currentMappingPlatform() {
+				defaultMappings[m.GUID] = m
+			}
+		}
+	})
+	return defaultMappings
+}
+
+// AddMapping adds a mapping in the format of the SDL_GameControllerDB, which
+// replaces any mapping for the same GUID, including the bundled ones. Mappings
+// for other platforms are ignored.
+func (gm *GamepadManager) AddMapping(mapping string) error {
+	m, err := ParseGamepadMapping(mapping)
+	if err != nil {
+		return err
+	}
+	gm.addMappings([]GamepadMapping{m})
+	return gm.updateMappingsImpl(mapping)
+}
+
+// AddMappings reads mappings in the format of the SDL_GameControllerDB from r,
+// such as the gamecontrollerdb.txt file of the database, and adds them like
+// AddMapping.
+func (gm *GamepadManager) AddMappings(r io.Reader) error {
+	var text strings.Builder
+	mappings, err := ParseGamepadMappings(io.TeeReader(r, &text))
+	if err != nil {
+		return err
+	}
+	gm.addMappings(mappings)
+	return gm.updateMappingsImpl(text.String())
+}
+
+func (gm *GamepadManager) addMappings(mappings []GamepadMapping) {
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+	if gm.mappings == nil {
+		gm.mappings = make(map[string]GamepadMapping)
+	}
+	gm.idMappings = nil
+	for _, m := range mappings {
+		if m.Platform == "" || m.Platform == currentMappingPlatform() {
+			gm.mappings[m.GUID] = m
+		}
+	}
+}
+
+// Mapping returns the mapping for the joystick with the given GUID, which was
+// either added or bundled.
+func (gm *GamepadManager) Mapping(guid string) (GamepadMapping, bool) {
+	gm.mutex.RLock()
+	m, ok := gm.mappings[guid]
+	gm.mutex.RUnlock()
+	if ok {
+		return m, true
+	}
+	m, ok = loadDefaultMappings()[guid]
+	return m, ok
+}
+
+// MappingForProduct returns the mapping for a joystick with the given USB
+// vendor and product ID, which was either added or bundled. It is used when the
+// GUID of a joystick isn't known, such as in browsers, which only report the
+// vendor and product. Added mappings are preferred over the bundled ones.
+func (gm *GamepadManager) MappingForProduct(vendor, product uint16) (GamepadMapping, bool) {
+	gm.mutex.RLock()
+	m, ok := findMappingForProduct(gm.mappings, vendor, product)
+	gm.mutex.RUnlock()
+	if ok {
+		return m, true
+	}
+	return findMappingForProduct(loadDefaultMappings(), vendor, product)
+}
+
+// mappingForBrowserID returns the mapping for a gamepad with the given id in a
+// browser, or nil if there is none. The results are cached until mappings are
+// added.
+func (gm *GamepadManager) mappingForBrowserID(id string) *GamepadMapping {
+	gm.mutex.RLock()
+	m, ok := gm.idMappings[id]
+	gm.mutex.RUnlock()
+	if ok {
+		return m
+	}
+
+	if vendor, product, valid := parseBrowserGamepadID(id); valid {
+		if found, ok := gm.MappingForProduct(vendor, product); ok {
+			m = &found
+		}
+	}
+	gm.mutex.Lock()
+	if gm.idMappings == nil {
+		gm.idMappings = make(map[string]*GamepadMapping)
+	}
+	gm.idMappings[id] = m
+	gm.mutex.Unlock()
+	return m
+}
+
+// findMappingForProduct returns the mapping with the given vendor and product
+// in its GUID. When several mappings match, the one with the lowest GUID is
+// returned, so the result doesn't depend on the order of the map.
+func findMappingForProduct(mappings map[string]GamepadMapping, vendor, product uint16) (GamepadMapping, bool) {
+	var found GamepadMapping
+	ok := false
+	for guid, m := range mappings {
+		v, p, valid := guidProduct(guid)
+		if !valid || v != vendor || p != product {
+			continue
+		}
+		if !ok || guid < found.GUID {
+			found, ok = m, true
+		}
+	}
+	return found, ok
+}
+
+// guidProduct returns the USB vendor and product ID in a GUID of the
+// SDL_GameControllerDB, which holds them as little endian numbers, after the
+// bus type and a checksum
+func guidProduct(guid string) (vendor, product uint16, ok bool) {
+	if len(guid) != 32 {
+		return 0, 0, false
+	}
+	v, err := strconv.ParseUint(guid[10:12]+guid[8:10], 16, 16)
+	if err != nil {
+		return 0, 0, false
+	}
+	p, err := strconv.ParseUint(guid[18:20]+guid[16:18], 16, 16)
+	if err != nil {
+		return 0, 0, false
+	}
+	return uint16(v), uint16(p), true
+}
+
+// parseBrowserGamepadID returns the USB vendor and product ID in the id of a
+// gamepad in a browser. Chrome reports them like "USB Gamepad (Vendor: 0079
+// Product: 0011)", while Firefox starts the id with them, like "79-11-USB
+// Gamepad".
+func parseBrowserGamepadID(id string) (vendor, product uint16, ok bool) {
+	if i := strings.Index(id, "Vendor: "); i >= 0 {
+		rest := id[i+len("Vendor: "):]
+		v, after, found := strings.Cut(rest, " Product: ")
+		if !found || len(after) < 4 {
+			return 0, 0, false
+		}
+		return parseProductIDs(v, after[:4])
+	}
+
+	parts := strings.SplitN(id, "-", 3)
+	if len(parts) < 3 {
+		return 0, 0, false
+	}
+	return parseProductIDs(parts[0], parts[1])
+}
+
+func parseProductIDs(vendor, product string) (uint16, uint16, bool) {
+	v, err := strconv.ParseUint(vendor, 16, 16)
+	if err != nil {
+		return 0, 0, false
+	}
+	p, err := strconv.ParseUint(product, 16, 16)
+	if err != nil {
+		return 0, 0, false
+	}
+	return uint16(v), uint16(p), true
+}
@@ -0,0 +1,188 @@
+package engo
+
+import (
+	"strings"
+	"testing"
+)
+
+const testGamepadMapping = "0300000000000000aaaa000000000000,Test Pad,a:b1,b:b0,back:b8,dpup:h0.1,dpright:h0.2,leftx:a0,lefty:a1~,lefttrigger:a2,righttrigger:b7,+rightx:b4,-rightx:b5,righty:-a3,misc1:b12,platform:Linux,"
+
+func TestParseGamepadMapping(t *testing.T) {
+	m, err := ParseGamepadMapping(testGamepadMapping)
+	if err != nil {
+		t.Fatalf("Unable to parse mapping: %v", err)
+	}
+	if m.GUID != "0300000000000000aaaa000000000000" || m.Name != "Test Pad" || m.Platform != "Linux" {
+		t.Errorf("GUID, name or platform were not parsed, got %q, %q and %q", m.GUID, m.Name, m.Platform)
+	}
+	if len(m.Bindings) != 12 {
+		t.Fatalf("Expected 12 bindings, the unknown misc1 being ignored, got %d", len(m.Bindings))
+	}
+
+	expected := map[string]GamepadBinding{
+		"A":      {Target: "A", Input: MappingInput{Kind: MappingButton, Index: 1}},
+		"DpadUp": {Target: "DpadUp", Input: MappingInput{Kind: MappingHat, Index: 0, HatMask: 1}},
+		"LeftY":  {Target: "LeftY", Input: MappingInput{Kind: MappingAxis, Index: 1, Invert: true}},
+		"RightY": {Target: "RightY", Input: MappingInput{Kind: MappingAxis, Index: 3, Half: -1}},
+	}
+	for _, binding := range m.Bindings {
+		if e, ok := expected[binding.Target]; ok && e != binding {
+			t.Errorf("Expected binding %+v, got %+v", e, binding)
+		}
+	}
+	if m.Bindings[10].Target != "RightX" || m.Bindings[10].TargetHalf != -1 {
+		t.Errorf("-rightx should have bound the negative half of RightX, got %+v", m.Bindings[10])
+	}
+}
+
+func TestParseGamepadMappingErrors(t *testing.T) {
+	for _, mapping := range []string{
+		"",
+		"guid",
+		"guid,name,a",
+		"guid,name,a:x0",
+		"guid,name,a:b",
+		"guid,name,dpup:h0",
+		"guid,name,dpup:h0.up",
+		"guid,name,leftx:a-1",
+	} {
+		if _, err := ParseGamepadMapping(mapping); err == nil {
+			t.Errorf("Parsing %q should have failed", mapping)
+		}
+	}
+}
+
+func TestGamepadMappingApply(t *testing.T) {
+	m, err := ParseGamepadMapping(testGamepadMapping)
+	if err != nil {
+		t.Fatalf("Unable to parse mapping: %v", err)
+	}
+
+	g := &Gamepad{}
+	state := JoystickState{
+		Buttons: []bool{false, true, false, false, false, true, false, true},
+		Axes:    []float32{0.5, 0.25, 0, 0.5},
+		Hats:    []uint8{1 | 2},
+	}
+	m.Apply(state, g)
+	m.Apply(state, g)
+
+	if !g.A.Down() || g.B.Down() || g.Back.Down() {
+		t.Error("Only the A button should have been down, using button 1")
+	}
+	if !g.DpadUp.Down() || !g.DpadRight.Down() || g.DpadDown.Down() {
+		t.Error("Up and right on the hat should have pressed DpadUp and DpadRight")
+	}
+	checks := []struct {
+		name     string
+		expected float32
+	}{
+		{"LeftX", 0.5},
+		{"LeftY", -0.25},
+		// A full axis drives a trigger from 0 to 1
+		{"LeftTrigger", 0.5},
+		{"RightTrigger", 1},
+		// Button 5 drives the negative half of RightX
+		{"RightX", -1},
+		// Only the negative half of axis 3 drives RightY
+		{"RightY", -1},
+	}
+	for _, c := range checks {
+		if v := g.Axis(c.name).Value(); v != c.expected {
+			t.Errorf("Expected %s to be %v, got %v", c.name, c.expected, v)
+		}
+	}
+
+	state.Axes[3] = -0.5
+	m.Apply(state, g)
+	if v := g.RightY.Value(); v != 0 {
+		t.Errorf("Half an axis tilted halfway should have centered RightY, got %v", v)
+	}
+	m.Apply(JoystickState{}, g)
+	if !g.A.JustReleased() || g.LeftX.Value() != 0 {
+		t.Error("Missing inputs should have been released and centered")
+	}
+}
+
+func TestGamepadManagerMappings(t *testing.T) {
+	gm := NewGamepadManager()
+	if _, ok := gm.Mapping("xinput"); !ok {
+		t.Error("Bundled mapping for XInput should have been available")
+	}
+	if _, ok := gm.Mapping("0300000000000000aaaa000000000000"); ok {
+		t.Error("Mapping should not have been available before adding it")
+	}
+
+	file := "# Comment\n\n" + strings.Replace(testGamepadMapping, "platform:Linux,", "", 1) + "\n" +
+		"xinput,Custom XInput,a:b3,\n" +
+		"0300000000000000bbbb000000000000,Other Platform,a:b0,platform:Unknown,\n"
+	if err := gm.AddMappings(strings.NewReader(file)); err != nil {
+		t.Fatalf("Unable to add mappings: %v", err)
+	}
+	if m, ok := gm.Mapping("0300000000000000aaaa000000000000"); !ok || m.Name != "Test Pad" {
+		t.Errorf("Added mapping should have been available, got %+v", m)
+	}
+	if m, _ := gm.Mapping("xinput"); m.Name != "Custom XInput" {
+		t.Errorf("Added mapping should have replaced the bundled one, got %q", m.Name)
+	}
+	if _, ok := gm.Mapping("0300000000000000bbbb000000000000"); ok {
+		t.Error("Mapping for another platform should have been ignored")
+	}
+
+	if err := gm.AddMappings(strings.NewReader("guid,name,a:q1\n")); err == nil {
+		t.Error("Adding an invalid mapping should have failed")
+	}
+}
+
+func TestParseBrowserGamepadID(t *testing.T) {
+	data := []struct {
+		id              string
+		vendor, product uint16
+		ok              bool
+	}{
+		{"USB Gamepad (Vendor: 0079 Product: 0011)", 0x0079, 0x0011, true},
+		{"Wireless Controller (STANDARD GAMEPAD Vendor: 054c Product: 09cc)", 0x054c, 0x09cc, true},
+		{"79-11-USB Gamepad", 0x0079, 0x0011, true},
+		{"054c-09cc-Wireless Controller", 0x054c, 0x09cc, true},
+		{"Xbox 360 Controller (XInput STANDARD GAMEPAD)", 0, 0, false},
+		{"USB Gamepad (Vendor: zz Product: 0011)", 0, 0, false},
+	}
+	for _, d := range data {
+		vendor, product, ok := parseBrowserGamepadID(d.id)
+		if vendor != d.vendor || product != d.product || ok != d.ok {
+			t.Errorf("Parsing %q should have returned %04x, %04x and %v, got %04x, %04x and %v", d.id, d.vendor,
+				d.product, d.ok, vendor, product, ok)
+		}
+	}
+}
+
+func TestGamepadManagerMappingForProduct(t *testing.T) {
+	gm := NewGamepadManager()
+	if m := gm.mappingForBrowserID("USB Gamepad (Vendor: 0079 Product: 0011)"); m != nil {
+		t.Errorf("No mapping should have been found before adding it, got %+v", m)
+	}
+
+	if err := gm.AddMapping("03000000790000001100000010010000,Retro Pad,a:b1,b:b2,leftx:a0,"); err != nil {
+		t.Fatalf("Unable to add mapping: %v", err)
+	}
+	if m, ok := gm.MappingForProduct(0x0079, 0x0011); !ok || m.Name != "Retro Pad" {
+		t.Errorf("Mapping should have been found by its vendor and product, got %+v", m)
+	}
+	if _, ok := gm.MappingForProduct(0x0079, 0x0012); ok {
+		t.Error("Mapping of another product should not have been found")
+	}
+
+	// Adding mappings clears the cache of the ids which had none
+	for _, id := range []string{"USB Gamepad (Vendor: 0079 Product: 0011)", "79-11-USB Gamepad"} {
+		m := gm.mappingForBrowserID(id)
+		if m == nil || m.Name != "Retro Pad" {
+			t.Fatalf("Mapping should have been found for %q, got %+v", id, m)
+		}
+
+		g := &Gamepad{}
+		m.Apply(JoystickState{Buttons: []bool{false, true}, Axes: []float32{-0.5}}, g)
+		if !g.A.currentState || g.LeftX.Value() != -0.5 {
+			t.Errorf("Mapping should have normalized the joystick, got %+v", g)
+		}
+	}
+}
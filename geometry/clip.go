@@ -0,0 +1,465 @@
+package geometry
+
+import (
+	"math"
+	"sort"
+
+	"github.com/EngoEngine/engo"
+)
+
+// ClipOperation is a boolean operation which combines two polygons.
+type ClipOperation uint8
+
+const (
+	// ClipIntersection keeps the area which is covered by both polygons.
+	ClipIntersection ClipOperation = iota
+	// ClipUnion keeps the area which is covered by either polygon.
+	ClipUnion
+	// ClipDifference keeps the area which is covered by the subject, but not
+	// by the clip polygon.
+	ClipDifference
+)
+
+// edgeSide tells where a piece of an edge lies compared to the other polygon
+type edgeSide uint8
+
+const (
+	sideOutside edgeSide = iota
+	sideInside
+	// sideSame and sideOpposite are for pieces which lie on an edge of the
+	// other polygon, going the same or the opposite way
+	sideSame
+	sideOpposite
+)
+
+// vec is a point used while clipping, with the precision of a float64
+type vec struct {
+	x, y float64
+}
+
+// clipEdge is a directed piece of an edge which is part of the result
+type clipEdge struct {
+	from, to vec
+	used     bool
+}
+
+// Intersection returns the polygons which cover the area covered by both a
+// and b.
+func Intersection(a, b Polygon) []Polygon {
+	return Clip(a, b, ClipIntersection)
+}
+
+// Union returns the polygons which cover the area covered by either a or b.
+func Union(a, b Polygon) []Polygon {
+	return Clip(a, b, ClipUnion)
+}
+
+// Difference returns the polygons which cover the area covered by a, but not
+// by b.
+func Difference(a, b Polygon) []Polygon {
+	return Clip(a, b, ClipDifference)
+}
+
+// Clip combines the subject and the clip polygon using the boolean operation,
+// and returns the resulting polygons, with the Winding of the subject.
+// Polygons may be concave, and may touch or share edges.
+//
+// Polygons can't have holes, so a hole in the result is returned as a
+// separate polygon with the opposite Winding, after the polygon it is in.
+func Clip(subject, clip Polygon, op ClipOperation) []Polygon {
+	if len(subject) < 3 {
+		if op == ClipUnion && len(clip) >= 3 {
+			return []Polygon{clip}
+		}
+		return nil
+	}
+	if len(clip) < 3 {
+		if op == ClipIntersection {
+			return nil
+		}
+		return []Polygon{subject}
+	}
+
+	// Points closer than epsilon are the same point
+	var scale float64
+	for _, p := range [][]engo.Point{subject, clip} {
+		for _, point := range p {
+			scale = math.Max(scale, math.Max(math.Abs(float64(point.X)), math.Abs(float64(point.Y))))
+		}
+	}
+	epsilon := 1e-6 * (scale + 1)
+
+	s, c := clipPoints(subject), clipPoints(clip)
+	snap(c, s, epsilon)
+	s, c = dedupeVecs(s), dedupeVecs(c)
+	if len(s) < 3 || len(c) < 3 {
+		// The clip polygon has no area left once snapped to the subject
+		if op == ClipIntersection {
+			return nil
+		}
+		return []Polygon{subject}
+	}
+
+	sPieces, cPieces := split(s, c, epsilon)
+	var edges []clipEdge
+	for _, piece := range sPieces {
+		switch classify(piece, c, epsilon) {
+		case sideInside:
+			if op == ClipIntersection {
+				edges = append(edges, piece)
+			}
+		case sideOutside:
+			if op != ClipIntersection {
+				edges = append(edges, piece)
+			}
+		case sideSame:
+			if op != ClipDifference {
+				edges = append(edges, piece)
+			}
+		case sideOpposite:
+			if op == ClipDifference {
+				edges = append(edges, piece)
+			}
+		}
+	}
+	// Pieces of the clip polygon on the subject were added above already
+	for _, piece := range cPieces {
+		switch classify(piece, s, epsilon) {
+		case sideInside:
+			if op == ClipIntersection {
+				edges = append(edges, piece)
+			} else if op == ClipDifference {
+				edges = append(edges, clipEdge{from: piece.to, to: piece.from})
+			}
+		case sideOutside:
+			if op == ClipUnion {
+				edges = append(edges, piece)
+			}
+		}
+	}
+
+	return arrange(chain(edges, epsilon), subject.Winding(), epsilon)
+}
+
+// clipPoints returns the points of the polygon, going around it with a
+// positive signed area
+func clipPoints(p Polygon) []vec {
+	points := make([]vec, len(p))
+	for i, point := range p {
+		points[i] = vec{float64(point.X), float64(point.Y)}
+	}
+	if p.SignedArea() < 0 {
+		for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+			points[i], points[j] = points[j], points[i]
+		}
+	}
+	return points
+}
+
+// snap moves the points which lie close to a point of the other polygon
+// onto it, so they are the same when the edges are joined
+func snap(points, other []vec, epsilon float64) {
+	for i, p := range points {
+		for _, o := range other {
+			if distance(p, o) < epsilon {
+				points[i] = o
+				break
+			}
+		}
+	}
+}
+
+// dedupeVecs removes the points which are equal to the one before them
+func dedupeVecs(points []vec) []vec {
+	var result []vec
+	for _, p := range points {
+		if len(result) == 0 || p != result[len(result)-1] {
+			result = append(result, p)
+		}
+	}
+	for len(result) > 1 && result[0] == result[len(result)-1] {
+		result = result[:len(result)-1]
+	}
+	return result
+}
+
+// split cuts the edges of both polygons where they cross or touch the other
+// polygon, and returns the pieces
+func split(a, b []vec, epsilon float64) (aPieces, bPieces []clipEdge) {
+	aCuts, bCuts := make([][]vec, len(a)), make([][]vec, len(b))
+	for i := range a {
+		a1, a2 := a[i], a[(i+1)%len(a)]
+		for j := range b {
+			b1, b2 := b[j], b[(j+1)%len(b)]
+			if onEdge(b1, a1, a2, epsilon) {
+				aCuts[i] = append(aCuts[i], b1)
+			}
+			if onEdge(a1, b1, b2, epsilon) {
+				bCuts[j] = append(bCuts[j], a1)
+			}
+			if p, ok := crossing(a1, a2, b1, b2, epsilon); ok {
+				// The same point goes into both, so the pieces can be joined
+				aCuts[i] = append(aCuts[i], p)
+				bCuts[j] = append(bCuts[j], p)
+			}
+		}
+	}
+	return pieces(a, aCuts, epsilon), pieces(b, bCuts, epsilon)
+}
+
+// pieces returns the edges of the polygon, cut at the given points
+func pieces(points []vec, cuts [][]vec, epsilon float64) []clipEdge {
+	var result []clipEdge
+	for i, from := range points {
+		to := points[(i+1)%len(points)]
+		along := cuts[i]
+		sort.Slice(along, func(k, l int) bool {
+			return distance(from, along[k]) < distance(from, along[l])
+		})
+		for _, cut := range along {
+			if distance(from, cut) < epsilon || distance(cut, to) < epsilon {
+				continue
+			}
+			result = append(result, clipEdge{from: from, to: cut})
+			from = cut
+		}
+		result = append(result, clipEdge{from: from, to: to})
+	}
+	return result
+}
+
+// onEdge reports whether p lies on the edge from a to b, other than at its
+// ends
+func onEdge(p, a, b vec, epsilon float64) bool {
+	if distance(p, a) < epsilon || distance(p, b) < epsilon {
+		return false
+	}
+	return segmentDistance(p, a, b) < epsilon
+}
+
+// crossing returns the point where the edges a1-a2 and b1-b2 cross, if they
+// do so away from their ends
+func crossing(a1, a2, b1, b2 vec, epsilon float64) (vec, bool) {
+	dx1, dy1 := a2.x-a1.x, a2.y-a1.y
+	dx2, dy2 := b2.x-b1.x, b2.y-b1.y
+	denominator := dx1*dy2 - dy1*dx2
+	if denominator == 0 {
+		return vec{}, false
+	}
+	ox, oy := b1.x-a1.x, b1.y-a1.y
+	alphaA := (ox*dy2 - oy*dx2) / denominator
+	alphaB := (ox*dy1 - oy*dx1) / denominator
+	if alphaA <= 0 || alphaA >= 1 || alphaB <= 0 || alphaB >= 1 {
+		return vec{}, false
+	}
+	p := vec{a1.x + alphaA*dx1, a1.y + alphaA*dy1}
+	for _, end := range []vec{a1, a2, b1, b2} {
+		if distance(p, end) < epsilon {
+			// Points on an edge are cut by onEdge
+			return vec{}, false
+		}
+	}
+	return p, true
+}
+
+// classify returns where the piece lies compared to the polygon
+func classify(piece clipEdge, polygon []vec, epsilon float64) edgeSide {
+	mid := vec{(piece.from.x + piece.to.x) / 2, (piece.from.y + piece.to.y) / 2}
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		if segmentDistance(mid, a, b) < epsilon {
+			if (piece.to.x-piece.from.x)*(b.x-a.x)+(piece.to.y-piece.from.y)*(b.y-a.y) > 0 {
+				return sideSame
+			}
+			return sideOpposite
+		}
+	}
+	if containsVec(polygon, mid) {
+		return sideInside
+	}
+	return sideOutside
+}
+
+// chain joins the edges into closed loops. Where several edges leave the same
+// point, the one turning furthest towards the inside is taken, so polygons
+// which touch in a point are kept apart.
+func chain(edges []clipEdge, epsilon float64) [][]vec {
+	leaving := make(map[vec][]int)
+	for i, e := range edges {
+		leaving[e.from] = append(leaving[e.from], i)
+	}
+
+	var loops [][]vec
+	for first := range edges {
+		if edges[first].used {
+			continue
+		}
+		edges[first].used = true
+		loop := []vec{edges[first].from}
+		current := first
+		for {
+			e := edges[current]
+			next, best := -1, -math.MaxFloat64
+			for _, i := range leaving[e.to] {
+				if edges[i].used && i != first {
+					continue
+				}
+				turn := turnAngle(e, edges[i])
+				if turn > best {
+					next, best = i, turn
+				}
+			}
+			if next == -1 || next == first {
+				break
+			}
+			edges[next].used = true
+			loop = append(loop, edges[next].from)
+			current = next
+		}
+		if edges[current].to == edges[first].from {
+			loops = append(loops, simplify(loop, epsilon))
+		}
+	}
+	return loops
+}
+
+// turnAngle returns the angle from the direction of a to the one of b, which
+// is positive when turning towards the inside
+func turnAngle(a, b clipEdge) float64 {
+	ax, ay := a.to.x-a.from.x, a.to.y-a.from.y
+	bx, by := b.to.x-b.from.x, b.to.y-b.from.y
+	return math.Atan2(ax*by-ay*bx, ax*bx+ay*by)
+}
+
+// simplify removes the points in the middle of a straight line, which are
+// left over where edges were cut
+func simplify(loop []vec, epsilon float64) []vec {
+	for removed := true; removed && len(loop) >= 3; {
+		removed = false
+		for i := range loop {
+			prev, next := loop[(i+len(loop)-1)%len(loop)], loop[(i+1)%len(loop)]
+			if segmentDistance(loop[i], prev, next) < epsilon {
+				loop = append(loop[:i:i], loop[i+1:]...)
+				removed = true
+				break
+			}
+		}
+	}
+	return loop
+}
+
+// arrange turns the loops into polygons with the given winding, and puts
+// every hole after the polygon it is in
+func arrange(loops [][]vec, winding Winding, epsilon float64) []Polygon {
+	var outers, holes [][]vec
+	for _, loop := range loops {
+		if area := signedArea(loop); area > 0 {
+			outers = append(outers, loop)
+		} else if area < 0 {
+			holes = append(holes, loop)
+		}
+	}
+
+	inside := make([][][]vec, len(outers))
+	for _, hole := range holes {
+		in := -1
+		for i, outer := range outers {
+			if holeIn(hole, outer, epsilon) && (in == -1 || signedArea(outer) < signedArea(outers[in])) {
+				in = i
+			}
+		}
+		if in != -1 {
+			inside[in] = append(inside[in], hole)
+		}
+	}
+
+	var results []Polygon
+	for i, outer := range outers {
+		for _, loop := range append([][]vec{outer}, inside[i]...) {
+			polygon := make(Polygon, len(loop))
+			for i, p := range loop {
+				polygon[i] = engo.Point{X: float32(p.x), Y: float32(p.y)}
+			}
+			if polygon = dedupe(polygon); len(polygon) < 3 {
+				continue
+			}
+			if winding != Clockwise {
+				polygon = polygon.Reverse()
+			}
+			results = append(results, polygon)
+		}
+	}
+	return results
+}
+
+// holeIn reports whether the hole lies in the outer polygon, which it may
+// touch
+func holeIn(hole, outer []vec, epsilon float64) bool {
+	for _, p := range hole {
+		onBorder := false
+		for i, a := range outer {
+			if segmentDistance(p, a, outer[(i+1)%len(outer)]) < epsilon {
+				onBorder = true
+				break
+			}
+		}
+		if !onBorder {
+			return containsVec(outer, p)
+		}
+	}
+	return false
+}
+
+// signedArea returns the area of the loop, which is positive if it goes around
+// the same way as the polygons being clipped
+func signedArea(loop []vec) float64 {
+	var area float64
+	for i, a := range loop {
+		b := loop[(i+1)%len(loop)]
+		area += a.x*b.y - b.x*a.y
+	}
+	return area / 2
+}
+
+// containsVec reports whether the point lies within the polygon
+func containsVec(polygon []vec, p vec) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.y > p.y) != (b.y > p.y) && p.x < (b.x-a.x)*(p.y-a.y)/(b.y-a.y)+a.x {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// distance returns the distance between a and b
+func distance(a, b vec) float64 {
+	return math.Hypot(b.x-a.x, b.y-a.y)
+}
+
+// segmentDistance returns the distance from p to the segment from a to b
+func segmentDistance(p, a, b vec) float64 {
+	dx, dy := b.x-a.x, b.y-a.y
+	length := dx*dx + dy*dy
+	if length == 0 {
+		return distance(p, a)
+	}
+	t := ((p.x-a.x)*dx + (p.y-a.y)*dy) / length
+	t = math.Max(0, math.Min(1, t))
+	return distance(p, vec{a.x + t*dx, a.y + t*dy})
+}
+
+// dedupe removes the points which are equal to the one before them
+func dedupe(p Polygon) Polygon {
+	var result Polygon
+	for _, point := range p {
+		if len(result) == 0 || point != result[len(result)-1] {
+			result = append(result, point)
+		}
+	}
+	for len(result) > 1 && result[0] == result[len(result)-1] {
+		result = result[:len(result)-1]
+	}
+	return result
+}
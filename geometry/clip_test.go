@@ -0,0 +1,131 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/math"
+)
+
+// totalArea returns the area covered by the polygons, subtracting the holes,
+// which have the opposite winding of the first polygon
+func totalArea(polygons []Polygon) float32 {
+	var area float32
+	for _, p := range polygons {
+		if p.Winding() == polygons[0].Winding() {
+			area += p.Area()
+		} else {
+			area -= p.Area()
+		}
+	}
+	return area
+}
+
+func offset(p Polygon, x, y float32) Polygon {
+	moved := make(Polygon, len(p))
+	for i, point := range p {
+		moved[i] = engo.Point{X: point.X + x, Y: point.Y + y}
+	}
+	return moved
+}
+
+func TestClip(t *testing.T) {
+	// uShape is open at the top, and bar closes it, leaving a hole
+	uShape := Polygon{
+		{X: 0, Y: 0}, {X: 3, Y: 0}, {X: 3, Y: 3}, {X: 2, Y: 3},
+		{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 3}, {X: 0, Y: 3},
+	}
+	bar := Polygon{{X: -1, Y: 2}, {X: 4, Y: 2}, {X: 4, Y: 2.5}, {X: -1, Y: 2.5}}
+
+	data := []struct {
+		name          string
+		subject, clip Polygon
+		op            ClipOperation
+		count         int
+		area          float32
+	}{
+		{"overlapping intersection", square, offset(square, 1, 1), ClipIntersection, 1, 1},
+		{"overlapping union", square, offset(square, 1, 1), ClipUnion, 1, 7},
+		{"overlapping difference", square, offset(square, 1, 1), ClipDifference, 1, 3},
+		{"opposite windings", square, offset(square, 1, 1).Reverse(), ClipIntersection, 1, 1},
+		{"concave intersection", uShape, bar, ClipIntersection, 2, 1},
+		{"union with a hole", uShape, bar, ClipUnion, 2, 8.5},
+		{"concave difference", uShape, bar, ClipDifference, 3, 6},
+		{"disjoint intersection", square, offset(square, 5, 0), ClipIntersection, 0, 0},
+		{"disjoint union", square, offset(square, 5, 0), ClipUnion, 2, 8},
+		{"disjoint difference", square, offset(square, 5, 0), ClipDifference, 1, 4},
+		{"contained intersection", lShape, Polygon{{X: 0.25, Y: 0.25}, {X: 0.75, Y: 0.25}, {X: 0.5, Y: 0.75}}, ClipIntersection, 1, 0.125},
+		{"difference with a hole", square, Polygon{{X: 0.5, Y: 0.5}, {X: 1.5, Y: 0.5}, {X: 1.5, Y: 1.5}, {X: 0.5, Y: 1.5}}, ClipDifference, 2, 3},
+		{"within the clip polygon", Polygon{{X: 0.5, Y: 0.5}, {X: 1.5, Y: 0.5}, {X: 1.5, Y: 1.5}}, square, ClipDifference, 0, 0},
+		// Polygons touching each other
+		{"shared edge intersection", square, offset(square, 2, 0), ClipIntersection, 0, 0},
+		{"shared edge union", square, offset(square, 2, 0), ClipUnion, 1, 8},
+		{"same polygon", lShape, lShape, ClipIntersection, 1, 3},
+		{"same polygon difference", lShape, lShape, ClipDifference, 0, 0},
+		{"touching corners union", square, offset(square, 2, 2), ClipUnion, 2, 8},
+		{"shared corner", lShape, offset(square, 1, 1), ClipDifference, 1, 3},
+	}
+
+	for _, d := range data {
+		res := Clip(d.subject, d.clip, d.op)
+		if len(res) != d.count {
+			t.Errorf("%s: expected %d polygons, got %v", d.name, d.count, res)
+			continue
+		}
+		if len(res) == 0 {
+			continue
+		}
+		if area := totalArea(res); math.Abs(area-d.area) > 0.001 {
+			t.Errorf("%s: expected an area of %v, got %v for %v", d.name, d.area, area, res)
+		}
+		if res[0].Winding() != d.subject.Winding() {
+			t.Errorf("%s: result should have had the winding of the subject, got %v", d.name, res)
+		}
+	}
+
+	if res := Intersection(square, offset(square, 1, 1)); len(res) != 1 || !res[0].Contains(engo.Point{X: 1.5, Y: 1.5}) {
+		t.Errorf("Intersection should have covered the overlapping part, got %v", res)
+	}
+	if res := Union(nil, square); len(res) != 1 {
+		t.Errorf("Union with an empty polygon should have returned the other one, got %v", res)
+	}
+	if res := Difference(square, nil); len(res) != 1 {
+		t.Errorf("Difference with an empty polygon should have returned the subject, got %v", res)
+	}
+}
+
+func TestClipPixelScale(t *testing.T) {
+	big := Polygon{{X: 0, Y: 0}, {X: 500, Y: 0}, {X: 500, Y: 500}, {X: 0, Y: 500}}
+	notch := Polygon{{X: 490, Y: 100}, {X: 500, Y: 100}, {X: 500, Y: 110}, {X: 490, Y: 110}}
+
+	data := []struct {
+		name          string
+		subject, clip Polygon
+		op            ClipOperation
+		count         int
+		area          float32
+	}{
+		{"overlapping intersection", big, offset(big, 490, 490), ClipIntersection, 1, 100},
+		{"overlapping difference", big, offset(big, 490, 490), ClipDifference, 1, 249900},
+		{"overlapping union", big, offset(big, 490, 490), ClipUnion, 1, 499900},
+		// The notch lies on the edge of the square
+		{"notch intersection", big, notch, ClipIntersection, 1, 100},
+		{"notch difference", big, notch, ClipDifference, 1, 249900},
+		{"notch union", big, notch, ClipUnion, 1, 250000},
+	}
+
+	for _, d := range data {
+		res := Clip(d.subject, d.clip, d.op)
+		if len(res) != d.count {
+			t.Errorf("%s: expected %d polygons, got %v", d.name, d.count, res)
+			continue
+		}
+		if area := totalArea(res); math.Abs(area-d.area) > 0.1 {
+			t.Errorf("%s: expected an area of %v, got %v for %v", d.name, d.area, area, res)
+		}
+	}
+
+	if res := Difference(big, notch); len(res) != 1 || len(res[0]) != 8 {
+		t.Errorf("Difference should have cut the notch out of the edge, got %v", res)
+	}
+}
@@ -0,0 +1,42 @@
+package geometry
+
+import (
+	"sort"
+
+	"github.com/EngoEngine/engo"
+)
+
+// ConvexHull returns the smallest convex Polygon which contains all of the
+// points, with a Clockwise winding. Points which lie on its edges are left out.
+func ConvexHull(points []engo.Point) Polygon {
+	sorted := make([]engo.Point, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+	if len(sorted) < 3 {
+		return Polygon(sorted)
+	}
+
+	// Andrew's monotone chain builds the lower and the upper half of the hull,
+	// dropping every corner which doesn't turn clockwise
+	hull := make(Polygon, 0, 2*len(sorted))
+	for _, p := range sorted {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(sorted) - 2; i >= 0; i-- {
+		p := sorted[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	return hull[:len(hull)-1]
+}
@@ -0,0 +1,212 @@
+// Package geometry contains algorithms for working with polygons, such as
+// triangulating them for drawing, or splitting them into convex parts for
+// collision detection.
+package geometry
+
+import (
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/math"
+)
+
+// Winding is the direction in which the points of a Polygon go around it.
+type Winding uint8
+
+const (
+	// Clockwise is the winding of a Polygon of which the points go around
+	// clockwise on screen, where the y axis points down. Its signed area is
+	// positive.
+	Clockwise Winding = iota
+	// CounterClockwise is the winding of a Polygon of which the points go
+	// around counter-clockwise on screen. Its signed area is negative.
+	CounterClockwise
+)
+
+// Polygon is a simple polygon, made of the points in order, where the last
+// point connects back to the first one. The edges of a simple polygon don't
+// cross each other.
+type Polygon []engo.Point
+
+// SignedArea returns the area of the Polygon, which is positive if its Winding
+// is Clockwise, and negative if it is CounterClockwise.
+func (p Polygon) SignedArea() float32 {
+	var area float32
+	for i := range p {
+		a, b := p[i], p[(i+1)%len(p)]
+		area += a.X*b.Y - b.X*a.Y
+	}
+	return area / 2
+}
+
+// Area returns the area of the Polygon.
+func (p Polygon) Area() float32 {
+	return math.Abs(p.SignedArea())
+}
+
+// Winding returns the direction in which the points go around the Polygon.
+func (p Polygon) Winding() Winding {
+	if p.SignedArea() < 0 {
+		return CounterClockwise
+	}
+	return Clockwise
+}
+
+// Reverse returns a copy of the Polygon with its points in the opposite order,
+// which flips its Winding.
+func (p Polygon) Reverse() Polygon {
+	r := make(Polygon, len(p))
+	for i, point := range p {
+		r[len(p)-1-i] = point
+	}
+	return r
+}
+
+// WithWinding returns the Polygon with the given Winding, reversing a copy of
+// it if needed.
+func (p Polygon) WithWinding(w Winding) Polygon {
+	if p.Winding() != w {
+		return p.Reverse()
+	}
+	return p
+}
+
+// Centroid returns the center of mass of the Polygon. The average of its points
+// is returned if it has no area.
+func (p Polygon) Centroid() engo.Point {
+	var c engo.Point
+	if len(p) == 0 {
+		return c
+	}
+	area := p.SignedArea()
+	if engo.FloatEqual(area, 0) {
+		for _, point := range p {
+			c.Add(point)
+		}
+		c.MultiplyScalar(1 / float32(len(p)))
+		return c
+	}
+	for i := range p {
+		a, b := p[i], p[(i+1)%len(p)]
+		cross := a.X*b.Y - b.X*a.Y
+		c.X += (a.X + b.X) * cross
+		c.Y += (a.Y + b.Y) * cross
+	}
+	c.MultiplyScalar(1 / (6 * area))
+	return c
+}
+
+// Contains reports whether the point lies within the Polygon. Points on its
+// edges may be reported either way. It implements the engo.Container interface.
+func (p Polygon) Contains(point engo.Point) bool {
+	inside := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		a, b := p[i], p[j]
+		if (a.Y > point.Y) != (b.Y > point.Y) && point.X < (b.X-a.X)*(point.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// AABB returns the axis aligned bounding box of the Polygon. It implements the
+// engo.AABBer interface.
+func (p Polygon) AABB() engo.AABB {
+	if len(p) == 0 {
+		return engo.AABB{}
+	}
+	box := engo.AABB{Min: p[0], Max: p[0]}
+	for _, point := range p[1:] {
+		box.Min.X = math.Min(box.Min.X, point.X)
+		box.Min.Y = math.Min(box.Min.Y, point.Y)
+		box.Max.X = math.Max(box.Max.X, point.X)
+		box.Max.Y = math.Max(box.Max.Y, point.Y)
+	}
+	return box
+}
+
+// Normalized returns the Polygon scaled to fit from 0 to 1 within its bounding
+// box, along with the box, e.g. to use it as a common.ComplexTriangles.
+func (p Polygon) Normalized() (Polygon, engo.AABB) {
+	box := p.AABB()
+	w, h := box.Max.X-box.Min.X, box.Max.Y-box.Min.Y
+	n := make(Polygon, len(p))
+	for i, point := range p {
+		n[i] = point
+		n[i].Subtract(box.Min)
+		if w > 0 {
+			n[i].X /= w
+		}
+		if h > 0 {
+			n[i].Y /= h
+		}
+	}
+	return n, box
+}
+
+// Lines returns the edges of the Polygon, which can be used as the Lines of a
+// common.Shape. Collision detection only works for convex shapes, so concave
+// polygons have to be split using ConvexDecomposition first.
+func (p Polygon) Lines() []engo.Line {
+	lines := make([]engo.Line, len(p))
+	for i := range p {
+		lines[i] = engo.Line{P1: p[i], P2: p[(i+1)%len(p)]}
+	}
+	return lines
+}
+
+// IsConvex reports whether the Polygon is convex, meaning all of its corners
+// turn the same way.
+func (p Polygon) IsConvex() bool {
+	sign := float32(0)
+	for i := range p {
+		turn := cross(p[i], p[(i+1)%len(p)], p[(i+2)%len(p)])
+		if engo.FloatEqual(turn, 0) {
+			continue
+		}
+		if sign != 0 && (turn > 0) != (sign > 0) {
+			return false
+		}
+		sign = turn
+	}
+	return true
+}
+
+// IsSimple reports whether the edges of the Polygon don't cross or touch each
+// other, other than where they meet.
+func (p Polygon) IsSimple() bool {
+	n := len(p)
+	for i := 0; i < n; i++ {
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				// The last edge meets the first one
+				continue
+			}
+			if segmentsTouch(p[i], p[(i+1)%n], p[j], p[(j+1)%n]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// segmentsTouch reports whether the segments a1-a2 and b1-b2 share any point
+func segmentsTouch(a1, a2, b1, b2 engo.Point) bool {
+	d1, d2 := cross(b1, b2, a1), cross(b1, b2, a2)
+	d3, d4 := cross(a1, a2, b1), cross(a1, a2, b2)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(b1, b2, a1)) || (d2 == 0 && onSegment(b1, b2, a2)) ||
+		(d3 == 0 && onSegment(a1, a2, b1)) || (d4 == 0 && onSegment(a1, a2, b2))
+}
+
+// onSegment reports whether p, which lies on the line through a and b, lies in
+// between them
+func onSegment(a, b, p engo.Point) bool {
+	return math.Min(a.X, b.X) <= p.X && p.X <= math.Max(a.X, b.X) && math.Min(a.Y, b.Y) <= p.Y && p.Y <= math.Max(a.Y, b.Y)
+}
+
+// cross returns the cross product of the vectors from a to b and from b to c,
+// which is positive if the corner at b turns clockwise on screen
+func cross(a, b, c engo.Point) float32 {
+	return (b.X-a.X)*(c.Y-b.Y) - (b.Y-a.Y)*(c.X-b.X)
+}
@@ -0,0 +1,145 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/EngoEngine/engo"
+)
+
+// square is a square from (0, 0) to (2, 2) with a Clockwise winding
+var square = Polygon{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}}
+
+// lShape is a concave polygon with a Clockwise winding and an area of 3
+var lShape = Polygon{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 0, Y: 2}}
+
+func TestPolygonArea(t *testing.T) {
+	data := []struct {
+		polygon  Polygon
+		signed   float32
+		winding  Winding
+		centroid engo.Point
+	}{
+		{square, 4, Clockwise, engo.Point{X: 1, Y: 1}},
+		{square.Reverse(), -4, CounterClockwise, engo.Point{X: 1, Y: 1}},
+		{lShape, 3, Clockwise, engo.Point{X: 5.0 / 6, Y: 5.0 / 6}},
+		{Polygon{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 3}}, 6, Clockwise, engo.Point{X: 4.0 / 3, Y: 1}},
+		// Without area, the average of the points is used as the centroid
+		{Polygon{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}}, 0, Clockwise, engo.Point{X: 1, Y: 1}},
+	}
+
+	for _, d := range data {
+		if res := d.polygon.SignedArea(); !engo.FloatEqual(res, d.signed) {
+			t.Errorf("SignedArea of %v should have been %v, got %v", d.polygon, d.signed, res)
+		}
+		if res := d.polygon.Area(); res < 0 {
+			t.Errorf("Area of %v should have been positive, got %v", d.polygon, res)
+		}
+		if res := d.polygon.Winding(); res != d.winding {
+			t.Errorf("Winding of %v should have been %v, got %v", d.polygon, d.winding, res)
+		}
+		if res := d.polygon.Centroid(); !res.Equal(d.centroid) {
+			t.Errorf("Centroid of %v should have been %v, got %v", d.polygon, d.centroid, res)
+		}
+	}
+
+	if res := square.WithWinding(CounterClockwise); res.Winding() != CounterClockwise || !res[0].Equal(square[3]) {
+		t.Errorf("WithWinding should have reversed the polygon, got %v", res)
+	}
+	if res := square.WithWinding(Clockwise); &res[0] != &square[0] {
+		t.Error("WithWinding should have kept the polygon when it already has the winding")
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+	data := []struct {
+		p        engo.Point
+		expected bool
+	}{
+		{engo.Point{X: 0.5, Y: 0.5}, true},
+		{engo.Point{X: 1.5, Y: 0.5}, true},
+		{engo.Point{X: 0.5, Y: 1.5}, true},
+		{engo.Point{X: 1.5, Y: 1.5}, false},
+		{engo.Point{X: -1, Y: 0.5}, false},
+		{engo.Point{X: 3, Y: 3}, false},
+	}
+
+	for _, d := range data {
+		if res := lShape.Contains(d.p); res != d.expected {
+			t.Errorf("Contains(%v) should have been %v, got %v", d.p, d.expected, res)
+		}
+		if res := d.p.Within(lShape.Reverse()); res != d.expected {
+			t.Errorf("Contains(%v) should not have depended on the winding, got %v", d.p, res)
+		}
+	}
+}
+
+func TestPolygonAABBAndNormalized(t *testing.T) {
+	p := Polygon{{X: 10, Y: 20}, {X: 30, Y: 20}, {X: 20, Y: 60}}
+	box := p.AABB()
+	if !box.Min.Equal(engo.Point{X: 10, Y: 20}) || !box.Max.Equal(engo.Point{X: 30, Y: 60}) {
+		t.Errorf("AABB did not return the bounding box, got %v", box)
+	}
+
+	n, nbox := p.Normalized()
+	if nbox != box {
+		t.Errorf("Normalized should have returned the bounding box, got %v", nbox)
+	}
+	expected := Polygon{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0.5, Y: 1}}
+	for i := range expected {
+		if !n[i].Equal(expected[i]) {
+			t.Errorf("Normalized did not scale the points within the box, wanted %v, got %v", expected, n)
+			break
+		}
+	}
+}
+
+func TestPolygonLines(t *testing.T) {
+	lines := square.Lines()
+	if len(lines) != 4 {
+		t.Fatalf("Lines should have returned an edge for each point, got %v", lines)
+	}
+	if last := lines[3]; !last.P1.Equal(square[3]) || !last.P2.Equal(square[0]) {
+		t.Errorf("The last line should have closed the polygon, got %v", last)
+	}
+}
+
+func TestPolygonIsConvex(t *testing.T) {
+	data := []struct {
+		polygon  Polygon
+		expected bool
+	}{
+		{square, true},
+		{square.Reverse(), true},
+		{lShape, false},
+		{lShape.Reverse(), false},
+		// Points on the edges don't make a polygon concave
+		{Polygon{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}}, true},
+	}
+
+	for _, d := range data {
+		if res := d.polygon.IsConvex(); res != d.expected {
+			t.Errorf("IsConvex of %v should have been %v, got %v", d.polygon, d.expected, res)
+		}
+	}
+}
+
+func TestConvexHull(t *testing.T) {
+	points := []engo.Point{
+		{X: 1, Y: 1}, {X: 0, Y: 0}, {X: 2, Y: 0}, {X: 1, Y: 0},
+		{X: 2, Y: 2}, {X: 0.5, Y: 1.5}, {X: 0, Y: 2}, {X: 1, Y: 2},
+	}
+	hull := ConvexHull(points)
+	if len(hull) != 4 {
+		t.Fatalf("ConvexHull should have returned the corners of the square, got %v", hull)
+	}
+	if hull.Winding() != Clockwise || !hull.IsConvex() || !engo.FloatEqual(hull.Area(), 4) {
+		t.Errorf("ConvexHull should have been a convex clockwise polygon covering the square, got %v", hull)
+	}
+	if !hull.Contains(engo.Point{X: 1, Y: 1}) {
+		t.Errorf("ConvexHull should have contained the points within it, got %v", hull)
+	}
+
+	if res := ConvexHull(points[:2]); len(res) != 2 {
+		t.Errorf("ConvexHull of two points should have returned them, got %v", res)
+	}
+}
@@ -0,0 +1,181 @@
+package geometry
+
+import (
+	"errors"
+
+	"github.com/EngoEngine/engo"
+)
+
+var (
+	// ErrTooFewPoints is returned when a Polygon has less than three points,
+	// and therefore no area.
+	ErrTooFewPoints = errors.New("polygon has less than three points")
+	// ErrNotSimple is returned when a Polygon can't be triangulated, because
+	// its edges cross or touch each other.
+	ErrNotSimple = errors.New("polygon is not simple, its edges cross or touch each other")
+)
+
+// Triangulate splits the Polygon into triangles using ear clipping, and returns
+// their points, three for each triangle, with the same Winding as the Polygon.
+// The triangles of a normalized Polygon, see Normalized, can be used as the
+// Points of a common.ComplexTriangles to draw it.
+func (p Polygon) Triangulate() ([]engo.Point, error) {
+	triangles, reversed, err := p.triangulate()
+	if err != nil {
+		return nil, err
+	}
+	points := make([]engo.Point, 0, 3*len(triangles))
+	for _, t := range triangles {
+		if reversed {
+			t[0], t[2] = t[2], t[0]
+		}
+		points = append(points, p[t[0]], p[t[1]], p[t[2]])
+	}
+	return points, nil
+}
+
+// ConvexDecomposition splits the Polygon into convex polygons with the same
+// Winding, using the algorithm of Hertel and Mehlhorn, which merges the
+// triangles of the Polygon as long as the result stays convex. It results in at
+// most four times as many polygons as needed. Each of them can be used as a
+// common.Shape for collision detection, using their Lines.
+func (p Polygon) ConvexDecomposition() ([]Polygon, error) {
+	triangles, reversed, err := p.triangulate()
+	if err != nil {
+		return nil, err
+	}
+	pieces := make([][]int, len(triangles))
+	for i, t := range triangles {
+		pieces[i] = []int{t[0], t[1], t[2]}
+	}
+
+	for merged := true; merged; {
+		merged = false
+	search:
+		for i := range pieces {
+			for j := i + 1; j < len(pieces); j++ {
+				if m := mergePieces(pieces[i], pieces[j]); m != nil && p.piece(m).IsConvex() {
+					pieces[i] = m
+					pieces = append(pieces[:j], pieces[j+1:]...)
+					merged = true
+					break search
+				}
+			}
+		}
+	}
+
+	polygons := make([]Polygon, len(pieces))
+	for i, piece := range pieces {
+		polygons[i] = p.piece(piece)
+		if reversed {
+			polygons[i] = polygons[i].Reverse()
+		}
+	}
+	return polygons, nil
+}
+
+// piece returns the Polygon made of the points of p with the given indices
+func (p Polygon) piece(indices []int) Polygon {
+	piece := make(Polygon, len(indices))
+	for i, index := range indices {
+		piece[i] = p[index]
+	}
+	return piece
+}
+
+// triangulate returns the triangles of the Polygon as indices of its points,
+// with a Clockwise winding. It reports whether that is the reverse of the
+// Winding of the Polygon.
+func (p Polygon) triangulate() (triangles [][3]int, reversed bool, err error) {
+	if len(p) < 3 {
+		return nil, false, ErrTooFewPoints
+	}
+	if !p.IsSimple() {
+		return nil, false, ErrNotSimple
+	}
+	indices := make([]int, len(p))
+	for i := range indices {
+		indices[i] = i
+	}
+	if p.SignedArea() < 0 {
+		reversed = true
+		for i, j := 0, len(indices)-1; i < j; i, j = i+1, j-1 {
+			indices[i], indices[j] = indices[j], indices[i]
+		}
+	}
+
+	for len(indices) > 3 {
+		clipped := false
+		for i := range indices {
+			a := indices[(i+len(indices)-1)%len(indices)]
+			b := indices[i]
+			c := indices[(i+1)%len(indices)]
+			turn := cross(p[a], p[b], p[c])
+			if engo.FloatEqual(turn, 0) {
+				// b lies on the line through its neighbours, and is a spike if
+				// it isn't in between them
+				if !engo.FloatEqual(p[a].PointDistance(p[b])+p[b].PointDistance(p[c]), p[a].PointDistance(p[c])) {
+					continue
+				}
+			} else {
+				if turn < 0 || p.blocksEar(indices, a, b, c) {
+					continue
+				}
+				triangles = append(triangles, [3]int{a, b, c})
+			}
+			// b is an ear, or lies in between its neighbours, which needs no
+			// triangle
+			indices = append(indices[:i], indices[i+1:]...)
+			clipped = true
+			break
+		}
+		if !clipped {
+			return nil, false, ErrNotSimple
+		}
+	}
+	if turn := cross(p[indices[0]], p[indices[1]], p[indices[2]]); turn > 0 && !engo.FloatEqual(turn, 0) {
+		triangles = append(triangles, [3]int{indices[0], indices[1], indices[2]})
+	}
+	return triangles, reversed, nil
+}
+
+// blocksEar reports whether any of the remaining points, other than the
+// corners, lies within the triangle abc
+func (p Polygon) blocksEar(indices []int, a, b, c int) bool {
+	for _, i := range indices {
+		if i == a || i == b || i == c {
+			continue
+		}
+		point := p[i]
+		if point == p[a] || point == p[b] || point == p[c] {
+			continue
+		}
+		if cross(p[a], p[b], point) >= 0 && cross(p[b], p[c], point) >= 0 && cross(p[c], p[a], point) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// mergePieces returns the polygon made of both pieces if they share an edge, as
+// indices, or nil if they don't
+func mergePieces(a, b []int) []int {
+	for i := range a {
+		x, y := a[i], a[(i+1)%len(a)]
+		for j := range b {
+			if b[j] != y || b[(j+1)%len(b)] != x {
+				continue
+			}
+			// Go around a from y to x, and then around b in between x and y
+			merged := make([]int, 0, len(a)+len(b)-2)
+			for k := 1; k <= len(a); k++ {
+				merged = append(merged, a[(i+k)%len(a)])
+			}
+			for k := 2; k < len(b); k++ {
+				merged = append(merged, b[(j+k)%len(b)])
+			}
+			return merged
+		}
+	}
+	return nil
+}
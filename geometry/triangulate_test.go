@@ -0,0 +1,92 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/EngoEngine/engo"
+)
+
+// comb is a concave polygon with several reflex corners, and an area of 7
+var comb = Polygon{
+	{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 5, Y: 3}, {X: 4, Y: 3}, {X: 4, Y: 1},
+	{X: 3, Y: 1}, {X: 3, Y: 3}, {X: 2, Y: 3}, {X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 3}, {X: 0, Y: 3},
+}
+
+func TestPolygonTriangulate(t *testing.T) {
+	data := []Polygon{square, lShape, comb, comb.Reverse(), {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 1, Y: 1}}}
+
+	for _, p := range data {
+		points, err := p.Triangulate()
+		if err != nil {
+			t.Errorf("Unable to triangulate %v: %v", p, err)
+			continue
+		}
+		if len(points)%3 != 0 {
+			t.Errorf("Triangulate should have returned three points for each triangle, got %d", len(points))
+			continue
+		}
+
+		var area float32
+		for i := 0; i < len(points); i += 3 {
+			triangle := Polygon(points[i : i+3])
+			if triangle.Winding() != p.Winding() {
+				t.Errorf("Triangle %v should have had the winding of %v", triangle, p)
+			}
+			if !p.Contains(triangle.Centroid()) {
+				t.Errorf("Triangle %v should have been within %v", triangle, p)
+			}
+			area += triangle.Area()
+		}
+		if !engo.FloatEqual(area, p.Area()) {
+			t.Errorf("Triangles of %v should have covered an area of %v, got %v", p, p.Area(), area)
+		}
+	}
+}
+
+func TestPolygonTriangulateErrors(t *testing.T) {
+	if _, err := (Polygon{{X: 0, Y: 0}, {X: 1, Y: 1}}).Triangulate(); err != ErrTooFewPoints {
+		t.Errorf("Triangulating two points should have failed with ErrTooFewPoints, got %v", err)
+	}
+	bowtie := Polygon{{X: 0, Y: 0}, {X: 2, Y: 2}, {X: 2, Y: 0}, {X: 0, Y: 2}}
+	if bowtie.IsSimple() || !comb.IsSimple() {
+		t.Error("Only the polygon of which the edges cross should not have been simple")
+	}
+	if _, err := bowtie.ConvexDecomposition(); err == nil {
+		t.Error("Decomposing a polygon of which the edges cross should have failed")
+	}
+}
+
+func TestPolygonConvexDecomposition(t *testing.T) {
+	data := []struct {
+		polygon Polygon
+		// most is the most convex polygons the decomposition should result in
+		most int
+	}{
+		{square, 1},
+		{lShape, 2},
+		{comb, 5},
+		{comb.Reverse(), 5},
+	}
+
+	for _, d := range data {
+		pieces, err := d.polygon.ConvexDecomposition()
+		if err != nil {
+			t.Errorf("Unable to decompose %v: %v", d.polygon, err)
+			continue
+		}
+		if len(pieces) > d.most {
+			t.Errorf("Decomposing %v should have resulted in at most %d polygons, got %d", d.polygon, d.most, len(pieces))
+		}
+
+		var area float32
+		for _, piece := range pieces {
+			if !piece.IsConvex() || piece.Winding() != d.polygon.Winding() {
+				t.Errorf("Piece %v should have been convex, with the winding of %v", piece, d.polygon)
+			}
+			area += piece.Area()
+		}
+		if !engo.FloatEqual(area, d.polygon.Area()) {
+			t.Errorf("Pieces of %v should have covered an area of %v, got %v", d.polygon, d.polygon.Area(), area)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package engo
 
+import "io"
+
 const (
 	// AxisMax is the maximum value a joystick or keypress axis will reach
 	AxisMax float32 = 1
@@ -12,11 +14,14 @@ const (
 // NewInputManager holds onto anything input related for engo
 func NewInputManager() *InputManager {
 	return &InputManager{
-		Touches:  make(map[int]Point),
-		axes:     make(map[string]Axis),
-		buttons:  make(map[string]Button),
-		keys:     NewKeyManager(),
-		gamepads: NewGamepadManager(),
+		Touches:      make(map[int]Point),
+		axes:         make(map[string]Axis),
+		buttons:      make(map[string]Button),
+		chords:       &chordCache{},
+		keys:         NewKeyManager(),
+		mouseButtons: &mouseButtonManager{},
+		gamepads:     NewGamepadManager(),
+		buffer:       newInputBuffer(),
 	}
 }
 
@@ -35,13 +40,85 @@ type InputManager struct {
 
 	axes     map[string]Axis
 	buttons  map[string]Button
+	chords   *chordCache
 	keys     *KeyManager
 	gamepads *GamepadManager
+	capture  *keyCapture
+	recorder *inputRecorder
+	playback *inputPlayback
+	buffer   *inputBuffer
+
+	// mouseButtons is the state of each mouse button, which is set by the backends
+	mouseButtons *mouseButtonManager
+
+	// contexts are the active InputContexts, from the highest to the lowest priority
+	contexts       []*InputContext
+	contextsPushed uint64
+
+	// blockedInput is the InputManager given to the Scenes below the top one
+	blockedInput *InputManager
+	// isBlocked is set for blockedInput itself
+	isBlocked bool
 }
 
 func (im *InputManager) update() {
+	// the recording is in charge of the gamepads while replaying
+	if im.playback == nil {
+		im.gamepads.update()
+	}
+	im.updateCapture()
 	im.keys.update()
-	im.gamepads.update()
+	im.mouseButtons.update()
+}
+
+// blocked returns an InputManager which shares the registered axes and buttons
+// of im, but in which nothing is pressed, touched or moved. It's used for the
+// Scenes that are below the top-most Scene.
+func (im *InputManager) blocked() *InputManager {
+	b := im.blockedInput
+	if b == nil {
+		b = &InputManager{
+			Mouse:        Mouse{Action: Neutral},
+			Touches:      make(map[int]Point),
+			axes:         im.axes,
+			buttons:      im.buttons,
+			chords:       im.chords,
+			keys:         NewKeyManager(),
+			mouseButtons: &mouseButtonManager{},
+			gamepads:     NewGamepadManager(),
+			buffer:       newInputBuffer(),
+			isBlocked:    true,
+		}
+		im.blockedInput = b
+	}
+	b.Mouse = Mouse{Action: Neutral}
+	for id := range b.Touches {
+		delete(b.Touches, id)
+	}
+	b.contexts = im.contexts
+
+	// Keep the same gamepads registered, none of which are pressed
+	im.gamepads.mutex.RLock()
+	for name := range b.gamepads.gamepads {
+		if _, ok := im.gamepads.gamepads[name]; !ok {
+			delete(b.gamepads.gamepads, name)
+		}
+	}
+	for name := range im.gamepads.gamepads {
+		if _, ok := b.gamepads.gamepads[name]; !ok {
+			b.gamepads.gamepads[name] = &Gamepad{}
+		}
+	}
+	im.gamepads.mutex.RUnlock()
+
+	return b
+}
+
+// Blocked reports whether the InputManager is the one given to the Scenes
+// which don't get to see the input, such as the ones below an Overlay.
+// Nothing is pressed, touched or moved in it.
+func (im *InputManager) Blocked() bool {
+	return im.isBlocked
 }
 
 // RegisterAxis registers a new axis which can be used to retrieve inputs which are spectrums.
@@ -52,28 +129,73 @@ func (im *InputManager) RegisterAxis(name string, pairs ...AxisPair) {
 	}
 }
 
+// AddAxisPairs adds pairs to the Axis with the given name, which is registered if it
+// doesn't exist yet. Unlike RegisterAxis, it keeps the pairs the Axis already has, e.g. to
+// drive it using on-screen controls as well.
+func (im *InputManager) AddAxisPairs(name string, pairs ...AxisPair) {
+	axis := im.axes[name]
+	axis.Name = name
+	axis.Pairs = append(append([]AxisPair(nil), axis.Pairs...), pairs...)
+	im.axes[name] = axis
+}
+
 // RegisterButton registers a new button input.
 func (im *InputManager) RegisterButton(name string, keys ...Key) {
 	im.buttons[name] = Button{
 		Triggers: keys,
 		Name:     name,
 	}
+	im.chords.invalidate()
 }
 
 // RegisterGamepad registers a new gamepad for use. It starts with joystick0
 // and continues until it finds one that can be used. If it does not find a
-// suitable gamepad, an error will be returned.
+// suitable gamepad, an error will be returned, and the gamepad is used once
+// one gets plugged in.
 func (im *InputManager) RegisterGamepad(name string) error {
 	return im.gamepads.Register(name)
 }
 
-// Axis retrieves an Axis with a specified name.
+// JoinGamepad lets the next unassigned joystick of which the given button is
+// pressed join as the gamepad with the given name. See GamepadManager.Join.
+func (im *InputManager) JoinGamepad(name, button string) {
+	im.gamepads.Join(name, button)
+}
+
+// LeaveGamepad unregisters the gamepad with the given name, so its joystick
+// can join again. See GamepadManager.Leave.
+func (im *InputManager) LeaveGamepad(name string) {
+	im.gamepads.Leave(name)
+}
+
+// AddGamepadMappings reads mappings in the format of the SDL_GameControllerDB from r, which
+// map the layout of more kinds of joysticks to the one of a Gamepad. See
+// GamepadManager.AddMappings.
+func (im *InputManager) AddGamepadMappings(r io.Reader) error {
+	return im.gamepads.AddMappings(r)
+}
+
+// Axis retrieves an Axis with a specified name. The active InputContexts are searched
+// first, from the highest to the lowest priority, and then the Axes registered on the
+// InputManager itself.
 func (im *InputManager) Axis(name string) Axis {
+	for _, ic := range im.contexts {
+		if axis, ok := ic.axes[name]; ok {
+			return axis
+		}
+	}
 	return im.axes[name]
 }
 
-// Button retrieves a Button with a specified name.
+// Button retrieves a Button with a specified name. The active InputContexts are searched
+// first, from the highest to the lowest priority, and then the Buttons registered on the
+// InputManager itself.
 func (im *InputManager) Button(name string) Button {
+	for _, ic := range im.contexts {
+		if button, ok := ic.buttons[name]; ok {
+			return button
+		}
+	}
 	return im.buttons[name]
 }
 
@@ -0,0 +1,402 @@
+package engo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// BindingProfile holds the keys, mouse buttons and gamepad inputs that are
+// bound to the Buttons and Axes of an InputManager, by their name. It can be
+// saved as JSON to let players keep their controls, with keys stored by their
+// name so profiles work on all platforms.
+type BindingProfile struct {
+	// Buttons holds the keys bound to each Button
+	Buttons map[string][]Key `json:"buttons"`
+	// MouseButtons holds the MouseButtons bound to each Button
+	MouseButtons map[string][]MouseButton `json:"mouseButtons"`
+	// GamepadButtons holds the GamepadButtonTriggers of each Button
+	GamepadButtons map[string][]GamepadButtonTrigger `json:"gamepadButtons"`
+	// GamepadAxisTriggers holds the GamepadAxisTriggers of each Button
+	GamepadAxisTriggers map[string][]GamepadAxisTrigger `json:"gamepadAxisTriggers"`
+	// Axes holds the AxisKeyPairs of each Axis
+	Axes map[string][]AxisKeyPair `json:"axes"`
+	// GamepadAxes holds the AxisGamepadPairs of each Axis. Other kinds of
+	// AxisPairs, such as AxisMouse, are not included.
+	GamepadAxes map[string][]AxisGamepadPair `json:"gamepadAxes"`
+}
+
+// BindingConflict is a ButtonTrigger or AxisPair which is bound to more than
+// one Button or Axis.
+type BindingConflict struct {
+	// Trigger is the ButtonTrigger, such as a Key, MouseButton or
+	// GamepadButtonTrigger, or nil for an AxisPair
+	Trigger ButtonTrigger
+	// Pair is the AxisPair, such as an AxisGamepadPair, or nil for a
+	// ButtonTrigger
+	Pair AxisPair
+	// Bindings are the names of the Buttons and Axes it is bound to, in
+	// alphabetical order
+	Bindings []string
+}
+
+// keyCapture is a request to receive the next key or gamepad button that gets
+// pressed
+type keyCapture struct {
+	key           func(Key)
+	gamepadButton func(gamepad, button string)
+}
+
+// RebindButton replaces the keys of a Button that has been registered before,
+// while keeping its other triggers. Use this to let players change their
+// controls at runtime.
+func (im *InputManager) RebindButton(name string, keys ...Key) error {
+	button, ok := im.buttons[name]
+	if !ok {
+		return fmt.Errorf("unable to rebind button %q, it has not been registered", name)
+	}
+
+	button.Triggers = keys
+	im.buttons[name] = button
+	im.chords.invalidate()
+	return nil
+}
+
+// RebindButtonTriggers replaces all triggers of a Button that has been
+// registered before, such as keys, mouse buttons and the GamepadButtonTriggers
+// of buttons captured using CaptureNextGamepadButton.
+func (im *InputManager) RebindButtonTriggers(name string, triggers ...ButtonTrigger) error {
+	button, ok := im.buttons[name]
+	if !ok {
+		return fmt.Errorf("unable to rebind button %q, it has not been registered", name)
+	}
+
+	rebound := newButton(name, button.context, triggers)
+	button.Triggers, button.Extra = rebound.Triggers, rebound.Extra
+	im.buttons[name] = button
+	im.chords.invalidate()
+	return nil
+}
+
+// RebindAxis replaces the pairs of an Axis that has been registered before. Use
+// this to let players change their controls at runtime.
+func (im *InputManager) RebindAxis(name string, pairs ...AxisPair) error {
+	axis, ok := im.axes[name]
+	if !ok {
+		return fmt.Errorf("unable to rebind axis %q, it has not been registered", name)
+	}
+
+	axis.Pairs = pairs
+	im.axes[name] = axis
+	return nil
+}
+
+// CaptureNextKey calls fn with the next Key that gets pressed, e.g. to let
+// players pick a key in a controls menu. It is called once, at the start of the
+// frame after the key was pressed. Capturing again, or calling CancelCapture,
+// replaces any capture that has not happened yet.
+func (im *InputManager) CaptureNextKey(fn func(Key)) {
+	im.capture = &keyCapture{key: fn}
+}
+
+// CaptureNextGamepadButton calls fn with the name of the Gamepad, and the name
+// of the next button of it that gets pressed, such as "A" or "DpadUp". It works
+// like CaptureNextKey, which it replaces and is replaced by.
+func (im *InputManager) CaptureNextGamepadButton(fn func(gamepad, button string)) {
+	im.capture = &keyCapture{gamepadButton: fn}
+}
+
+// CancelCapture cancels capturing the next key or gamepad button.
+func (im *InputManager) CancelCapture() {
+	im.capture = nil
+}
+
+// Capturing reports whether the InputManager is waiting for a key or gamepad
+// button to capture.
+func (im *InputManager) Capturing() bool {
+	return im.capture != nil
+}
+
+// updateCapture calls the capture callback if the key it waits for has been
+// pressed since the last update. The KeyManager has to be updated after this,
+// and the GamepadManager before this.
+func (im *InputManager) updateCapture() {
+	capture := im.capture
+	if capture == nil {
+		return
+	}
+
+	if capture.key != nil {
+		im.keys.mutex.RLock()
+		var pressed []Key
+		pressed = append(pressed, im.keys.pressed...)
+		im.keys.mutex.RUnlock()
+
+		if len(pressed) > 0 {
+			im.capture = nil
+			capture.key(pressed[0])
+		}
+		return
+	}
+
+	im.gamepads.mutex.RLock()
+	names := make([]string, 0, len(im.gamepads.gamepads))
+	for name := range im.gamepads.gamepads {
+		names = append(names, name)
+	}
+	im.gamepads.mutex.RUnlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		gamepad := im.gamepads.GetGamepad(name)
+		if gamepad == nil {
+			continue
+		}
+		for _, button := range gamepadButtonNames {
+			if gamepad.Button(button).JustPressed() {
+				im.capture = nil
+				capture.gamepadButton(name, button)
+				return
+			}
+		}
+	}
+}
+
+// Bindings returns the names of the Buttons and Axes the trigger is bound to,
+// in alphabetical order. The ones of the active InputContexts are named after
+// their context, e.g. "menu/confirm".
+func (im *InputManager) Bindings(t ButtonTrigger) []string {
+	if !hashable(t) {
+		return nil
+	}
+	return im.bound()[t]
+}
+
+// Conflicts returns the triggers and AxisPairs which are bound to more than one
+// Button or Axis. A controls menu can use this to warn players about inputs
+// that do several things at once.
+func (im *InputManager) Conflicts() []BindingConflict {
+	var conflicts []BindingConflict
+	for bound, names := range im.bound() {
+		if len(names) < 2 {
+			continue
+		}
+		conflict := BindingConflict{Bindings: names}
+		if t, ok := bound.(ButtonTrigger); ok {
+			conflict.Trigger = t
+		} else {
+			conflict.Pair = bound.(AxisPair)
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflictName(conflicts[i]) < conflictName(conflicts[j])
+	})
+	return conflicts
+}
+
+// conflictName returns the name a BindingConflict is sorted by
+func conflictName(c BindingConflict) string {
+	if c.Trigger != nil {
+		return fmt.Sprintf("%T %v", c.Trigger, c.Trigger)
+	}
+	return fmt.Sprintf("%T %v", c.Pair, c.Pair)
+}
+
+// bound maps the triggers and AxisPairs to the sorted names of the Buttons and
+// Axes they are bound to. Triggers which can't be compared, like Chords, are
+// left out.
+func (im *InputManager) bound() map[interface{}][]string {
+	bound := make(map[interface{}][]string)
+	add := func(b interface{}, name string) {
+		if !hashable(b) {
+			return
+		}
+		names := bound[b]
+		if len(names) == 0 || names[len(names)-1] != name {
+			bound[b] = append(names, name)
+		}
+	}
+	addAll := func(prefix string, buttons map[string]Button, axes map[string]Axis) {
+		for name, button := range buttons {
+			for _, k := range button.Triggers {
+				add(k, prefix+name)
+			}
+			for _, t := range button.Extra {
+				add(t, prefix+name)
+			}
+		}
+		for name, axis := range axes {
+			for _, pair := range axis.Pairs {
+				switch p := pair.(type) {
+				case AxisKeyPair:
+					add(p.Min, prefix+name)
+					add(p.Max, prefix+name)
+				case AxisTriggerPair:
+					add(p.Min, prefix+name)
+					add(p.Max, prefix+name)
+				default:
+					add(pair, prefix+name)
+				}
+			}
+		}
+	}
+
+	addAll("", im.buttons, im.axes)
+	for _, ic := range im.contexts {
+		addAll(ic.Name+"/", ic.buttons, ic.axes)
+	}
+	for _, names := range bound {
+		sort.Strings(names)
+	}
+	return bound
+}
+
+// hashable reports whether v can be used as the key of a map
+func hashable(v interface{}) bool {
+	return v != nil && reflect.TypeOf(v).Comparable()
+}
+
+// BindingProfile returns the keys, mouse buttons and gamepad inputs that are
+// currently bound to the Buttons and Axes.
+func (im *InputManager) BindingProfile() BindingProfile {
+	profile := BindingProfile{
+		Buttons:             make(map[string][]Key, len(im.buttons)),
+		MouseButtons:        make(map[string][]MouseButton, len(im.buttons)),
+		GamepadButtons:      make(map[string][]GamepadButtonTrigger, len(im.buttons)),
+		GamepadAxisTriggers: make(map[string][]GamepadAxisTrigger, len(im.buttons)),
+		Axes:                make(map[string][]AxisKeyPair, len(im.axes)),
+		GamepadAxes:         make(map[string][]AxisGamepadPair, len(im.axes)),
+	}
+	for name, button := range im.buttons {
+		keys := make([]Key, len(button.Triggers))
+		copy(keys, button.Triggers)
+		profile.Buttons[name] = keys
+
+		mouseButtons, gamepadButtons, gamepadAxes := []MouseButton{}, []GamepadButtonTrigger{}, []GamepadAxisTrigger{}
+		for _, trigger := range button.Extra {
+			switch trigger := trigger.(type) {
+			case MouseButton:
+				mouseButtons = append(mouseButtons, trigger)
+			case GamepadButtonTrigger:
+				gamepadButtons = append(gamepadButtons, trigger)
+			case GamepadAxisTrigger:
+				gamepadAxes = append(gamepadAxes, trigger)
+			}
+		}
+		profile.MouseButtons[name] = mouseButtons
+		profile.GamepadButtons[name] = gamepadButtons
+		profile.GamepadAxisTriggers[name] = gamepadAxes
+	}
+	for name, axis := range im.axes {
+		pairs, gamepadPairs := []AxisKeyPair{}, []AxisGamepadPair{}
+		for _, pair := range axis.Pairs {
+			switch pair := pair.(type) {
+			case AxisKeyPair:
+				pairs = append(pairs, pair)
+			case AxisGamepadPair:
+				gamepadPairs = append(gamepadPairs, pair)
+			}
+		}
+		profile.Axes[name] = pairs
+		profile.GamepadAxes[name] = gamepadPairs
+	}
+	return profile
+}
+
+// ApplyBindingProfile binds the triggers and pairs in the profile to the
+// Buttons and Axes. Only the kinds of bindings the profile holds are replaced,
+// and names which have not been registered are ignored.
+func (im *InputManager) ApplyBindingProfile(profile BindingProfile) {
+	for name, keys := range profile.Buttons {
+		im.RebindButton(name, keys...)
+	}
+	for name, button := range im.buttons {
+		mouseButtons, hasMouse := profile.MouseButtons[name]
+		gamepadButtons, hasGamepad := profile.GamepadButtons[name]
+		gamepadAxes, hasGamepadAxes := profile.GamepadAxisTriggers[name]
+		if !hasMouse && !hasGamepad && !hasGamepadAxes {
+			continue
+		}
+
+		var extra []ButtonTrigger
+		for _, trigger := range mouseButtons {
+			extra = append(extra, trigger)
+		}
+		for _, trigger := range gamepadButtons {
+			extra = append(extra, trigger)
+		}
+		for _, trigger := range gamepadAxes {
+			extra = append(extra, trigger)
+		}
+		for _, trigger := range button.Extra {
+			switch trigger.(type) {
+			case MouseButton:
+				if hasMouse {
+					continue
+				}
+			case GamepadButtonTrigger:
+				if hasGamepad {
+					continue
+				}
+			case GamepadAxisTrigger:
+				if hasGamepadAxes {
+					continue
+				}
+			}
+			extra = append(extra, trigger)
+		}
+		button.Extra = extra
+		im.buttons[name] = button
+	}
+	im.chords.invalidate()
+	for name, axis := range im.axes {
+		keyPairs, hasKeys := profile.Axes[name]
+		gamepadPairs, hasGamepad := profile.GamepadAxes[name]
+		if !hasKeys && !hasGamepad {
+			continue
+		}
+
+		var pairs []AxisPair
+		for _, pair := range keyPairs {
+			pairs = append(pairs, pair)
+		}
+		for _, pair := range gamepadPairs {
+			pairs = append(pairs, pair)
+		}
+		for _, pair := range axis.Pairs {
+			switch pair.(type) {
+			case AxisKeyPair:
+				if hasKeys {
+					continue
+				}
+			case AxisGamepadPair:
+				if hasGamepad {
+					continue
+				}
+			}
+			pairs = append(pairs, pair)
+		}
+		im.RebindAxis(name, pairs...)
+	}
+}
+
+// SaveBindings writes the BindingProfile of the InputManager to w as JSON.
+func (im *InputManager) SaveBindings(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(im.BindingProfile())
+}
+
+// LoadBindings reads a BindingProfile which was saved using SaveBindings from
+// r, and applies it.
+func (im *InputManager) LoadBindings(r io.Reader) error {
+	var profile BindingProfile
+	if err := json.NewDecoder(r).Decode(&profile); err != nil {
+		return fmt.Errorf("unable to load bindings: %w", err)
+	}
+	im.ApplyBindingProfile(profile)
+	return nil
+}
@@ -0,0 +1,311 @@
+package engo
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKeyNames(t *testing.T) {
+	if KeyArrowLeft.String() != "ArrowLeft" {
+		t.Errorf("Key should be named after its constant, got %q", KeyArrowLeft.String())
+	}
+
+	data, err := json.Marshal([]Key{KeyA, KeySpace, KeyF12})
+	if err != nil {
+		t.Fatalf("Unable to encode keys: %v", err)
+	}
+	if string(data) != `["A","Space","F12"]` {
+		t.Errorf("Keys should be encoded by name, got %s", data)
+	}
+
+	var keys []Key
+	if err = json.Unmarshal(data, &keys); err != nil {
+		t.Fatalf("Unable to decode keys: %v", err)
+	}
+	if !reflect.DeepEqual(keys, []Key{KeyA, KeySpace, KeyF12}) {
+		t.Errorf("Keys were not decoded correctly, got %v", keys)
+	}
+
+	if err = json.Unmarshal([]byte(`["NotAKey"]`), &keys); err == nil {
+		t.Error("Unknown key names should not be decoded")
+	}
+}
+
+func TestRebind(t *testing.T) {
+	Input = NewInputManager()
+	Input.RegisterButton("jump", KeySpace)
+	Input.RegisterAxis("horizontal", AxisKeyPair{KeyA, KeyD})
+
+	if err := Input.RebindButton("jump", KeyW, KeyArrowUp); err != nil {
+		t.Fatalf("Unable to rebind button: %v", err)
+	}
+	if err := Input.RebindAxis("horizontal", AxisKeyPair{KeyArrowLeft, KeyArrowRight}); err != nil {
+		t.Fatalf("Unable to rebind axis: %v", err)
+	}
+
+	Input.keys.Set(KeyArrowUp, true)
+	Input.keys.Set(KeyArrowRight, true)
+	Input.update()
+	if !Input.Button("jump").Down() {
+		t.Error("Button should use the keys it was rebound to")
+	}
+	if Input.Axis("horizontal").Value() != AxisMax {
+		t.Error("Axis should use the keys it was rebound to")
+	}
+
+	if err := Input.RebindButton("fire", KeyF); err == nil {
+		t.Error("Rebinding a button that was not registered should fail")
+	}
+	if err := Input.RebindAxis("vertical"); err == nil {
+		t.Error("Rebinding an axis that was not registered should fail")
+	}
+}
+
+func TestCaptureNextKey(t *testing.T) {
+	im := NewInputManager()
+
+	var captured []Key
+	im.CaptureNextKey(func(k Key) { captured = append(captured, k) })
+	if !im.Capturing() {
+		t.Error("InputManager should be capturing")
+	}
+
+	im.update()
+	if len(captured) != 0 {
+		t.Error("Nothing should be captured before a key is pressed")
+	}
+
+	im.keys.Set(KeyQ, true)
+	im.keys.Set(KeyE, true)
+	im.update()
+	im.keys.Set(KeyR, true)
+	im.update()
+	if !reflect.DeepEqual(captured, []Key{KeyQ}) || im.Capturing() {
+		t.Errorf("Only the first key pressed should be captured, got %v", captured)
+	}
+
+	// Keys that are held down are not pressed again
+	im.CaptureNextKey(func(k Key) { captured = append(captured, k) })
+	im.keys.Set(KeyQ, true)
+	im.update()
+	im.CancelCapture()
+	im.keys.Set(KeyT, true)
+	im.update()
+	if len(captured) != 1 {
+		t.Errorf("Nothing should be captured after cancelling, got %v", captured)
+	}
+}
+
+func TestCaptureNextGamepadButton(t *testing.T) {
+	im := NewInputManager()
+	im.gamepads.gamepads["player2"] = &Gamepad{}
+	im.gamepads.gamepads["player1"] = &Gamepad{}
+
+	var gamepad, button string
+	im.CaptureNextGamepadButton(func(g, b string) { gamepad, button = g, b })
+	im.keys.Set(KeyA, true)
+	im.update()
+	if !im.Capturing() {
+		t.Error("Keys should not be captured while capturing gamepad buttons")
+	}
+	if im.Gamepad("player2").Button("DpadLeft") == nil || im.Gamepad("player2").Button("Trigger") != nil {
+		t.Error("Gamepad buttons should be looked up by the name of their field")
+	}
+	im.Gamepad("player2").Button("DpadLeft").set(true)
+	im.updateCapture()
+	if gamepad != "player2" || button != "DpadLeft" {
+		t.Errorf("Gamepad button was not captured, got %q and %q", gamepad, button)
+	}
+}
+
+func TestBindingConflicts(t *testing.T) {
+	im := NewInputManager()
+	im.RegisterButton("jump", KeySpace, KeyW)
+	im.RegisterButton("confirm", KeySpace, KeyEnter)
+	im.RegisterAxis("vertical", AxisKeyPair{KeyS, KeyW}, NewAxisMouse(AxisMouseVert))
+	im.RegisterAxis("horizontal", AxisKeyPair{KeyA, KeyD})
+
+	if names := im.Bindings(KeyW); !reflect.DeepEqual(names, []string{"jump", "vertical"}) {
+		t.Errorf("Key should be bound to jump and vertical, got %v", names)
+	}
+
+	conflicts := im.Conflicts()
+	expected := []BindingConflict{
+		{Trigger: KeySpace, Bindings: []string{"confirm", "jump"}},
+		{Trigger: KeyW, Bindings: []string{"jump", "vertical"}},
+	}
+	if !reflect.DeepEqual(conflicts, expected) {
+		t.Errorf("Conflicts were not detected correctly, got %v", conflicts)
+	}
+
+	im.RebindButton("jump", KeyJ)
+	if len(im.Conflicts()) != 0 {
+		t.Errorf("Rebinding should resolve the conflicts, got %v", im.Conflicts())
+	}
+
+	data := []struct {
+		name     string
+		conflict BindingConflict
+	}{
+		{"mouse", BindingConflict{Trigger: MouseButtonLeft, Bindings: []string{"fire", "select"}}},
+		{"gamepad button", BindingConflict{Trigger: GamepadButtonTrigger{"player1", "A"}, Bindings: []string{"fire", "menu/confirm"}}},
+		{"gamepad axis", BindingConflict{Trigger: GamepadAxisTrigger{"player1", "RightTrigger", 0.5}, Bindings: []string{"fire", "throttle"}}},
+		{"gamepad axis pair", BindingConflict{Pair: AxisGamepadPair{"player1", "LeftX"}, Bindings: []string{"horizontal", "steer"}}},
+	}
+
+	im = NewInputManager()
+	im.RegisterButtonTriggers("fire", MouseButtonLeft, GamepadButtonTrigger{"player1", "A"},
+		GamepadAxisTrigger{"player1", "RightTrigger", 0.5})
+	im.RegisterButtonTriggers("select", MouseButtonLeft)
+	im.RegisterAxis("throttle", AxisTriggerPair{Max: GamepadAxisTrigger{"player1", "RightTrigger", 0.5}})
+	im.RegisterAxis("horizontal", AxisGamepadPair{"player1", "LeftX"})
+	im.RegisterAxis("steer", AxisGamepadPair{"player1", "LeftX"})
+	menu := NewInputContext("menu", 1)
+	menu.RegisterButtonTriggers("confirm", GamepadButtonTrigger{"player1", "A"}, Chord{Modifiers: Control, Trigger: KeyEnter})
+	im.PushContext(menu)
+
+	conflicts = im.Conflicts()
+	for _, d := range data {
+		found := false
+		for _, conflict := range conflicts {
+			found = found || reflect.DeepEqual(conflict, d.conflict)
+		}
+		if !found {
+			t.Errorf("%s: conflict was not detected, got %v", d.name, conflicts)
+		}
+	}
+	if len(conflicts) != len(data) {
+		t.Errorf("Expected %d conflicts, got %v", len(data), conflicts)
+	}
+}
+
+func TestSaveLoadBindings(t *testing.T) {
+	im := NewInputManager()
+	im.RegisterButton("jump", KeySpace)
+	im.RegisterButton("fire", KeyF)
+	im.RegisterAxis("vertical", AxisKeyPair{KeyS, KeyW}, NewAxisMouse(AxisMouseVert))
+	im.RebindButton("jump", KeyJ, KeyK)
+	im.RebindAxis("vertical", AxisKeyPair{KeyArrowDown, KeyArrowUp}, NewAxisMouse(AxisMouseVert))
+
+	saved := &bytes.Buffer{}
+	if err := im.SaveBindings(saved); err != nil {
+		t.Fatalf("Unable to save bindings: %v", err)
+	}
+	if !strings.Contains(saved.String(), `"ArrowUp"`) {
+		t.Errorf("Keys should be saved by name, got %s", saved.String())
+	}
+
+	loaded := NewInputManager()
+	loaded.RegisterButton("jump", KeySpace)
+	loaded.RegisterButton("crouch", KeyC)
+	loaded.RegisterAxis("vertical", AxisKeyPair{KeyS, KeyW}, NewAxisMouse(AxisMouseVert))
+	if err := loaded.LoadBindings(saved); err != nil {
+		t.Fatalf("Unable to load bindings: %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded.Button("jump").Triggers, []Key{KeyJ, KeyK}) {
+		t.Errorf("Button was not rebound, got %v", loaded.Button("jump").Triggers)
+	}
+	if !reflect.DeepEqual(loaded.Button("crouch").Triggers, []Key{KeyC}) {
+		t.Error("Buttons missing from the profile should keep their keys")
+	}
+	if _, ok := loaded.buttons["fire"]; ok {
+		t.Error("Buttons which were not registered should not be loaded")
+	}
+
+	pairs := loaded.Axis("vertical").Pairs
+	if len(pairs) != 2 || pairs[0] != (AxisKeyPair{KeyArrowDown, KeyArrowUp}) {
+		t.Errorf("Axis was not rebound, got %v", pairs)
+	}
+	if _, ok := pairs[1].(*AxisMouse); !ok {
+		t.Error("Pairs other than AxisKeyPairs should be kept")
+	}
+
+	if err := loaded.LoadBindings(strings.NewReader(`{"buttons": {"jump": ["NotAKey"]}}`)); err == nil {
+		t.Error("Loading unknown keys should fail")
+	}
+}
+
+func TestRebindButtonTriggers(t *testing.T) {
+	Input = NewInputManager()
+	Input.RegisterButton("jump", KeySpace)
+	Input.gamepads.gamepads["player1"] = &Gamepad{}
+
+	if err := Input.RebindButtonTriggers("jump", KeyW, GamepadButtonTrigger{"player1", "A"}); err != nil {
+		t.Fatalf("Unable to rebind button: %v", err)
+	}
+	button := Input.Button("jump")
+	if !reflect.DeepEqual(button.Triggers, []Key{KeyW}) || len(button.Extra) != 1 {
+		t.Errorf("Triggers should have been split into keys and other triggers, got %v and %v", button.Triggers, button.Extra)
+	}
+	Input.Gamepad("player1").A.set(true)
+	Input.Gamepad("player1").A.set(true)
+	if !Input.Button("jump").Down() {
+		t.Error("Button should use the gamepad button it was rebound to")
+	}
+
+	// Rebinding the keys keeps the other triggers
+	Input.RebindButton("jump", KeyJ)
+	if len(Input.Button("jump").Extra) != 1 {
+		t.Error("RebindButton should have kept the triggers other than keys")
+	}
+
+	if err := Input.RebindButtonTriggers("fire", KeyF); err == nil {
+		t.Error("Rebinding a button that was not registered should fail")
+	}
+}
+
+func TestSaveLoadGamepadBindings(t *testing.T) {
+	im := NewInputManager()
+	im.gamepads.gamepads["player1"] = &Gamepad{}
+	im.RegisterButton("jump", KeySpace)
+	im.RegisterButton("fire", KeyF)
+	im.RegisterAxis("horizontal", AxisKeyPair{KeyA, KeyD}, AxisGamepadPair{"player1", "LeftX"})
+
+	// Let the player pick a gamepad button for jumping, like a controls menu
+	// would
+	im.CaptureNextGamepadButton(func(gamepad, button string) {
+		im.RebindButtonTriggers("jump", KeySpace, GamepadButtonTrigger{gamepad, button})
+	})
+	im.Gamepad("player1").Button("Y").set(true)
+	im.updateCapture()
+	im.RebindButtonTriggers("fire", KeyF, MouseButtonLeft, GamepadAxisTrigger{"player1", "RightTrigger", 0.5})
+	im.RebindAxis("horizontal", AxisKeyPair{KeyArrowLeft, KeyArrowRight}, AxisGamepadPair{"player1", "RightX"})
+
+	saved := &bytes.Buffer{}
+	if err := im.SaveBindings(saved); err != nil {
+		t.Fatalf("Unable to save bindings: %v", err)
+	}
+
+	loaded := NewInputManager()
+	loaded.RegisterButton("jump", KeySpace)
+	loaded.RegisterButtonTriggers("fire", KeyF, GamepadButtonTrigger{"player1", "X"})
+	loaded.RegisterAxis("horizontal", AxisKeyPair{KeyA, KeyD}, AxisGamepadPair{"player1", "LeftX"})
+	if err := loaded.LoadBindings(saved); err != nil {
+		t.Fatalf("Unable to load bindings: %v", err)
+	}
+
+	for _, name := range []string{"jump", "fire"} {
+		if res, e := loaded.Button(name), im.Button(name); !reflect.DeepEqual(res.Triggers, e.Triggers) ||
+			!reflect.DeepEqual(res.Extra, e.Extra) {
+			t.Errorf("Button %q should have been loaded as %v %v, got %v %v", name, e.Triggers, e.Extra, res.Triggers, res.Extra)
+		}
+	}
+	if res, e := loaded.Axis("horizontal").Pairs, im.Axis("horizontal").Pairs; !reflect.DeepEqual(res, e) {
+		t.Errorf("Axis should have been loaded as %v, got %v", e, res)
+	}
+
+	// Profiles which only hold keys keep the other bindings
+	if err := loaded.LoadBindings(strings.NewReader(`{"buttons": {"jump": ["J"]}, "axes": {}}`)); err != nil {
+		t.Fatalf("Unable to load bindings: %v", err)
+	}
+	if extra := loaded.Button("jump").Extra; !reflect.DeepEqual(extra, []ButtonTrigger{GamepadButtonTrigger{"player1", "Y"}}) {
+		t.Errorf("Gamepad buttons should have been kept, got %v", extra)
+	}
+	if pairs := loaded.Axis("horizontal").Pairs; len(pairs) != 2 {
+		t.Errorf("Axis pairs should have been kept, got %v", pairs)
+	}
+}
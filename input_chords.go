@@ -0,0 +1,222 @@
+package engo
+
+import "reflect"
+
+// modifierKeys are the keys which hold down each Modifier
+var modifierKeys = []struct {
+	modifier Modifier
+	keys     [2]Key
+}{
+	{Shift, [2]Key{KeyLeftShift, KeyRightShift}},
+	{Control, [2]Key{KeyLeftControl, KeyRightControl}},
+	{Alt, [2]Key{KeyLeftAlt, KeyRightAlt}},
+	{Super, [2]Key{KeyLeftSuper, KeyRightSuper}},
+}
+
+// Modifiers returns the modifiers which are being held down, e.g.
+// Control|Shift. Unlike the Modifier field, which some platforms only update
+// along with other keys, this follows the state of the modifier keys
+// themselves.
+func (im *InputManager) Modifiers() Modifier {
+	_, current := im.modifierState()
+	return current
+}
+
+// modifierState returns the modifiers held down during the previous and the
+// current frame
+func (im *InputManager) modifierState() (last, current Modifier) {
+	for _, mk := range modifierKeys {
+		for _, k := range mk.keys {
+			ks := im.keys.Get(k)
+			if ks.lastState {
+				last |= mk.modifier
+			}
+			if ks.currentState {
+				current |= mk.modifier
+			}
+		}
+	}
+	return last, current
+}
+
+// triggerState returns whether the trigger was held down during the previous
+// and the current frame
+func triggerState(t ButtonTrigger) (last, current bool) {
+	if t.Down() {
+		return true, true
+	}
+	return t.JustReleased(), t.JustPressed()
+}
+
+// Chord is a ButtonTrigger which is only pressed while its Modifiers and Held
+// triggers are held down along with its Trigger, such as Ctrl+S or Shift+Click.
+// Whenever a Chord is down, the Buttons which are bound to a part of it are
+// not, so pressing Ctrl+Shift+S doesn't trigger a Button bound to Ctrl+S, and
+// Ctrl+S doesn't trigger one bound to S.
+type Chord struct {
+	// Modifiers have to be held down, e.g. Control|Shift
+	Modifiers Modifier
+	// Held are the triggers other than modifiers that have to be held down
+	Held []ButtonTrigger
+	// Trigger is the main trigger of the Chord, e.g. KeyS or MouseButtonLeft
+	Trigger ButtonTrigger
+}
+
+// state returns whether all parts of the Chord were held down during the
+// previous and the current frame
+func (c Chord) state() (last, current bool) {
+	if c.Trigger == nil {
+		return false, false
+	}
+
+	last, current = triggerState(c.Trigger)
+	lastModifiers, currentModifiers := Input.modifierState()
+	last = last && lastModifiers&c.Modifiers == c.Modifiers
+	current = current && currentModifiers&c.Modifiers == c.Modifiers
+	for _, held := range c.Held {
+		heldLast, heldCurrent := triggerState(held)
+		last = last && heldLast
+		current = current && heldCurrent
+	}
+	return last, current
+}
+
+// JustPressed reports whether the Chord was completed in the current frame. It
+// implements the ButtonTrigger interface.
+func (c Chord) JustPressed() bool {
+	last, current := c.state()
+	return !last && current
+}
+
+// JustReleased reports whether any part of the Chord was released in the
+// current frame. It implements the ButtonTrigger interface.
+func (c Chord) JustReleased() bool {
+	last, current := c.state()
+	return last && !current
+}
+
+// Down reports whether the Chord is being held down. It implements the
+// ButtonTrigger interface.
+func (c Chord) Down() bool {
+	last, current := c.state()
+	return last && current
+}
+
+// parts returns the modifiers and triggers the Chord consists of
+func (c Chord) parts() (Modifier, []ButtonTrigger) {
+	triggers := make([]ButtonTrigger, 0, len(c.Held)+1)
+	triggers = append(triggers, c.Held...)
+	if c.Trigger != nil {
+		triggers = append(triggers, c.Trigger)
+	}
+	return c.Modifiers, triggers
+}
+
+// triggerParts returns the modifiers and triggers a ButtonTrigger consists of
+func triggerParts(t ButtonTrigger) (Modifier, []ButtonTrigger) {
+	if c, ok := t.(Chord); ok {
+		return c.parts()
+	}
+	return 0, []ButtonTrigger{t}
+}
+
+// sameTrigger reports whether both triggers are the same, without panicking on
+// triggers that can't be compared
+func sameTrigger(a, b ButtonTrigger) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// containsParts reports whether the parts of a contain all parts of b, and at
+// least one more
+func containsParts(aModifiers Modifier, a []ButtonTrigger, bModifiers Modifier, b []ButtonTrigger) bool {
+	if aModifiers&bModifiers != bModifiers {
+		return false
+	}
+	for _, tb := range b {
+		found := false
+		for _, ta := range a {
+			if sameTrigger(ta, tb) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return aModifiers != bModifiers || len(a) > len(b)
+}
+
+// shadowed reports whether a Chord registered to any Button, including those of
+// the active InputContexts, which contains the trigger and more, is or just was
+// held down. This lets the most specific binding win.
+func (im *InputManager) shadowed(t ButtonTrigger) bool {
+	modifiers, parts := triggerParts(t)
+	if shadowedBy(im.chords.get(im.buttons), modifiers, parts) {
+		return true
+	}
+	for _, ic := range im.contexts {
+		if shadowedBy(ic.chords.get(ic.buttons), modifiers, parts) {
+			return true
+		}
+	}
+	return false
+}
+
+// shadowedBy reports whether any of the chords contains the parts and more, and
+// is or just was held down
+func shadowedBy(chords []chordParts, modifiers Modifier, parts []ButtonTrigger) bool {
+	for _, chord := range chords {
+		if !containsParts(chord.modifiers, chord.parts, modifiers, parts) {
+			continue
+		}
+		if last, current := chord.chord.state(); last || current {
+			return true
+		}
+	}
+	return false
+}
+
+// chordParts is a Chord along with its parts
+type chordParts struct {
+	chord     Chord
+	modifiers Modifier
+	parts     []ButtonTrigger
+}
+
+// chordCache holds the Chords bound to a set of Buttons, so they aren't looked
+// up each time a Button is checked. It is invalidated whenever the Buttons
+// change.
+type chordCache struct {
+	chords []chordParts
+	valid  bool
+}
+
+// get returns the Chords among the Extra triggers of the buttons
+func (c *chordCache) get(buttons map[string]Button) []chordParts {
+	if c.valid {
+		return c.chords
+	}
+	c.chords = c.chords[:0]
+	for _, button := range buttons {
+		for _, extra := range button.Extra {
+			if chord, ok := extra.(Chord); ok {
+				modifiers, parts := chord.parts()
+				c.chords = append(c.chords, chordParts{chord: chord, modifiers: modifiers, parts: parts})
+			}
+		}
+	}
+	c.valid = true
+	return c.chords
+}
+
+// invalidate makes the cache look up the Chords again
+func (c *chordCache) invalidate() {
+	c.valid = false
+}
@@ -0,0 +1,137 @@
+package engo
+
+import "testing"
+
+// chordTestFrame sets the given keys, and then checks which buttons are pressed
+type chordTestFrame struct {
+	press, release []Key
+	justPressed    []string
+	down           []string
+}
+
+func runChordTestFrames(t *testing.T, buttons []string, frames []chordTestFrame) {
+	for i, f := range frames {
+		Input.update()
+		for _, k := range f.press {
+			Input.keys.Set(k, true)
+		}
+		for _, k := range f.release {
+			Input.keys.Set(k, false)
+		}
+
+		for _, name := range buttons {
+			if pressed := Input.Button(name).JustPressed(); pressed != chordTestContains(f.justPressed, name) {
+				t.Errorf("Button %q should have JustPressed %v in frame %d", name, !pressed, i)
+			}
+			if down := Input.Button(name).Down(); down != chordTestContains(f.down, name) {
+				t.Errorf("Button %q should have Down %v in frame %d", name, !down, i)
+			}
+		}
+	}
+}
+
+func chordTestContains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestChord(t *testing.T) {
+	Input = NewInputManager()
+	Input.RegisterButton("down", KeyS)
+	Input.RegisterButtonTriggers("save", Chord{Modifiers: Control, Trigger: KeyS})
+	Input.RegisterButtonTriggers("saveAs", Chord{Modifiers: Control | Shift, Trigger: KeyS})
+
+	buttons := []string{"down", "save", "saveAs"}
+	runChordTestFrames(t, buttons, []chordTestFrame{
+		{press: []Key{KeyS}, justPressed: []string{"down"}},
+		{down: []string{"down"}},
+		{release: []Key{KeyS}},
+		{},
+		// Ctrl+S wins from S
+		{press: []Key{KeyLeftControl}},
+		{press: []Key{KeyS}, justPressed: []string{"save"}},
+		{down: []string{"save"}},
+		// Ctrl+Shift+S wins from Ctrl+S
+		{release: []Key{KeyS}},
+		{press: []Key{KeyRightShift}},
+		{press: []Key{KeyS}, justPressed: []string{"saveAs"}},
+		{down: []string{"saveAs"}},
+		// Letting go of a modifier releases the chord
+		{release: []Key{KeyRightShift, KeyLeftControl}},
+		{down: []string{"down"}},
+	})
+}
+
+func TestChordHeld(t *testing.T) {
+	Input = NewInputManager()
+	Input.RegisterButton("left", KeyA)
+	Input.RegisterButton("right", KeyD)
+	Input.RegisterButtonTriggers("dash", Chord{Held: []ButtonTrigger{KeyA}, Trigger: KeyD})
+	Input.RegisterButtonTriggers("shiftClick", Chord{Modifiers: Shift, Trigger: MouseButtonLeft})
+
+	runChordTestFrames(t, []string{"left", "right", "dash"}, []chordTestFrame{
+		{press: []Key{KeyA}, justPressed: []string{"left"}},
+		{down: []string{"left"}},
+		{press: []Key{KeyD}, justPressed: []string{"dash"}},
+		{down: []string{"dash"}},
+		{release: []Key{KeyA}},
+		{down: []string{"right"}},
+	})
+
+	if Input.Modifiers() != 0 {
+		t.Errorf("No modifiers should be held, got %v", Input.Modifiers())
+	}
+	Input.keys.Set(KeyLeftShift, true)
+	if Input.Modifiers() != Shift {
+		t.Errorf("Shift should be held, got %v", Input.Modifiers())
+	}
+	Input.mouseButtons.Set(MouseButtonLeft, true)
+	if !Input.Button("shiftClick").JustPressed() {
+		t.Error("Shift+Click should have been pressed")
+	}
+}
+
+func TestChordUncomparable(t *testing.T) {
+	Input = NewInputManager()
+	Input.RegisterButtonTriggers("nested", Chord{Held: []ButtonTrigger{Chord{Trigger: KeyA}}, Trigger: KeyB})
+	Input.RegisterButtonTriggers("other", Chord{Held: []ButtonTrigger{Chord{Trigger: KeyA}}, Trigger: KeyC})
+
+	Input.keys.Set(KeyA, true)
+	Input.keys.Set(KeyB, true)
+	if !Input.Button("nested").JustPressed() {
+		t.Error("Chords should be able to hold other chords")
+	}
+}
+
+func TestChordRegisteredLater(t *testing.T) {
+	Input = NewInputManager()
+	Input.RegisterButton("down", KeyS)
+
+	buttons := []string{"down", "save"}
+	runChordTestFrames(t, buttons, []chordTestFrame{
+		{press: []Key{KeyLeftControl}},
+		{press: []Key{KeyS}, justPressed: []string{"down"}},
+		{release: []Key{KeyS}},
+	})
+
+	Input.AddButtonTriggers("save", Chord{Modifiers: Control, Trigger: KeyS})
+
+	// Chords of an active context shadow the Buttons as well, even when
+	// registered after pushing it
+	menu := NewInputContext("menu", 0)
+	menu.Consume = ConsumeNone
+	Input.PushContext(menu)
+	runChordTestFrames(t, buttons, []chordTestFrame{
+		{press: []Key{KeyS}, justPressed: []string{"save"}},
+		{release: []Key{KeyS}},
+	})
+	menu.RegisterButtonTriggers("saveAs", Chord{Modifiers: Control | Shift, Trigger: KeyS})
+	runChordTestFrames(t, buttons, []chordTestFrame{
+		{press: []Key{KeyLeftShift}},
+		{press: []Key{KeyS}},
+	})
+}
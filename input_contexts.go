@@ -0,0 +1,271 @@
+package engo
+
+import "reflect"
+
+// ConsumeMode decides which input an InputContext hides from the contexts below
+// it.
+type ConsumeMode uint
+
+const (
+	// ConsumeBound hides the triggers which are bound to the Buttons and Axes
+	// of the context, so the contexts below don't see them. Other triggers pass
+	// through.
+	ConsumeBound ConsumeMode = iota
+	// ConsumeAll hides all input from the contexts below, e.g. while entering
+	// text.
+	ConsumeAll
+	// ConsumeNone lets all input pass through to the contexts below.
+	ConsumeNone
+)
+
+// InputContext is a named set of Buttons and Axes, such as the controls for
+// gameplay or for a menu, which is only active while it is pushed onto the
+// InputManager. Contexts with a higher priority see input first, and can
+// consume it so the contexts below don't see it. The Buttons and Axes
+// registered directly on the InputManager are below all contexts.
+type InputContext struct {
+	// Name of the context, e.g. "menu"
+	Name string
+	// Priority decides the order of the contexts, from high to low. Of the
+	// contexts with the same priority, the most recently pushed one comes
+	// first.
+	Priority int
+	// Consume decides which input is hidden from the contexts below, which
+	// defaults to ConsumeBound
+	Consume ConsumeMode
+
+	axes    map[string]Axis
+	buttons map[string]Button
+	chords  chordCache
+	pushed  uint64
+}
+
+// NewInputContext creates a new InputContext, which can be pushed onto an
+// InputManager once its Buttons and Axes have been registered.
+func NewInputContext(name string, priority int) *InputContext {
+	return &InputContext{
+		Name:     name,
+		Priority: priority,
+		axes:     make(map[string]Axis),
+		buttons:  make(map[string]Button),
+	}
+}
+
+// RegisterAxis registers a new axis within the context.
+func (ic *InputContext) RegisterAxis(name string, pairs ...AxisPair) {
+	ic.axes[name] = Axis{
+		Name:    name,
+		Pairs:   pairs,
+		context: ic,
+	}
+}
+
+// RegisterButton registers a new button within the context.
+func (ic *InputContext) RegisterButton(name string, keys ...Key) {
+	ic.buttons[name] = Button{
+		Triggers: keys,
+		Name:     name,
+		context:  ic,
+	}
+	ic.chords.invalidate()
+}
+
+// RegisterButtonTriggers registers a new button within the context, which can
+// be pressed using any kind of ButtonTrigger, like
+// InputManager.RegisterButtonTriggers.
+func (ic *InputContext) RegisterButtonTriggers(name string, triggers ...ButtonTrigger) {
+	ic.buttons[name] = newButton(name, ic, triggers)
+	ic.chords.invalidate()
+}
+
+// Axis retrieves an Axis of the context with a specified name. It only has a
+// value while the context is pushed.
+func (ic *InputContext) Axis(name string) Axis {
+	return ic.axes[name]
+}
+
+// Button retrieves a Button of the context with a specified name. It can only
+// be pressed while the context is pushed.
+func (ic *InputContext) Button(name string) Button {
+	return ic.buttons[name]
+}
+
+// binds reports whether the trigger is bound to any of the Buttons or Axes of
+// the context
+func (ic *InputContext) binds(t ButtonTrigger) bool {
+	for _, button := range ic.buttons {
+		for _, k := range button.Triggers {
+			if sameTrigger(k, t) {
+				return true
+			}
+		}
+		for _, extra := range button.Extra {
+			if sameTrigger(extra, t) {
+				return true
+			}
+		}
+	}
+	for _, axis := range ic.axes {
+		for _, pair := range axis.Pairs {
+			switch p := pair.(type) {
+			case AxisKeyPair:
+				if sameTrigger(p.Min, t) || sameTrigger(p.Max, t) {
+					return true
+				}
+			case AxisTriggerPair:
+				if sameTrigger(p.Min, t) || sameTrigger(p.Max, t) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// bindsPair reports whether the AxisPair, or any of its triggers, is bound to
+// any of the Buttons or Axes of the context
+func (ic *InputContext) bindsPair(pair AxisPair) bool {
+	switch p := pair.(type) {
+	case AxisKeyPair:
+		return ic.binds(p.Min) || ic.binds(p.Max)
+	case AxisTriggerPair:
+		return (p.Min != nil && ic.binds(p.Min)) || (p.Max != nil && ic.binds(p.Max))
+	}
+
+	if !reflect.TypeOf(pair).Comparable() {
+		return false
+	}
+	for _, axis := range ic.axes {
+		for _, other := range axis.Pairs {
+			if reflect.TypeOf(other) == reflect.TypeOf(pair) && other == pair {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PushContext activates the InputContext. Pushing a context which is active
+// already moves it to the top of the contexts with the same priority.
+func (im *InputManager) PushContext(ic *InputContext) {
+	im.removeContext(ic)
+
+	im.contextsPushed++
+	ic.pushed = im.contextsPushed
+
+	index := len(im.contexts)
+	for i, other := range im.contexts {
+		if other.Priority <= ic.Priority {
+			index = i
+			break
+		}
+	}
+	contexts := make([]*InputContext, 0, len(im.contexts)+1)
+	contexts = append(contexts, im.contexts[:index]...)
+	contexts = append(contexts, ic)
+	im.contexts = append(contexts, im.contexts[index:]...)
+}
+
+// PopContext deactivates the InputContext which was pushed most recently, and
+// returns it. It returns nil if no contexts are active.
+func (im *InputManager) PopContext() *InputContext {
+	var latest *InputContext
+	for _, ic := range im.contexts {
+		if latest == nil || ic.pushed > latest.pushed {
+			latest = ic
+		}
+	}
+	if latest != nil {
+		im.removeContext(latest)
+	}
+	return latest
+}
+
+// RemoveContext deactivates the InputContext, regardless of when it was pushed.
+func (im *InputManager) RemoveContext(ic *InputContext) {
+	im.removeContext(ic)
+}
+
+func (im *InputManager) removeContext(ic *InputContext) {
+	contexts := make([]*InputContext, 0, len(im.contexts))
+	for _, other := range im.contexts {
+		if other != ic {
+			contexts = append(contexts, other)
+		}
+	}
+	im.contexts = contexts
+}
+
+// Contexts returns the active InputContexts, from the highest to the lowest
+// priority.
+func (im *InputManager) Contexts() []*InputContext {
+	contexts := make([]*InputContext, len(im.contexts))
+	copy(contexts, im.contexts)
+	return contexts
+}
+
+// Context returns the active InputContext with the given name, or nil if there
+// is none.
+func (im *InputManager) Context(name string) *InputContext {
+	for _, ic := range im.contexts {
+		if ic.Name == name {
+			return ic
+		}
+	}
+	return nil
+}
+
+// contextsAbove returns the active contexts above the given one, which is nil
+// for the Buttons and Axes registered on the InputManager itself. The second
+// return value is false if the context isn't active.
+func (im *InputManager) contextsAbove(ic *InputContext) ([]*InputContext, bool) {
+	if ic == nil {
+		return im.contexts, true
+	}
+	for i, other := range im.contexts {
+		if other == ic {
+			return im.contexts[:i], true
+		}
+	}
+	return nil, false
+}
+
+// consumed reports whether the trigger is hidden from the given context by the
+// contexts above it
+func (im *InputManager) consumed(t ButtonTrigger, ic *InputContext) bool {
+	above, active := im.contextsAbove(ic)
+	if !active {
+		return true
+	}
+	for _, other := range above {
+		switch other.Consume {
+		case ConsumeAll:
+			return true
+		case ConsumeBound:
+			if other.binds(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// consumedPair reports whether the AxisPair is hidden from the given context by
+// the contexts above it
+func (im *InputManager) consumedPair(pair AxisPair, ic *InputContext) bool {
+	above, active := im.contextsAbove(ic)
+	if !active {
+		return true
+	}
+	for _, other := range above {
+		switch other.Consume {
+		case ConsumeAll:
+			return true
+		case ConsumeBound:
+			if other.bindsPair(pair) {
+				return true
+			}
+		}
+	}
+	return false
+}
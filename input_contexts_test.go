@@ -0,0 +1,139 @@
+package engo
+
+import "testing"
+
+func TestInputContextOrder(t *testing.T) {
+	Input = NewInputManager()
+	gameplay := NewInputContext("gameplay", 0)
+	menu := NewInputContext("menu", 10)
+	dialog := NewInputContext("dialog", 10)
+
+	Input.PushContext(gameplay)
+	Input.PushContext(menu)
+	Input.PushContext(dialog)
+
+	expected := []*InputContext{dialog, menu, gameplay}
+	contexts := Input.Contexts()
+	if len(contexts) != len(expected) {
+		t.Fatalf("Expected %d contexts, got %d", len(expected), len(contexts))
+	}
+	for i := range expected {
+		if contexts[i] != expected[i] {
+			t.Errorf("Expected context %q at %d, got %q", expected[i].Name, i, contexts[i].Name)
+		}
+	}
+
+	if Input.Context("menu") != menu {
+		t.Error("Context should have returned the menu context")
+	}
+	if ic := Input.PopContext(); ic != dialog {
+		t.Errorf("PopContext should have returned the dialog context, got %v", ic)
+	}
+
+	// Pushing gameplay again doesn't move it above the menu, which has a higher
+	// priority
+	Input.PushContext(gameplay)
+	if contexts = Input.Contexts(); len(contexts) != 2 || contexts[0] != menu {
+		t.Error("Menu context should have stayed on top")
+	}
+	if ic := Input.PopContext(); ic != gameplay {
+		t.Errorf("PopContext should have returned the most recently pushed context, got %v", ic)
+	}
+
+	Input.RemoveContext(menu)
+	if Input.PopContext() != nil {
+		t.Error("PopContext should have returned nil without any active contexts")
+	}
+	if Input.Context("menu") != nil {
+		t.Error("Context should have returned nil for a removed context")
+	}
+}
+
+func TestInputContextConsume(t *testing.T) {
+	Input = NewInputManager()
+	Input.RegisterButton("jump", KeySpace)
+	Input.RegisterButton("fire", KeyF)
+	Input.RegisterAxis("horizontal", AxisKeyPair{Min: KeyA, Max: KeyD})
+
+	menu := NewInputContext("menu", 10)
+	menu.RegisterButton("confirm", KeySpace)
+
+	Input.update()
+	Input.keys.Set(KeySpace, true)
+	Input.keys.Set(KeyF, true)
+	// Axes only see keys that are held down, which takes a second frame
+	Input.keys.Set(KeyD, true)
+	Input.keys.Set(KeyD, true)
+
+	if menu.Button("confirm").JustPressed() {
+		t.Error("Button of a context should not be pressed before it is pushed")
+	}
+	if !Input.Button("jump").JustPressed() {
+		t.Error("Jump should have been pressed without any contexts")
+	}
+
+	Input.PushContext(menu)
+	if !Input.Button("confirm").JustPressed() {
+		t.Error("Confirm should have been pressed once the menu context was pushed")
+	}
+	if Input.Button("jump").JustPressed() {
+		t.Error("Jump should not have been pressed, the menu context consumes KeySpace")
+	}
+	if !Input.Button("fire").JustPressed() {
+		t.Error("Fire should have been pressed, the menu context doesn't bind KeyF")
+	}
+	if v := Input.Axis("horizontal").Value(); v != AxisMax {
+		t.Errorf("Horizontal axis should have passed through the menu context, got %v", v)
+	}
+
+	menu.Consume = ConsumeAll
+	if Input.Button("fire").JustPressed() {
+		t.Error("Fire should not have been pressed while the menu context consumes all input")
+	}
+	if v := Input.Axis("horizontal").Value(); v != AxisNeutral {
+		t.Errorf("Horizontal axis should have been consumed, got %v", v)
+	}
+
+	menu.Consume = ConsumeNone
+	if !Input.Button("jump").JustPressed() || !Input.Button("confirm").JustPressed() {
+		t.Error("Both jump and confirm should have been pressed while the menu context consumes nothing")
+	}
+
+	Input.PopContext()
+	if !Input.Button("jump").JustPressed() {
+		t.Error("Jump should have been pressed once the menu context was popped")
+	}
+}
+
+func TestInputContextShadowing(t *testing.T) {
+	Input = NewInputManager()
+	gameplay := NewInputContext("gameplay", 0)
+	gameplay.RegisterButton("jump", KeySpace)
+	menu := NewInputContext("menu", 10)
+	menu.RegisterButton("jump", KeyW)
+	menu.RegisterAxis("scroll", AxisKeyPair{Min: KeyA, Max: KeyD})
+	Input.PushContext(gameplay)
+	Input.PushContext(menu)
+
+	Input.update()
+	Input.keys.Set(KeyW, true)
+	Input.keys.Set(KeySpace, true)
+
+	// Input.Button resolves the name from the highest context which has it
+	if !Input.Button("jump").JustPressed() {
+		t.Error("Jump of the menu context should have been pressed using KeyW")
+	}
+	Input.keys.Set(KeyW, false)
+	if Input.Button("jump").JustPressed() {
+		t.Error("Jump of the menu context should not be pressed using KeySpace")
+	}
+	if !gameplay.Button("jump").JustPressed() {
+		t.Error("Jump of the gameplay context should have been pressed using KeySpace")
+	}
+
+	Input.keys.Set(KeyA, true)
+	Input.keys.Set(KeyA, true)
+	if v := Input.Axis("scroll").Value(); v != AxisMin {
+		t.Errorf("Scroll axis of the menu context should have been at its minimum, got %v", v)
+	}
+}
@@ -0,0 +1,474 @@
+package engo
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// inputRecordingVersion is the version of the format input is recorded in
+const inputRecordingVersion = 2
+
+// recordedButton is the state of a key or gamepad button
+type recordedButton struct {
+	Last, Current bool
+}
+
+// recordedGamepad is the state of all buttons and axes of a Gamepad
+type recordedGamepad struct {
+	Buttons map[string]recordedButton
+	// Axes holds the raw values of the axes, before their filters were applied,
+	// so the filters of the Gamepad are applied to them again during a replay
+	Axes map[string]float32
+	// Last holds the values of the axes during the previous frame, which the
+	// filters smooth from
+	Last map[string]float32
+}
+
+// gamepadMessageKind is the kind of message a recordedGamepadMessage was
+type gamepadMessageKind uint8
+
+const (
+	gamepadConnected gamepadMessageKind = iota
+	gamepadDisconnected
+	gamepadJoined
+)
+
+// recordedGamepadMessage is a message about a gamepad which was dispatched
+// during a frame
+type recordedGamepadMessage struct {
+	Kind gamepadMessageKind
+	Name string
+	GUID string
+}
+
+// inputState is the state of all input during a frame
+type inputState struct {
+	// Keys are stored by their names, so recordings can be replayed on every
+	// platform
+	Keys         map[string]recordedButton
+	Mouse        Mouse
+	MouseButtons [MouseButtonLast + 1]recordedButton
+	Modifier     Modifier
+	Touches      map[int]Point
+	Gamepads     map[string]recordedGamepad
+}
+
+// inputFrame holds what changed in the inputState since the previous frame
+type inputFrame struct {
+	Keys           map[string]recordedButton
+	Mouse          *Mouse
+	MouseButtons   *[MouseButtonLast + 1]recordedButton
+	Modifier       *Modifier
+	TouchesChanged bool
+	Touches        map[int]Point
+	Gamepads       map[string]recordedGamepad
+	// GamepadMessages are the messages about gamepads connecting, disconnecting
+	// and joining during the frame
+	GamepadMessages []recordedGamepadMessage
+}
+
+// inputRecordingHeader starts every input recording
+type inputRecordingHeader struct {
+	Version int
+}
+
+// inputRecorder writes the input of each frame to a recording
+type inputRecorder struct {
+	compressor *gzip.Writer
+	encoder    *gob.Encoder
+	previous   inputState
+	err        error
+}
+
+// inputPlayback feeds the frames of a recording into an InputManager
+type inputPlayback struct {
+	frames []inputFrame
+	next   int
+	state  inputState
+}
+
+// Record starts recording the input seen by the Scenes during each frame, and
+// writes it to w. Call StopRecording to finish the recording.
+func (im *InputManager) Record(w io.Writer) error {
+	if im.recorder != nil {
+		return errors.New("unable to record input, already recording")
+	}
+
+	compressor := gzip.NewWriter(w)
+	encoder := gob.NewEncoder(compressor)
+	if err := encoder.Encode(inputRecordingHeader{Version: inputRecordingVersion}); err != nil {
+		return fmt.Errorf("unable to record input: %w", err)
+	}
+	im.recorder = &inputRecorder{
+		compressor: compressor,
+		encoder:    encoder,
+		previous:   newInputState(),
+	}
+	return nil
+}
+
+// StopRecording finishes recording input, and returns the first error which
+// occurred while writing it.
+func (im *InputManager) StopRecording() error {
+	recorder := im.recorder
+	if recorder == nil {
+		return errors.New("unable to stop recording input, not recording")
+	}
+	im.recorder = nil
+
+	if err := recorder.compressor.Close(); err != nil && recorder.err == nil {
+		recorder.err = fmt.Errorf("unable to record input: %w", err)
+	}
+	return recorder.err
+}
+
+// Recording reports whether input is being recorded.
+func (im *InputManager) Recording() bool {
+	return im.recorder != nil
+}
+
+// Replay reads a recording made using Record, and replays it in place of the
+// live input, one recorded frame per frame. This works in HeadlessMode as well,
+// where gamepads that are missing are added while replaying. The gamepads
+// aren't updated while replaying, and the recorded messages about them are
+// dispatched instead. Live input is used again once the recording is over.
+func (im *InputManager) Replay(r io.Reader) error {
+	decompressor, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("unable to replay input: %w", err)
+	}
+	decoder := gob.NewDecoder(decompressor)
+
+	var header inputRecordingHeader
+	if err = decoder.Decode(&header); err != nil {
+		return fmt.Errorf("unable to replay input: %w", err)
+	}
+	if header.Version != inputRecordingVersion {
+		return fmt.Errorf("unable to replay input recorded in version %d, expected version %d", header.Version, inputRecordingVersion)
+	}
+
+	var frames []inputFrame
+	for {
+		var frame inputFrame
+		err = decoder.Decode(&frame)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("unable to replay input: %w", err)
+		}
+		frames = append(frames, frame)
+	}
+
+	im.playback = &inputPlayback{
+		frames: frames,
+		state:  newInputState(),
+	}
+	return nil
+}
+
+// StopReplay stops replaying input, so live input is used again.
+func (im *InputManager) StopReplay() {
+	im.playback = nil
+}
+
+// Replaying reports whether recorded input is being replayed.
+func (im *InputManager) Replaying() bool {
+	return im.playback != nil
+}
+
+// ReplayProgress returns the number of recorded frames that have been replayed,
+// and the number of frames in the recording.
+func (im *InputManager) ReplayProgress() (played, total int) {
+	if im.playback == nil {
+		return 0, 0
+	}
+	return im.playback.next, len(im.playback.frames)
+}
+
+// updateRecording replays and records the input of the current frame. It is
+// called right before the Scenes are updated, once all input of the frame has
+// come in.
+func (im *InputManager) updateRecording() {
+	if playback := im.playback; playback != nil {
+		if playback.next < len(playback.frames) {
+			frame := playback.frames[playback.next]
+			playback.next++
+			playback.state.apply(frame)
+			playback.state.restore(im)
+			im.gamepads.messages = replayGamepadMessages(frame.GamepadMessages)
+			dispatchGamepadMessages(im.gamepads.messages)
+		} else {
+			im.playback = nil
+			im.gamepads.messages = nil
+		}
+	}
+
+	if recorder := im.recorder; recorder != nil && recorder.err == nil {
+		state := im.snapshot()
+		frame := recorder.previous.diff(state)
+		frame.GamepadMessages = recordGamepadMessages(im.gamepads.messages)
+		if err := recorder.encoder.Encode(frame); err != nil {
+			recorder.err = fmt.Errorf("unable to record input: %w", err)
+		}
+		recorder.previous = state
+	}
+}
+
+func newInputState() inputState {
+	return inputState{
+		Keys:     make(map[string]recordedButton),
+		Mouse:    Mouse{Action: Neutral},
+		Touches:  make(map[int]Point),
+		Gamepads: make(map[string]recordedGamepad),
+	}
+}
+
+// snapshot returns the current state of all input of the InputManager
+func (im *InputManager) snapshot() inputState {
+	state := newInputState()
+	state.Mouse = im.Mouse
+	for mb := range state.MouseButtons {
+		ks := im.mouseButtons.Get(MouseButton(mb))
+		state.MouseButtons[mb] = recordedButton{Last: ks.lastState, Current: ks.currentState}
+	}
+	state.Modifier = im.Modifier
+	for id, p := range im.Touches {
+		state.Touches[id] = p
+	}
+
+	im.keys.mutex.RLock()
+	for k, ks := range im.keys.mapper {
+		if !ks.lastState && !ks.currentState {
+			continue
+		}
+		// keys without a name can't be replayed on another platform, so they
+		// are left out
+		if name, err := k.MarshalText(); err == nil {
+			state.Keys[string(name)] = recordedButton{Last: ks.lastState, Current: ks.currentState}
+		}
+	}
+	im.keys.mutex.RUnlock()
+
+	im.gamepads.mutex.RLock()
+	for name, gamepad := range im.gamepads.gamepads {
+		recorded := recordedGamepad{
+			Buttons: make(map[string]recordedButton),
+			Axes:    make(map[string]float32),
+			Last:    make(map[string]float32),
+		}
+		for _, button := range gamepadButtonNames {
+			b := gamepad.Button(button)
+			if b.lastState || b.currentState {
+				recorded.Buttons[button] = recordedButton{Last: b.lastState, Current: b.currentState}
+			}
+		}
+		for _, axis := range gamepadAxisNames {
+			if v := gamepad.Axis(axis).Raw(); v != 0 {
+				recorded.Axes[axis] = v
+			}
+			if v := gamepad.Axis(axis).last; v != 0 {
+				recorded.Last[axis] = v
+			}
+		}
+		state.Gamepads[name] = recorded
+	}
+	im.gamepads.mutex.RUnlock()
+
+	return state
+}
+
+// diff returns the inputFrame which turns s into next
+func (s inputState) diff(next inputState) inputFrame {
+	var frame inputFrame
+
+	for k, b := range next.Keys {
+		if old, ok := s.Keys[k]; !ok || old != b {
+			if frame.Keys == nil {
+				frame.Keys = make(map[string]recordedButton)
+			}
+			frame.Keys[k] = b
+		}
+	}
+	for k := range s.Keys {
+		if _, ok := next.Keys[k]; !ok {
+			if frame.Keys == nil {
+				frame.Keys = make(map[string]recordedButton)
+			}
+			frame.Keys[k] = recordedButton{}
+		}
+	}
+
+	if next.Mouse != s.Mouse {
+		mouse := next.Mouse
+		frame.Mouse = &mouse
+	}
+	if next.MouseButtons != s.MouseButtons {
+		mouseButtons := next.MouseButtons
+		frame.MouseButtons = &mouseButtons
+	}
+	if next.Modifier != s.Modifier {
+		modifier := next.Modifier
+		frame.Modifier = &modifier
+	}
+
+	if !equalTouches(s.Touches, next.Touches) {
+		frame.TouchesChanged = true
+		frame.Touches = next.Touches
+	}
+
+	for name, gamepad := range next.Gamepads {
+		if old, ok := s.Gamepads[name]; !ok || !equalGamepads(old, gamepad) {
+			if frame.Gamepads == nil {
+				frame.Gamepads = make(map[string]recordedGamepad)
+			}
+			frame.Gamepads[name] = gamepad
+		}
+	}
+
+	return frame
+}
+
+// apply updates s with what changed during the frame
+func (s *inputState) apply(frame inputFrame) {
+	for k, b := range frame.Keys {
+		if b.Last || b.Current {
+			s.Keys[k] = b
+		} else {
+			delete(s.Keys, k)
+		}
+	}
+	if frame.Mouse != nil {
+		s.Mouse = *frame.Mouse
+	}
+	if frame.MouseButtons != nil {
+		s.MouseButtons = *frame.MouseButtons
+	}
+	if frame.Modifier != nil {
+		s.Modifier = *frame.Modifier
+	}
+	if frame.TouchesChanged {
+		s.Touches = make(map[int]Point, len(frame.Touches))
+		for id, p := range frame.Touches {
+			s.Touches[id] = p
+		}
+	}
+	for name, gamepad := range frame.Gamepads {
+		s.Gamepads[name] = gamepad
+	}
+}
+
+// restore replaces the input of the InputManager with s
+func (s inputState) restore(im *InputManager) {
+	im.Mouse = s.Mouse
+	im.mouseButtons.restore(s.MouseButtons)
+	im.Modifier = s.Modifier
+	im.Touches = make(map[int]Point, len(s.Touches))
+	for id, p := range s.Touches {
+		im.Touches[id] = p
+	}
+
+	im.keys.mutex.Lock()
+	im.keys.mapper = make(map[Key]KeyState, len(s.Keys))
+	for name, b := range s.Keys {
+		var k Key
+		if err := k.UnmarshalText([]byte(name)); err != nil {
+			continue
+		}
+		im.keys.mapper[k] = KeyState{lastState: b.Last, currentState: b.Current}
+	}
+	im.keys.mutex.Unlock()
+
+	im.gamepads.mutex.Lock()
+	for name, gamepad := range im.gamepads.gamepads {
+		if _, ok := s.Gamepads[name]; !ok {
+			gamepad.release()
+		}
+	}
+	for name, recorded := range s.Gamepads {
+		gamepad, ok := im.gamepads.gamepads[name]
+		if !ok {
+			gamepad = &Gamepad{}
+			im.gamepads.gamepads[name] = gamepad
+		}
+		for _, button := range gamepadButtonNames {
+			b := recorded.Buttons[button]
+			gamepad.Button(button).lastState = b.Last
+			gamepad.Button(button).currentState = b.Current
+		}
+		for _, axis := range gamepadAxisNames {
+			ag := gamepad.Axis(axis)
+			ag.last = recorded.Last[axis]
+			ag.value, ag.raw = recorded.Axes[axis], recorded.Axes[axis]
+		}
+		gamepad.applyFilters()
+	}
+	im.gamepads.mutex.Unlock()
+}
+
+func equalTouches(a, b map[int]Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, p := range a {
+		if q, ok := b[id]; !ok || p != q {
+			return false
+		}
+	}
+	return true
+}
+
+func equalGamepads(a, b recordedGamepad) bool {
+	if len(a.Buttons) != len(b.Buttons) || len(a.Axes) != len(b.Axes) || len(a.Last) != len(b.Last) {
+		return false
+	}
+	for name, button := range a.Buttons {
+		if other, ok := b.Buttons[name]; !ok || button != other {
+			return false
+		}
+	}
+	for name, v := range a.Axes {
+		if other, ok := b.Axes[name]; !ok || v != other {
+			return false
+		}
+	}
+	for name, v := range a.Last {
+		if other, ok := b.Last[name]; !ok || v != other {
+			return false
+		}
+	}
+	return true
+}
+
+func recordGamepadMessages(messages []Message) []recordedGamepadMessage {
+	var recorded []recordedGamepadMessage
+	for _, msg := range messages {
+		switch m := msg.(type) {
+		case GamepadConnectedMessage:
+			recorded = append(recorded, recordedGamepadMessage{Kind: gamepadConnected, Name: m.Name, GUID: m.GUID})
+		case GamepadDisconnectedMessage:
+			recorded = append(recorded, recordedGamepadMessage{Kind: gamepadDisconnected, Name: m.Name, GUID: m.GUID})
+		case GamepadJoinedMessage:
+			recorded = append(recorded, recordedGamepadMessage{Kind: gamepadJoined, Name: m.Name, GUID: m.GUID})
+		}
+	}
+	return recorded
+}
+
+func replayGamepadMessages(recorded []recordedGamepadMessage) []Message {
+	var messages []Message
+	for _, m := range recorded {
+		switch m.Kind {
+		case gamepadConnected:
+			messages = append(messages, GamepadConnectedMessage{Name: m.Name, GUID: m.GUID})
+		case gamepadDisconnected:
+			messages = append(messages, GamepadDisconnectedMessage{Name: m.Name, GUID: m.GUID})
+		case gamepadJoined:
+			messages = append(messages, GamepadJoinedMessage{Name: m.Name, GUID: m.GUID})
+		}
+	}
+	return messages
+}
@@ -0,0 +1,256 @@
+package engo
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestInputRecordReplay(t *testing.T) {
+	resetScenes()
+	scene := &testStackScene{name: "testRecordScene"}
+	Run(RunOptions{
+		NoRun:         true,
+		HeadlessMode:  true,
+		FixedTickRate: 60,
+	}, scene)
+	defer SetFixedTickRate(0)
+	Input.RegisterButton("jump", KeySpace)
+
+	recording := &bytes.Buffer{}
+	if err := Input.Record(recording); err != nil {
+		t.Fatalf("Unable to record: %v", err)
+	}
+	if err := Input.Record(recording); err == nil {
+		t.Error("Recording twice should fail")
+	}
+
+	Input.gamepads.gamepads["player1"] = &Gamepad{}
+	steps := []func(){
+		func() {},
+		func() { Input.keys.Set(KeySpace, true) },
+		func() {
+			Input.Mouse.X, Input.Mouse.Y = 10, 20
+			Input.Touches[0] = Point{X: 1, Y: 2}
+			Input.mouseButtons.Set(MouseButtonRight, true)
+		},
+		func() {
+			Input.keys.Set(KeySpace, false)
+			Input.Gamepad("player1").A.set(true)
+			Input.Gamepad("player1").LeftX.set(0.5)
+		},
+		func() {
+			delete(Input.Touches, 0)
+			Input.mouseButtons.Set(MouseButtonRight, false)
+		},
+	}
+	var mice []Mouse
+	var touches []int
+	var gamepadA []bool
+	var mouseRight []KeyState
+	for _, step := range steps {
+		step()
+		Step(1, time.Second/60)
+		mice = append(mice, Input.Mouse)
+		touches = append(touches, len(Input.Touches))
+		gamepadA = append(gamepadA, Input.Gamepad("player1").A.currentState)
+		mouseRight = append(mouseRight, MouseButtonRight.state())
+	}
+	if err := Input.StopRecording(); err != nil {
+		t.Fatalf("Unable to stop recording: %v", err)
+	}
+	recorded := scene.input
+	if !reflect.DeepEqual(recorded, []bool{false, true, true, false, false}) {
+		t.Fatalf("Scene did not see the live input, got %v", recorded)
+	}
+
+	// Replay in a fresh game without any live input, and without the gamepad
+	resetScenes()
+	replayed := &testStackScene{name: "testRecordScene"}
+	Run(RunOptions{
+		NoRun:         true,
+		HeadlessMode:  true,
+		FixedTickRate: 60,
+	}, replayed)
+	Input.RegisterButton("jump", KeySpace)
+
+	if err := Input.Replay(bytes.NewReader(recording.Bytes())); err != nil {
+		t.Fatalf("Unable to replay: %v", err)
+	}
+	for i := range steps {
+		Step(1, time.Second/60)
+		if Input.Mouse != mice[i] {
+			t.Errorf("Mouse was not replayed in frame %d, got %v instead of %v", i, Input.Mouse, mice[i])
+		}
+		if res := MouseButtonRight.state(); res != mouseRight[i] {
+			t.Errorf("Mouse buttons were not replayed in frame %d, got %v instead of %v", i, res, mouseRight[i])
+		}
+		if len(Input.Touches) != touches[i] {
+			t.Errorf("Touches were not replayed in frame %d", i)
+		}
+		if gamepad := Input.Gamepad("player1"); gamepad == nil || gamepad.A.currentState != gamepadA[i] {
+			t.Errorf("Gamepad was not replayed in frame %d", i)
+		}
+	}
+	if played, total := Input.ReplayProgress(); played != len(steps) || total != len(steps) {
+		t.Errorf("All frames should have been replayed, got %d of %d", played, total)
+	}
+	if !reflect.DeepEqual(replayed.input, recorded) {
+		t.Errorf("Replayed input differs from the recording, got %v instead of %v", replayed.input, recorded)
+	}
+	if Input.Gamepad("player1").LeftX.Value() != 0.5 {
+		t.Error("Gamepad axes were not replayed")
+	}
+
+	Step(1, time.Second/60)
+	if Input.Replaying() {
+		t.Error("Replay should stop at the end of the recording")
+	}
+}
+
+func TestInputReplayInvalid(t *testing.T) {
+	im := NewInputManager()
+	if err := im.Replay(bytes.NewReader([]byte("not a recording"))); err == nil {
+		t.Error("Replaying something which is not a recording should fail")
+	}
+	if err := im.StopRecording(); err == nil {
+		t.Error("Stopping while not recording should fail")
+	}
+}
+
+func TestInputReplayFilteredAxes(t *testing.T) {
+	filter := &AxisFilter{InnerDeadZone: 0.2, Invert: true}
+	im := NewInputManager()
+	recorded := &Gamepad{}
+	recorded.SetFilter(filter)
+	im.gamepads.gamepads["player1"] = recorded
+	recorded.LeftX.set(0.6)
+	recorded.applyFilters()
+	state := im.snapshot()
+
+	replayed := NewInputManager()
+	gamepad := &Gamepad{}
+	gamepad.SetFilter(filter)
+	replayed.gamepads.gamepads["player1"] = gamepad
+	state.restore(replayed)
+
+	if gamepad.LeftX.Raw() != 0.6 {
+		t.Errorf("The raw value of the axis should have been replayed, got %v", gamepad.LeftX.Raw())
+	}
+	if gamepad.LeftX.Value() != recorded.LeftX.Value() {
+		t.Errorf("The filter should have been applied once, wanted %v, got %v", recorded.LeftX.Value(), gamepad.LeftX.Value())
+	}
+}
+
+func TestInputReplayLiveGamepad(t *testing.T) {
+	filter := &AxisFilter{Smoothing: 0.5}
+	im := NewInputManager()
+	recorded := &Gamepad{}
+	recorded.SetFilter(filter)
+	im.gamepads.gamepads["player1"] = recorded
+	recording := &bytes.Buffer{}
+	if err := im.Record(recording); err != nil {
+		t.Fatalf("Unable to record: %v", err)
+	}
+	var values []float32
+	for _, v := range []float32{1, 1, 0} {
+		recorded.LeftX.set(v)
+		recorded.applyFilters()
+		im.updateRecording()
+		values = append(values, recorded.LeftX.Value())
+	}
+	if err := im.StopRecording(); err != nil {
+		t.Fatalf("Unable to stop recording: %v", err)
+	}
+
+	replayed := NewInputManager()
+	gamepad := &Gamepad{}
+	gamepad.SetFilter(filter)
+	replayed.gamepads.gamepads["player1"] = gamepad
+	if err := replayed.Replay(bytes.NewReader(recording.Bytes())); err != nil {
+		t.Fatalf("Unable to replay: %v", err)
+	}
+	for i, v := range values {
+		// the live gamepad keeps reporting a value of its own
+		gamepad.LeftX.set(-1)
+		gamepad.A.set(true)
+		replayed.update()
+		replayed.updateRecording()
+		if gamepad.LeftX.Value() != v {
+			t.Errorf("The live gamepad changed the replay in frame %d, got %v instead of %v", i, gamepad.LeftX.Value(), v)
+		}
+		if gamepad.A.Down() {
+			t.Errorf("The live gamepad pressed a button in frame %d", i)
+		}
+	}
+}
+
+func TestInputReplayGamepadMessages(t *testing.T) {
+	mailbox := Mailbox
+	t.Cleanup(func() { Mailbox = mailbox })
+	Mailbox = &MessageManager{}
+	var messages []Message
+	ListenFor(Mailbox, func(msg GamepadConnectedMessage) {
+		messages = append(messages, msg)
+	})
+	ListenFor(Mailbox, func(msg GamepadDisconnectedMessage) {
+		messages = append(messages, msg)
+	})
+	ListenFor(Mailbox, func(msg GamepadJoinedMessage) {
+		messages = append(messages, msg)
+	})
+
+	im := NewInputManager()
+	recording := &bytes.Buffer{}
+	if err := im.Record(recording); err != nil {
+		t.Fatalf("Unable to record: %v", err)
+	}
+	frames := [][]Message{
+		{GamepadConnectedMessage{Name: "player1", GUID: "guid"}},
+		nil,
+		{GamepadDisconnectedMessage{Name: "player1", GUID: "guid"}, GamepadJoinedMessage{Name: "player2", GUID: "other"}},
+	}
+	for _, frame := range frames {
+		im.gamepads.messages = frame
+		im.updateRecording()
+	}
+	if err := im.StopRecording(); err != nil {
+		t.Fatalf("Unable to stop recording: %v", err)
+	}
+
+	replayed := NewInputManager()
+	if err := replayed.Replay(bytes.NewReader(recording.Bytes())); err != nil {
+		t.Fatalf("Unable to replay: %v", err)
+	}
+	for i, frame := range frames {
+		messages = nil
+		replayed.updateRecording()
+		if len(messages) != len(frame) || (len(frame) > 0 && !reflect.DeepEqual(messages, frame)) {
+			t.Errorf("Expected messages %v in frame %d, got %v", frame, i, messages)
+		}
+	}
+}
+
+func TestInputRecordKeyNames(t *testing.T) {
+	im := NewInputManager()
+	im.keys.Set(KeySpace, true)
+	state := im.snapshot()
+	name, err := KeySpace.MarshalText()
+	if err != nil {
+		t.Fatalf("Unable to encode the key: %v", err)
+	}
+	if b, ok := state.Keys[string(name)]; !ok || !b.Current {
+		t.Errorf("Keys should be recorded by their names, got %v", state.Keys)
+	}
+
+	state.Keys["not a key"] = recordedButton{Current: true}
+	replayed := NewInputManager()
+	state.restore(replayed)
+	if !replayed.keys.Get(KeySpace).currentState {
+		t.Error("The key should have been replayed")
+	}
+	if len(replayed.keys.mapper) != 1 {
+		t.Errorf("Unknown keys should be skipped, got %v", replayed.keys.mapper)
+	}
+}
@@ -0,0 +1,270 @@
+package engo
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultBufferLength is how long the InputManager remembers Button presses by
+// default, see SetBufferLength.
+const DefaultBufferLength = time.Second
+
+// SequenceStep is a step of an InputSequence. It is completed by pressing all
+// of its Buttons at once, e.g. "down" and "forward" for a diagonal direction,
+// or "forward" and "punch".
+type SequenceStep struct {
+	// Buttons are the names of the Buttons which have to be down, of which at
+	// least one has to have just been pressed
+	Buttons []string
+	// MaxGap is the longest time allowed since the previous step, or zero to
+	// only use the Window of the InputSequence
+	MaxGap time.Duration
+}
+
+// NewSequenceStep returns a SequenceStep which is completed by pressing the
+// given Buttons at once.
+func NewSequenceStep(buttons ...string) SequenceStep {
+	return SequenceStep{Buttons: buttons}
+}
+
+// InputSequence is a series of Button presses, such as a combo in a fighting
+// game. When its last step is completed, with all steps done in order and in
+// time, a SequenceMessage is dispatched to the Mailbox. Other Buttons may be
+// pressed in between the steps.
+type InputSequence struct {
+	// Name identifies the sequence in the SequenceMessage
+	Name string
+	// Steps have to be completed in order
+	Steps []SequenceStep
+	// Window is the longest time allowed between the first and the last step,
+	// or zero for no limit other than the buffer length of the InputManager
+	Window time.Duration
+}
+
+// SequenceMessage is dispatched to the Mailbox whenever an InputSequence is
+// completed.
+type SequenceMessage struct {
+	// Name of the InputSequence
+	Name string
+}
+
+// Type implements the Message interface.
+func (SequenceMessage) Type() string {
+	return "SequenceMessage"
+}
+
+// bufferedFrame holds the Buttons which were just pressed during a frame, and
+// the ones that were down
+type bufferedFrame struct {
+	time    time.Duration
+	pressed map[string]bool
+	down    map[string]bool
+}
+
+// inputBuffer remembers the recent Button presses, to detect InputSequences and
+// to buffer presses
+type inputBuffer struct {
+	now       time.Duration
+	length    time.Duration
+	frames    []bufferedFrame
+	sequences []InputSequence
+	// completed is when each sequence was last completed, so its steps aren't
+	// used twice
+	completed map[string]time.Duration
+	// used is when each buffered Button press was last used by ConsumeBuffered
+	used map[string]time.Duration
+}
+
+func newInputBuffer() *inputBuffer {
+	return &inputBuffer{
+		length:    DefaultBufferLength,
+		completed: make(map[string]time.Duration),
+		used:      make(map[string]time.Duration),
+	}
+}
+
+// SetBufferLength sets how long Button presses are remembered, which limits the
+// Window of the InputSequences, and how long presses can be buffered.
+func (im *InputManager) SetBufferLength(d time.Duration) {
+	im.buffer.length = d
+}
+
+// RegisterSequence registers an InputSequence, which replaces any sequence with
+// the same name. It returns an error if the sequence has no steps, or if any of
+// its steps has no Buttons.
+func (im *InputManager) RegisterSequence(seq InputSequence) error {
+	if len(seq.Steps) == 0 {
+		return fmt.Errorf("unable to register sequence %q, it has no steps", seq.Name)
+	}
+	for i, step := range seq.Steps {
+		if len(step.Buttons) == 0 {
+			return fmt.Errorf("unable to register sequence %q, step %d has no buttons", seq.Name, i)
+		}
+	}
+
+	im.RemoveSequence(seq.Name)
+	im.buffer.sequences = append(im.buffer.sequences, seq)
+	return nil
+}
+
+// RemoveSequence removes the InputSequence with the given name.
+func (im *InputManager) RemoveSequence(name string) {
+	sequences := im.buffer.sequences[:0]
+	for _, seq := range im.buffer.sequences {
+		if seq.Name != name {
+			sequences = append(sequences, seq)
+		}
+	}
+	im.buffer.sequences = sequences
+	delete(im.buffer.completed, name)
+}
+
+// Buffered reports whether the Button was pressed within the given window, and
+// that press has not been used by ConsumeBuffered yet. This lets games accept a
+// jump that was pressed just before landing.
+func (im *InputManager) Buffered(name string, window time.Duration) bool {
+	_, ok := im.buffer.lastPress(name, window)
+	return ok
+}
+
+// ConsumeBuffered reports whether the Button was pressed within the given
+// window like Buffered, and uses the press up so it's only acted upon once.
+func (im *InputManager) ConsumeBuffered(name string, window time.Duration) bool {
+	t, ok := im.buffer.lastPress(name, window)
+	if ok {
+		im.buffer.used[name] = t
+	}
+	return ok
+}
+
+// ClearBuffer forgets all Button presses, e.g. when a character gets hit and
+// combos in progress should be dropped.
+func (im *InputManager) ClearBuffer() {
+	im.buffer.frames = nil
+	for name := range im.buffer.used {
+		delete(im.buffer.used, name)
+	}
+}
+
+// lastPress returns the time of the most recent press of the Button within the
+// window which has not been used yet
+func (b *inputBuffer) lastPress(name string, window time.Duration) (time.Duration, bool) {
+	for i := len(b.frames) - 1; i >= 0; i-- {
+		frame := b.frames[i]
+		if b.now-frame.time > window {
+			break
+		}
+		if !frame.pressed[name] {
+			continue
+		}
+		if used, ok := b.used[name]; ok && used >= frame.time {
+			return 0, false
+		}
+		return frame.time, true
+	}
+	return 0, false
+}
+
+// updateBuffer remembers the Buttons that were pressed during this frame, and
+// dispatches a SequenceMessage for each InputSequence that has been completed.
+// It is called right before the Scenes are updated.
+func (im *InputManager) updateBuffer(dt float32) {
+	b := im.buffer
+	b.now += time.Duration(float64(dt) * float64(time.Second))
+
+	expired := 0
+	for expired < len(b.frames) && b.now-b.frames[expired].time > b.length {
+		expired++
+	}
+	b.frames = b.frames[expired:]
+
+	frame := bufferedFrame{
+		time:    b.now,
+		pressed: make(map[string]bool),
+		down:    make(map[string]bool),
+	}
+	im.bufferButtons(frame, im.buttons)
+	for _, ic := range im.contexts {
+		im.bufferButtons(frame, ic.buttons)
+	}
+	if len(frame.pressed) == 0 {
+		return
+	}
+	b.frames = append(b.frames, frame)
+
+	for _, seq := range b.sequences {
+		if b.match(seq) {
+			b.completed[seq.Name] = b.now
+			if Mailbox != nil {
+				Mailbox.Dispatch(SequenceMessage{Name: seq.Name})
+			}
+		}
+	}
+}
+
+// bufferButtons adds the state of the given Buttons to the frame. Buttons of a
+// context are only added if the context is active, and the name is looked up in
+// Input so a context can hide the Buttons below it.
+func (im *InputManager) bufferButtons(frame bufferedFrame, buttons map[string]Button) {
+	for name := range buttons {
+		button := im.Button(name)
+		if button.JustPressed() {
+			frame.pressed[name] = true
+			frame.down[name] = true
+		} else if button.Down() {
+			frame.down[name] = true
+		}
+	}
+}
+
+// completes reports whether the step was completed during the frame
+func (step SequenceStep) completes(frame bufferedFrame) bool {
+	pressed := false
+	for _, name := range step.Buttons {
+		if !frame.down[name] {
+			return false
+		}
+		pressed = pressed || frame.pressed[name]
+	}
+	return pressed
+}
+
+// match reports whether the sequence was completed during the latest frame. Its
+// steps are matched from the last to the first, each against the latest frame
+// in which it was completed before the next step, in time.
+func (b *inputBuffer) match(seq InputSequence) bool {
+	last := len(b.frames) - 1
+	if !seq.Steps[len(seq.Steps)-1].completes(b.frames[last]) {
+		return false
+	}
+
+	start, done := b.completed[seq.Name]
+	end := b.frames[last].time
+	next := end
+	i := last
+	for s := len(seq.Steps) - 2; s >= 0; s-- {
+		gap := seq.Steps[s+1].MaxGap
+		found := false
+		for i--; i >= 0; i-- {
+			frame := b.frames[i]
+			if done && frame.time <= start {
+				return false
+			}
+			if gap > 0 && next-frame.time > gap {
+				return false
+			}
+			if seq.Window > 0 && end-frame.time > seq.Window {
+				return false
+			}
+			if seq.Steps[s].completes(frame) {
+				found = true
+				next = frame.time
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
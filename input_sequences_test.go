@@ -0,0 +1,143 @@
+package engo
+
+import (
+	"testing"
+	"time"
+)
+
+// sequenceTestFrame presses and releases keys, and then advances the input
+// buffer by a frame
+func sequenceTestFrame(dt float32, press, release []Key) {
+	Input.update()
+	for _, k := range press {
+		Input.keys.Set(k, true)
+	}
+	for _, k := range release {
+		Input.keys.Set(k, false)
+	}
+	Input.updateBuffer(dt)
+}
+
+func setupSequenceTest(t *testing.T) *[]string {
+	input, mailbox := Input, Mailbox
+	t.Cleanup(func() { Input, Mailbox = input, mailbox })
+	Input = NewInputManager()
+	Input.RegisterButton("down", KeyS)
+	Input.RegisterButton("forward", KeyD)
+	Input.RegisterButton("punch", KeyJ)
+
+	Mailbox = &MessageManager{}
+	var completed []string
+	ListenFor(Mailbox, func(msg SequenceMessage) {
+		completed = append(completed, msg.Name)
+	})
+
+	err := Input.RegisterSequence(InputSequence{
+		Name: "fireball",
+		Steps: []SequenceStep{
+			NewSequenceStep("down"),
+			NewSequenceStep("down", "forward"),
+			{Buttons: []string{"forward", "punch"}, MaxGap: 250 * time.Millisecond},
+		},
+		Window: 500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Unable to register sequence: %v", err)
+	}
+	return &completed
+}
+
+func TestInputSequence(t *testing.T) {
+	completed := setupSequenceTest(t)
+	defer func() { Mailbox = nil }()
+
+	sequenceTestFrame(0.1, []Key{KeyS}, nil)
+	sequenceTestFrame(0.1, []Key{KeyD}, nil)
+	sequenceTestFrame(0.1, nil, []Key{KeyS})
+	if len(*completed) != 0 {
+		t.Fatalf("Sequence should not have been completed yet, got %v", *completed)
+	}
+	sequenceTestFrame(0.1, []Key{KeyJ}, nil)
+	if len(*completed) != 1 || (*completed)[0] != "fireball" {
+		t.Fatalf("Fireball should have been completed once, got %v", *completed)
+	}
+
+	// The steps of a completed sequence aren't used again
+	sequenceTestFrame(0.1, nil, []Key{KeyJ})
+	sequenceTestFrame(0.1, []Key{KeyJ}, nil)
+	if len(*completed) != 1 {
+		t.Errorf("Fireball should not have been completed again, got %v", *completed)
+	}
+}
+
+func TestInputSequenceTiming(t *testing.T) {
+	completed := setupSequenceTest(t)
+	defer func() { Mailbox = nil }()
+
+	// The last step comes too long after the previous one
+	sequenceTestFrame(0.1, []Key{KeyS}, nil)
+	sequenceTestFrame(0.1, []Key{KeyD}, nil)
+	sequenceTestFrame(0.3, []Key{KeyJ}, []Key{KeyS})
+	if len(*completed) != 0 {
+		t.Fatalf("Sequence should not have been completed after the MaxGap, got %v", *completed)
+	}
+
+	// The whole sequence takes too long
+	sequenceTestFrame(0.1, nil, []Key{KeyD, KeyJ})
+	sequenceTestFrame(0.1, []Key{KeyS}, nil)
+	sequenceTestFrame(0.4, []Key{KeyD}, nil)
+	sequenceTestFrame(0.15, []Key{KeyJ}, nil)
+	if len(*completed) != 0 {
+		t.Fatalf("Sequence should not have been completed outside the Window, got %v", *completed)
+	}
+
+	// Other input in between is fine
+	sequenceTestFrame(0.1, nil, []Key{KeyS, KeyD, KeyJ})
+	sequenceTestFrame(0.1, []Key{KeyS}, nil)
+	sequenceTestFrame(0.1, []Key{KeyJ}, nil)
+	sequenceTestFrame(0.1, []Key{KeyD}, []Key{KeyJ})
+	sequenceTestFrame(0.1, []Key{KeyJ}, []Key{KeyS})
+	if len(*completed) != 1 {
+		t.Errorf("Sequence should have been completed with other input in between, got %v", *completed)
+	}
+}
+
+func TestRegisterSequenceErrors(t *testing.T) {
+	Input = NewInputManager()
+	if err := Input.RegisterSequence(InputSequence{Name: "empty"}); err == nil {
+		t.Error("Registering a sequence without steps should have failed")
+	}
+	if err := Input.RegisterSequence(InputSequence{Name: "blank", Steps: []SequenceStep{{}}}); err == nil {
+		t.Error("Registering a sequence with a step without buttons should have failed")
+	}
+}
+
+func TestInputBuffered(t *testing.T) {
+	Input = NewInputManager()
+	Input.RegisterButton("jump", KeySpace)
+	window := 150 * time.Millisecond
+
+	sequenceTestFrame(0.1, []Key{KeySpace}, nil)
+	sequenceTestFrame(0.1, nil, []Key{KeySpace})
+	if !Input.Buffered("jump", window) {
+		t.Error("Jump should have been buffered")
+	}
+	if !Input.ConsumeBuffered("jump", window) {
+		t.Error("Jump should have been consumed")
+	}
+	if Input.Buffered("jump", window) || Input.ConsumeBuffered("jump", window) {
+		t.Error("Jump should not have been buffered once it was consumed")
+	}
+
+	sequenceTestFrame(0.1, []Key{KeySpace}, nil)
+	sequenceTestFrame(0.2, nil, []Key{KeySpace})
+	if Input.Buffered("jump", window) {
+		t.Error("Jump should not have been buffered outside the window")
+	}
+
+	sequenceTestFrame(0.1, []Key{KeySpace}, nil)
+	Input.ClearBuffer()
+	if Input.Buffered("jump", window) {
+		t.Error("Jump should not have been buffered after clearing the buffer")
+	}
+}
@@ -0,0 +1,298 @@
+package engo
+
+import "sync"
+
+// A ButtonTrigger is an input which can press a Button, such as a Key, a
+// MouseButton, a GamepadButtonTrigger or a GamepadAxisTrigger. Triggers look up
+// their state in Input when asked, so they keep working when the Gamepads are
+// registered after the Button.
+type ButtonTrigger interface {
+	// JustPressed reports whether the trigger was pressed in the current frame.
+	JustPressed() bool
+	// JustReleased reports whether the trigger was released in the current
+	// frame.
+	JustReleased() bool
+	// Down reports whether the trigger is being held down.
+	Down() bool
+}
+
+// JustPressed reports whether the Key was just pressed. It implements the
+// ButtonTrigger interface.
+func (k Key) JustPressed() bool {
+	return Input.keys.Get(k).JustPressed()
+}
+
+// JustReleased reports whether the Key was just released. It implements the
+// ButtonTrigger interface.
+func (k Key) JustReleased() bool {
+	return Input.keys.Get(k).JustReleased()
+}
+
+// Down reports whether the Key is being held down. It implements the
+// ButtonTrigger interface.
+func (k Key) Down() bool {
+	return Input.keys.Get(k).Down()
+}
+
+// state returns the state of the MouseButton, which is tracked by the mouse
+// button callbacks of the backend
+func (mb MouseButton) state() KeyState {
+	return Input.mouseButtons.Get(mb)
+}
+
+// JustPressed reports whether the MouseButton was just pressed. It implements
+// the ButtonTrigger interface.
+func (mb MouseButton) JustPressed() bool {
+	return mb.state().JustPressed()
+}
+
+// JustReleased reports whether the MouseButton was just released. It implements
+// the ButtonTrigger interface.
+func (mb MouseButton) JustReleased() bool {
+	return mb.state().JustReleased()
+}
+
+// Down reports whether the MouseButton is being held down. It implements the
+// ButtonTrigger interface.
+func (mb MouseButton) Down() bool {
+	return mb.state().Down()
+}
+
+// mouseButtonManager tracks which mouse buttons are held down, like the
+// KeyManager does for keys
+type mouseButtonManager struct {
+	mutex  sync.RWMutex
+	states [MouseButtonLast + 1]KeyState
+	// pressed are the buttons which went down since the last update
+	pressed [MouseButtonLast + 1]bool
+	// released are the buttons which went up after going down since the last
+	// update, which are released during the next update
+	released [MouseButtonLast + 1]bool
+}
+
+// Set is used for updating whether or not a mouse button is held down. It is
+// called by the backends when a mouse button is pressed or released.
+func (mm *mouseButtonManager) Set(mb MouseButton, down bool) {
+	if mb < 0 || mb > MouseButtonLast {
+		return
+	}
+	mm.mutex.Lock()
+	defer mm.mutex.Unlock()
+
+	state := &mm.states[mb]
+	if down {
+		mm.released[mb] = false
+		if !state.currentState {
+			mm.pressed[mb] = true
+			state.set(true)
+		}
+		return
+	}
+	if mm.pressed[mb] {
+		mm.released[mb] = true
+	} else if state.currentState {
+		state.set(false)
+	}
+}
+
+// Get retrieves the state of a mouse button.
+func (mm *mouseButtonManager) Get(mb MouseButton) KeyState {
+	if mb < 0 || mb > MouseButtonLast {
+		return KeyState{}
+	}
+	mm.mutex.RLock()
+	defer mm.mutex.RUnlock()
+	return mm.states[mb]
+}
+
+// restore replaces the state of all mouse buttons with the recorded one
+func (mm *mouseButtonManager) restore(recorded [MouseButtonLast + 1]recordedButton) {
+	mm.mutex.Lock()
+	defer mm.mutex.Unlock()
+
+	for mb, b := range recorded {
+		mm.states[mb] = KeyState{lastState: b.Last, currentState: b.Current}
+		mm.pressed[mb], mm.released[mb] = false, false
+	}
+}
+
+func (mm *mouseButtonManager) update() {
+	mm.mutex.Lock()
+	defer mm.mutex.Unlock()
+
+	for mb := range mm.states {
+		mm.pressed[mb] = false
+		if mm.released[mb] {
+			mm.released[mb] = false
+			mm.states[mb].set(false)
+		} else {
+			mm.states[mb].set(mm.states[mb].currentState)
+		}
+	}
+}
+
+// GamepadButtonTrigger presses a Button using a button of a Gamepad.
+type GamepadButtonTrigger struct {
+	// Gamepad is the name the Gamepad was registered with
+	Gamepad string `json:"gamepad"`
+	// Button is the name of the button, such as "A" or "DpadUp", see
+	// Gamepad.Button
+	Button string `json:"button"`
+}
+
+func (t GamepadButtonTrigger) state() GamepadButton {
+	gamepad := Input.Gamepad(t.Gamepad)
+	if gamepad == nil {
+		return GamepadButton{}
+	}
+	if button := gamepad.Button(t.Button); button != nil {
+		return *button
+	}
+	return GamepadButton{}
+}
+
+// JustPressed reports whether the gamepad button was just pressed. It
+// implements the ButtonTrigger interface.
+func (t GamepadButtonTrigger) JustPressed() bool {
+	return t.state().JustPressed()
+}
+
+// JustReleased reports whether the gamepad button was just released. It
+// implements the ButtonTrigger interface.
+func (t GamepadButtonTrigger) JustReleased() bool {
+	return t.state().JustReleased()
+}
+
+// Down reports whether the gamepad button is being held down. It implements the
+// ButtonTrigger interface.
+func (t GamepadButtonTrigger) Down() bool {
+	return t.state().Down()
+}
+
+// GamepadAxisTrigger presses a Button whenever an axis of a Gamepad is tilted
+// past a threshold, e.g. to use the triggers of a Gamepad as buttons, or to use
+// a stick for navigating a menu.
+type GamepadAxisTrigger struct {
+	// Gamepad is the name the Gamepad was registered with
+	Gamepad string `json:"gamepad"`
+	// Axis is the name of the axis, such as "LeftX" or "RightTrigger", see
+	// Gamepad.Axis
+	Axis string `json:"axis"`
+	// Threshold is the value the axis has to reach. A positive Threshold is
+	// reached by values greater than or equal to it, while a negative Threshold
+	// is reached by values less than or equal to it.
+	Threshold float32 `json:"threshold"`
+}
+
+func (t GamepadAxisTrigger) state() GamepadButton {
+	gamepad := Input.Gamepad(t.Gamepad)
+	if gamepad == nil {
+		return GamepadButton{}
+	}
+	axis := gamepad.Axis(t.Axis)
+	if axis == nil {
+		return GamepadButton{}
+	}
+	return GamepadButton{lastState: t.reached(axis.last), currentState: t.reached(axis.value)}
+}
+
+func (t GamepadAxisTrigger) reached(v float32) bool {
+	if t.Threshold < 0 {
+		return v <= t.Threshold
+	}
+	return v >= t.Threshold
+}
+
+// JustPressed reports whether the axis just reached the threshold. It
+// implements the ButtonTrigger interface.
+func (t GamepadAxisTrigger) JustPressed() bool {
+	return t.state().JustPressed()
+}
+
+// JustReleased reports whether the axis just went back from the threshold. It
+// implements the ButtonTrigger interface.
+func (t GamepadAxisTrigger) JustReleased() bool {
+	return t.state().JustReleased()
+}
+
+// Down reports whether the axis is past the threshold. It implements the
+// ButtonTrigger interface.
+func (t GamepadAxisTrigger) Down() bool {
+	return t.state().Down()
+}
+
+// RegisterButtonTriggers registers a new button input, which can be pressed
+// using any kind of ButtonTrigger. Keys end up in the Triggers of the Button,
+// and the other triggers in its Extra triggers.
+func (im *InputManager) RegisterButtonTriggers(name string, triggers ...ButtonTrigger) {
+	im.buttons[name] = newButton(name, nil, triggers)
+	im.chords.invalidate()
+}
+
+// AddButtonTriggers adds triggers to the Button with the given name, which is
+// registered if it doesn't exist yet. Unlike RegisterButtonTriggers, it keeps
+// the triggers the Button already has.
+func (im *InputManager) AddButtonTriggers(name string, triggers ...ButtonTrigger) {
+	button := newButton(name, nil, triggers)
+	old := im.buttons[name]
+	button.Triggers = append(append([]Key(nil), old.Triggers...), button.Triggers...)
+	button.Extra = append(append([]ButtonTrigger(nil), old.Extra...), button.Extra...)
+	im.buttons[name] = button
+	im.chords.invalidate()
+}
+
+// newButton creates a Button within the given context, splitting its triggers
+// into keys and other triggers
+func newButton(name string, ic *InputContext, triggers []ButtonTrigger) Button {
+	button := Button{Name: name, context: ic}
+	for _, trigger := range triggers {
+		if k, ok := trigger.(Key); ok {
+			button.Triggers = append(button.Triggers, k)
+		} else {
+			button.Extra = append(button.Extra, trigger)
+		}
+	}
+	return button
+}
+
+// AxisTriggerPair is a set of Min/Max ButtonTriggers used as an Axis, e.g. to
+// move using the directional pad of a Gamepad.
+type AxisTriggerPair struct {
+	Min ButtonTrigger
+	Max ButtonTrigger
+}
+
+// Value returns AxisMax while the Max trigger is down, AxisMin while the Min
+// trigger is down, and AxisNeutral otherwise.
+func (pair AxisTriggerPair) Value() float32 {
+	if pair.Max != nil && pair.Max.Down() {
+		return AxisMax
+	} else if pair.Min != nil && pair.Min.Down() {
+		return AxisMin
+	}
+
+	return AxisNeutral
+}
+
+// AxisGamepadPair uses an axis of a Gamepad as an Axis. Unlike using the
+// AxisGamepad of a Gamepad directly, it looks up the Gamepad by name, so it
+// keeps working when the Gamepad is registered later on.
+type AxisGamepadPair struct {
+	// Gamepad is the name the Gamepad was registered with
+	Gamepad string `json:"gamepad"`
+	// Axis is the name of the axis, such as "LeftX" or "RightTrigger", see
+	// Gamepad.Axis
+	Axis string `json:"axis"`
+}
+
+// Value returns the value of the gamepad axis, from -1 to 1.
+func (pair AxisGamepadPair) Value() float32 {
+	gamepad := Input.Gamepad(pair.Gamepad)
+	if gamepad == nil {
+		return AxisNeutral
+	}
+	if axis := gamepad.Axis(pair.Axis); axis != nil {
+		return axis.Value()
+	}
+	return AxisNeutral
+}
@@ -0,0 +1,158 @@
+package engo
+
+import "testing"
+
+func TestButtonTriggers(t *testing.T) {
+	Input = NewInputManager()
+	Input.gamepads.gamepads["player1"] = &Gamepad{}
+	Input.RegisterButtonTriggers("jump",
+		KeySpace,
+		MouseButtonRight,
+		GamepadButtonTrigger{Gamepad: "player1", Button: "A"},
+		GamepadAxisTrigger{Gamepad: "player1", Axis: "RightTrigger", Threshold: 0.5},
+		GamepadButtonTrigger{Gamepad: "player2", Button: "A"},
+	)
+
+	jump := Input.Button("jump")
+	if len(jump.Triggers) != 1 || jump.Triggers[0] != KeySpace || len(jump.Extra) != 4 {
+		t.Fatalf("Keys should end up in the Triggers, and the others in Extra, got %v and %v", jump.Triggers, jump.Extra)
+	}
+
+	type frame struct {
+		input                           func()
+		justPressed, down, justReleased bool
+	}
+	gamepad := Input.Gamepad("player1")
+	frames := []frame{
+		{input: func() {}},
+		// Keys
+		{input: func() { Input.keys.Set(KeySpace, true) }, justPressed: true},
+		{input: func() {}, down: true},
+		{input: func() { Input.keys.Set(KeySpace, false) }, justReleased: true},
+		// Mouse buttons, only the right one is bound
+		{input: func() { Input.mouseButtons.Set(MouseButtonLeft, true) }},
+		{input: func() { Input.mouseButtons.Set(MouseButtonRight, true) }, justPressed: true},
+		{input: func() {}, down: true},
+		{input: func() { Input.mouseButtons.Set(MouseButtonRight, false) }, justReleased: true},
+		// A click within a single frame is pressed during that frame, and
+		// released during the next one
+		{input: func() {
+			Input.mouseButtons.Set(MouseButtonLeft, false)
+			Input.mouseButtons.Set(MouseButtonRight, true)
+			Input.mouseButtons.Set(MouseButtonRight, false)
+		}, justPressed: true},
+		{input: func() {}, justReleased: true},
+		{input: func() {}},
+		// Gamepad buttons
+		{input: func() { gamepad.A.set(true) }, justPressed: true},
+		{input: func() { gamepad.A.set(true) }, down: true},
+		{input: func() { gamepad.A.set(false) }, justReleased: true},
+		{input: func() { gamepad.A.set(false) }},
+		// Gamepad axes, past the threshold
+		{input: func() { gamepad.RightTrigger.set(0.4) }},
+		{input: func() { gamepad.RightTrigger.set(0.6) }, justPressed: true},
+		{input: func() { gamepad.RightTrigger.set(1) }, down: true},
+		{input: func() { gamepad.RightTrigger.set(0.2) }, justReleased: true},
+		{input: func() { gamepad.RightTrigger.set(0) }},
+	}
+	for i, f := range frames {
+		Input.update()
+		f.input()
+
+		jump := Input.Button("jump")
+		if jump.JustPressed() != f.justPressed || jump.Down() != f.down || jump.JustReleased() != f.justReleased {
+			t.Errorf("Button had the wrong state in frame %d, got JustPressed %v, Down %v and JustReleased %v", i,
+				jump.JustPressed(), jump.Down(), jump.JustReleased())
+		}
+	}
+}
+
+func TestMouseButtonsSameFrame(t *testing.T) {
+	Input = NewInputManager()
+	Input.update()
+	Input.mouseButtons.Set(MouseButtonLeft, true)
+	Input.mouseButtons.Set(MouseButtonRight, true)
+	if !MouseButtonLeft.JustPressed() || !MouseButtonRight.JustPressed() {
+		t.Error("Both buttons which were pressed in the same frame should have been JustPressed")
+	}
+
+	Input.update()
+	Input.mouseButtons.Set(MouseButtonLeft, false)
+	if !MouseButtonLeft.JustReleased() || !MouseButtonRight.Down() {
+		t.Error("Releasing a button should have left the other one down")
+	}
+
+	Input.update()
+	if !MouseButtonLeft.state().Up() || !MouseButtonRight.Down() {
+		t.Error("The released button should have been up")
+	}
+	if MouseButton(-1).Down() || (MouseButtonLast + 1).Down() {
+		t.Error("Unknown mouse buttons should never be down")
+	}
+}
+
+func TestGamepadAxisTriggerNegative(t *testing.T) {
+	Input = NewInputManager()
+	Input.gamepads.gamepads["player1"] = &Gamepad{}
+	left := GamepadAxisTrigger{Gamepad: "player1", Axis: "LeftX", Threshold: -0.5}
+
+	Input.Gamepad("player1").LeftX.set(-0.7)
+	if !left.JustPressed() || left.Down() {
+		t.Error("Negative thresholds should be reached by values below them")
+	}
+	Input.Gamepad("player1").LeftX.set(0.7)
+	if !left.JustReleased() {
+		t.Error("Going back from a negative threshold should release the trigger")
+	}
+	if (GamepadAxisTrigger{Gamepad: "player1", Axis: "Unknown", Threshold: 0.5}).Down() {
+		t.Error("Unknown axes should never be down")
+	}
+}
+
+func TestAxisTriggerPairs(t *testing.T) {
+	Input = NewInputManager()
+	Input.gamepads.gamepads["player1"] = &Gamepad{}
+	Input.RegisterAxis("horizontal",
+		AxisTriggerPair{
+			Min: GamepadButtonTrigger{Gamepad: "player1", Button: "DpadLeft"},
+			Max: GamepadButtonTrigger{Gamepad: "player1", Button: "DpadRight"},
+		},
+		AxisGamepadPair{Gamepad: "player1", Axis: "LeftX"},
+		AxisGamepadPair{Gamepad: "player2", Axis: "LeftX"},
+	)
+
+	gamepad := Input.Gamepad("player1")
+	gamepad.LeftX.set(0.25)
+	if v := Input.Axis("horizontal").Value(); v != 0.25 {
+		t.Errorf("Axis should use the gamepad axis, got %v", v)
+	}
+
+	gamepad.DpadLeft.set(true)
+	gamepad.DpadLeft.set(true)
+	if v := Input.Axis("horizontal").Value(); v != AxisMin {
+		t.Errorf("Axis should use the directional pad, got %v", v)
+	}
+}
+
+func TestAddTriggersAndPairs(t *testing.T) {
+	Input = NewInputManager()
+	Input.RegisterButton("jump", KeySpace)
+	Input.AddButtonTriggers("jump", KeyW, MouseButtonRight)
+	Input.AddButtonTriggers("fire", KeyF)
+
+	jump := Input.Button("jump")
+	if len(jump.Triggers) != 2 || jump.Triggers[0] != KeySpace || len(jump.Extra) != 1 {
+		t.Errorf("Triggers should have been added to the existing ones, got %v and %v", jump.Triggers, jump.Extra)
+	}
+	if fire := Input.Button("fire"); fire.Name != "fire" || len(fire.Triggers) != 1 {
+		t.Errorf("Adding triggers to an unknown Button should have registered it, got %+v", fire)
+	}
+
+	Input.RegisterAxis("horizontal", AxisKeyPair{Min: KeyA, Max: KeyD})
+	Input.AddAxisPairs("horizontal", AxisKeyPair{Min: KeyArrowLeft, Max: KeyArrowRight})
+	Input.keys.Set(KeyArrowRight, true)
+	Input.keys.Set(KeyArrowRight, true)
+	if horizontal := Input.Axis("horizontal"); len(horizontal.Pairs) != 2 || horizontal.Value() != AxisMax {
+		t.Errorf("Pairs should have been added to the existing ones, got %v", horizontal.Pairs)
+	}
+}
@@ -0,0 +1,160 @@
+package engo
+
+import "fmt"
+
+// keyNames holds the name of each Key, which is the name of its constant
+// without the "Key" prefix. The values of the keys differ between platforms, so
+// their names are used wherever keys are stored.
+var keyNames = []struct {
+	key  Key
+	name string
+}{
+	{KeyA, "A"},
+	{KeyApostrophe, "Apostrophe"},
+	{KeyArrowDown, "ArrowDown"},
+	{KeyArrowLeft, "ArrowLeft"},
+	{KeyArrowRight, "ArrowRight"},
+	{KeyArrowUp, "ArrowUp"},
+	{KeyB, "B"},
+	{KeyBackslash, "Backslash"},
+	{KeyBackspace, "Backspace"},
+	{KeyC, "C"},
+	{KeyCapsLock, "CapsLock"},
+	{KeyComma, "Comma"},
+	{KeyD, "D"},
+	{KeyDash, "Dash"},
+	{KeyDelete, "Delete"},
+	{KeyE, "E"},
+	{KeyEight, "Eight"},
+	{KeyEnd, "End"},
+	{KeyEnter, "Enter"},
+	{KeyEquals, "Equals"},
+	{KeyEscape, "Escape"},
+	{KeyF, "F"},
+	{KeyF1, "F1"},
+	{KeyF10, "F10"},
+	{KeyF11, "F11"},
+	{KeyF12, "F12"},
+	{KeyF2, "F2"},
+	{KeyF3, "F3"},
+	{KeyF4, "F4"},
+	{KeyF5, "F5"},
+	{KeyF6, "F6"},
+	{KeyF7, "F7"},
+	{KeyF8, "F8"},
+	{KeyF9, "F9"},
+	{KeyFive, "Five"},
+	{KeyFour, "Four"},
+	{KeyG, "G"},
+	{KeyGrave, "Grave"},
+	{KeyH, "H"},
+	{KeyHome, "Home"},
+	{KeyI, "I"},
+	{KeyInsert, "Insert"},
+	{KeyJ, "J"},
+	{KeyK, "K"},
+	{KeyL, "L"},
+	{KeyLeftAlt, "LeftAlt"},
+	{KeyLeftBracket, "LeftBracket"},
+	{KeyLeftControl, "LeftControl"},
+	{KeyLeftShift, "LeftShift"},
+	{KeyLeftSuper, "LeftSuper"},
+	{KeyM, "M"},
+	{KeyN, "N"},
+	{KeyNine, "Nine"},
+	{KeyNumAdd, "NumAdd"},
+	{KeyNumDecimal, "NumDecimal"},
+	{KeyNumDivide, "NumDivide"},
+	{KeyNumEight, "NumEight"},
+	{KeyNumEnter, "NumEnter"},
+	{KeyNumFive, "NumFive"},
+	{KeyNumFour, "NumFour"},
+	{KeyNumLock, "NumLock"},
+	{KeyNumMultiply, "NumMultiply"},
+	{KeyNumNine, "NumNine"},
+	{KeyNumOne, "NumOne"},
+	{KeyNumSeven, "NumSeven"},
+	{KeyNumSix, "NumSix"},
+	{KeyNumSubtract, "NumSubtract"},
+	{KeyNumThree, "NumThree"},
+	{KeyNumTwo, "NumTwo"},
+	{KeyNumZero, "NumZero"},
+	{KeyO, "O"},
+	{KeyOne, "One"},
+	{KeyP, "P"},
+	{KeyPageDown, "PageDown"},
+	{KeyPageUp, "PageUp"},
+	{KeyPause, "Pause"},
+	{KeyPeriod, "Period"},
+	{KeyPrintScreen, "PrintScreen"},
+	{KeyQ, "Q"},
+	{KeyR, "R"},
+	{KeyRightAlt, "RightAlt"},
+	{KeyRightBracket, "RightBracket"},
+	{KeyRightControl, "RightControl"},
+	{KeyRightShift, "RightShift"},
+	{KeyRightSuper, "RightSuper"},
+	{KeyS, "S"},
+	{KeyScrollLock, "ScrollLock"},
+	{KeySemicolon, "Semicolon"},
+	{KeySeven, "Seven"},
+	{KeySix, "Six"},
+	{KeySlash, "Slash"},
+	{KeySpace, "Space"},
+	{KeyT, "T"},
+	{KeyTab, "Tab"},
+	{KeyThree, "Three"},
+	{KeyTwo, "Two"},
+	{KeyU, "U"},
+	{KeyV, "V"},
+	{KeyW, "W"},
+	{KeyX, "X"},
+	{KeyY, "Y"},
+	{KeyZ, "Z"},
+	{KeyZero, "Zero"},
+}
+
+var (
+	keysByName = make(map[string]Key, len(keyNames))
+	namesByKey = make(map[Key]string, len(keyNames))
+)
+
+func init() {
+	for _, kn := range keyNames {
+		keysByName[kn.name] = kn.key
+		// Some platforms use the same value for several keys, the first name is
+		// used for those
+		if _, ok := namesByKey[kn.key]; !ok {
+			namesByKey[kn.key] = kn.name
+		}
+	}
+}
+
+// String returns the name of the Key, which is the name of its constant without
+// the "Key" prefix, e.g. "A" for KeyA or "ArrowLeft" for KeyArrowLeft.
+func (k Key) String() string {
+	if name, ok := namesByKey[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("Key(%d)", int(k))
+}
+
+// MarshalText encodes the Key using its name, so stored keys work on all
+// platforms.
+func (k Key) MarshalText() ([]byte, error) {
+	name, ok := namesByKey[k]
+	if !ok {
+		return nil, fmt.Errorf("unable to encode unknown key %d", int(k))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a Key from its name.
+func (k *Key) UnmarshalText(text []byte) error {
+	key, ok := keysByName[string(text)]
+	if !ok {
+		return fmt.Errorf("unknown key %q", string(text))
+	}
+	*k = key
+	return nil
+}
@@ -28,6 +28,10 @@ type KeyManager struct {
 	dirtmap map[Key]Key
 	mapper  map[Key]KeyState
 	mutex   sync.RWMutex
+
+	// pressed are the keys which went down since the last update, in the order
+	// they were pressed
+	pressed []Key
 }
 
 // Set is used for updating whether or not a key is held down, or not held down.
@@ -35,6 +39,9 @@ func (km *KeyManager) Set(k Key, state bool) {
 	km.mutex.Lock()
 
 	ks := km.mapper[k]
+	if state && !ks.currentState {
+		km.pressed = append(km.pressed, k)
+	}
 	ks.set(state)
 	km.mapper[k] = ks
 	km.dirtmap[k] = k
@@ -54,6 +61,8 @@ func (km *KeyManager) Get(k Key) KeyState {
 func (km *KeyManager) update() {
 	km.mutex.Lock()
 
+	km.pressed = km.pressed[:0]
+
 	// Update the state on all the dirty keys
 	for _, key := range km.dirtmap {
 		delete(km.dirtmap, key)
@@ -285,6 +285,142 @@ func (m *Matrix) RotateRad(rad float32) *Matrix {
 	return m
 }
 
+// Shear shears m by x along the x axis and by y along the y axis, so the point (px, py)
+// ends up at (px + x*py, py + y*px).
+func (m *Matrix) Shear(x, y float32) *Matrix {
+	m.tmp[m00] = 1
+	m.tmp[m10] = y
+	m.tmp[m20] = 0
+
+	m.tmp[m01] = x
+	m.tmp[m11] = 1
+	m.tmp[m21] = 0
+
+	m.tmp[m02] = 0
+	m.tmp[m12] = 0
+	m.tmp[m22] = 1
+	multiplyMatricies(m.Val[:], m.tmp[:])
+	return m
+}
+
+// Determinant returns the determinant of m. The determinant of an affine transformation
+// is the factor by which it scales areas, which is negative if it mirrors them, and
+// zero if it can't be inverted.
+func (m *Matrix) Determinant() float32 {
+	v := m.Val
+	return v[m00]*(v[m11]*v[m22]-v[m12]*v[m21]) -
+		v[m01]*(v[m10]*v[m22]-v[m12]*v[m20]) +
+		v[m02]*(v[m10]*v[m21]-v[m11]*v[m20])
+}
+
+// Invert sets m to its inverse, which undoes the transformation of m, and returns m. If
+// m can't be inverted, because its determinant is zero within Epsilon, m is left
+// untouched and false is returned.
+func (m *Matrix) Invert() (*Matrix, bool) {
+	det := m.Determinant()
+	if FloatEqual(det, 0) {
+		return m, false
+	}
+	v := m.Val
+	inv := 1 / det
+	m.Val[m00] = (v[m11]*v[m22] - v[m12]*v[m21]) * inv
+	m.Val[m01] = (v[m02]*v[m21] - v[m01]*v[m22]) * inv
+	m.Val[m02] = (v[m01]*v[m12] - v[m02]*v[m11]) * inv
+	m.Val[m10] = (v[m12]*v[m20] - v[m10]*v[m22]) * inv
+	m.Val[m11] = (v[m00]*v[m22] - v[m02]*v[m20]) * inv
+	m.Val[m12] = (v[m02]*v[m10] - v[m00]*v[m12]) * inv
+	m.Val[m20] = (v[m10]*v[m21] - v[m11]*v[m20]) * inv
+	m.Val[m21] = (v[m01]*v[m20] - v[m00]*v[m21]) * inv
+	m.Val[m22] = (v[m00]*v[m11] - v[m01]*v[m10]) * inv
+	return m, true
+}
+
+// Clone returns a new matrix with the same values as m.
+func (m *Matrix) Clone() *Matrix {
+	return &Matrix{Val: m.Val}
+}
+
+// Equal reports whether m and m2 are equal, within Epsilon.
+func (m *Matrix) Equal(m2 *Matrix) bool {
+	for i := range m.Val {
+		if !FloatEqual(m.Val[i], m2.Val[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// TransformPoint returns the point p transformed by m.
+func (m *Matrix) TransformPoint(p Point) Point {
+	return *p.MultiplyMatrixVector(m)
+}
+
+// TransformVector returns the vector v transformed by m, without the translation of m,
+// e.g. to transform a direction or a size.
+func (m *Matrix) TransformVector(v Point) Point {
+	return Point{
+		X: m.Val[m00]*v.X + m.Val[m01]*v.Y,
+		Y: m.Val[m10]*v.X + m.Val[m11]*v.Y,
+	}
+}
+
+// InverseTransformPoint returns the point p transformed by the inverse of m, e.g. to
+// turn a point in the world into a point relative to an entity which is transformed by
+// m. It returns false if m can't be inverted.
+func (m *Matrix) InverseTransformPoint(p Point) (Point, bool) {
+	inv, ok := m.Clone().Invert()
+	if !ok {
+		return p, false
+	}
+	return inv.TransformPoint(p), true
+}
+
+// AffineTransform is an affine transformation split into its components, which are
+// applied in the order of scaling, skewing, rotating and translating.
+type AffineTransform struct {
+	// Translation moves the points
+	Translation Point
+	// Rotation rotates the points counter-clockwise, in degrees
+	Rotation float32
+	// Scale scales the points along both axes. A negative scale mirrors them.
+	Scale Point
+	// Skew shears the points along the x axis, in degrees
+	Skew float32
+}
+
+// Compose sets m to the affine transformation made of the components of t, and returns
+// m.
+func (m *Matrix) Compose(t AffineTransform) *Matrix {
+	return m.Identity().
+		Translate(t.Translation.X, t.Translation.Y).
+		Rotate(t.Rotation).
+		Shear(math.Tan(t.Skew*DegToRad), 0).
+		Scale(t.Scale.X, t.Scale.Y)
+}
+
+// Decompose splits the affine transformation m into its components, so that composing
+// them results in m again. Mirroring is expressed using a negative Scale.Y. If m has no
+// area, because it scales an axis to zero, the Rotation, Scale and Skew are only
+// partially recovered.
+func (m *Matrix) Decompose() AffineTransform {
+	a, b := m.Val[m00], m.Val[m10]
+	c, d := m.Val[m01], m.Val[m11]
+
+	t := AffineTransform{Translation: Point{X: m.Val[m02], Y: m.Val[m12]}}
+	sx := math.Sqrt(a*a + b*b)
+	if sx == 0 {
+		t.Scale.Y = math.Sqrt(c*c + d*d)
+		return t
+	}
+	sy := (a*d - b*c) / sx
+	t.Rotation = math.Atan2(b, a) * RadToDeg
+	t.Scale = Point{X: sx, Y: sy}
+	if sy != 0 {
+		t.Skew = math.Atan((a*c+b*d)/(sx*sy)) * RadToDeg
+	}
+	return t
+}
+
 // PointSide returns which side of the line l the point p sits on
 // true means the point is below/left of the line
 // false means the point is above/right of the line or touching the line
@@ -544,3 +680,14 @@ func (p *Point) MultiplyMatrixVector(m *Matrix) *Point {
 	p.X, p.Y = x, y
 	return p
 }
+
+// MultiplyInverseMatrixVector multiplies the inverse of the matrix m with the point and
+// returns the result, undoing MultiplyMatrixVector. If m can't be inverted, p is left
+// untouched and false is returned.
+func (p *Point) MultiplyInverseMatrixVector(m *Matrix) (*Point, bool) {
+	inv, ok := m.Clone().Invert()
+	if !ok {
+		return p, false
+	}
+	return p.MultiplyMatrixVector(inv), true
+}
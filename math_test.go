@@ -1587,3 +1587,152 @@ func TestMultipleMatrixVector(t *testing.T) {
 		}
 	}
 }
+
+func TestMatrixDeterminant(t *testing.T) {
+	data := []struct {
+		matrix   *Matrix
+		expected float32
+	}{
+		{IdentityMatrix(), 1},
+		{IdentityMatrix().Translate(10, -5), 1},
+		{IdentityMatrix().Scale(2, 3), 6},
+		{IdentityMatrix().Rotate(30).Scale(2, 3), 6},
+		{IdentityMatrix().Scale(-1, 1), -1},
+		{IdentityMatrix().Shear(0.5, 0), 1},
+		{IdentityMatrix().Scale(0, 1), 0},
+		{new(Matrix).Set([]float32{1, 2, 3, 4, 5, 6, 7, 8, 0}), 27},
+	}
+
+	for _, d := range data {
+		if res := d.matrix.Determinant(); !FloatEqual(res, d.expected) {
+			t.Errorf("Determinant did not return the expected value.\nMatrix: %v\nWanted: %v\nGot: %v", d.matrix.Val, d.expected, res)
+		}
+	}
+}
+
+func TestMatrixInvert(t *testing.T) {
+	data := []*Matrix{
+		IdentityMatrix(),
+		IdentityMatrix().Translate(10, -5),
+		IdentityMatrix().Translate(3, 4).Rotate(45).Scale(2, 0.5),
+		IdentityMatrix().Shear(0.3, -0.2).Scale(-1, 2),
+		new(Matrix).Set([]float32{1, 2, 3, 4, 5, 6, 7, 8, 0}),
+	}
+
+	for _, m := range data {
+		inv, ok := m.Clone().Invert()
+		if !ok {
+			t.Errorf("Invert should have succeeded for %v", m.Val)
+			continue
+		}
+		if res := m.Clone().Multiply(inv); !res.Equal(IdentityMatrix()) {
+			t.Errorf("Matrix times its inverse should have been the identity.\nMatrix: %v\nInverse: %v\nGot: %v", m.Val, inv.Val, res.Val)
+		}
+		if res := inv.Clone().Multiply(m); !res.Equal(IdentityMatrix()) {
+			t.Errorf("Inverse times its matrix should have been the identity.\nMatrix: %v\nInverse: %v\nGot: %v", m.Val, inv.Val, res.Val)
+		}
+	}
+
+	singular := new(Matrix).Set([]float32{1, 2, 0, 2, 4, 0, 5, 6, 1})
+	before := singular.Val
+	if _, ok := singular.Invert(); ok {
+		t.Error("Invert should have failed for a matrix without area")
+	}
+	if singular.Val != before {
+		t.Errorf("Invert should have left a singular matrix untouched, got %v", singular.Val)
+	}
+
+	nearlySingular := IdentityMatrix().Scale(1e-8, 1e-8)
+	if _, ok := nearlySingular.Invert(); ok {
+		t.Errorf("Invert should have failed for a nearly singular matrix, got %v", nearlySingular.Val)
+	}
+}
+
+func TestMatrixShear(t *testing.T) {
+	data := []struct {
+		x, y     float32
+		p, wants Point
+	}{
+		{0, 0, Point{1, 2}, Point{1, 2}},
+		{0.5, 0, Point{1, 2}, Point{2, 2}},
+		{0, 0.5, Point{2, 1}, Point{2, 2}},
+		{1, -1, Point{1, 1}, Point{2, 0}},
+	}
+
+	for _, d := range data {
+		if res := IdentityMatrix().Shear(d.x, d.y).TransformPoint(d.p); !res.Equal(d.wants) {
+			t.Errorf("Shear(%v, %v) did not move %v to %v, got %v", d.x, d.y, d.p, d.wants, res)
+		}
+	}
+}
+
+func TestMatrixTransformVector(t *testing.T) {
+	m := IdentityMatrix().Translate(100, 200).Scale(2, 3)
+	if res := m.TransformVector(Point{1, 1}); !res.Equal(Point{2, 3}) {
+		t.Errorf("TransformVector should have ignored the translation, got %v", res)
+	}
+	if res := m.TransformPoint(Point{1, 1}); !res.Equal(Point{102, 203}) {
+		t.Errorf("TransformPoint should have applied the translation, got %v", res)
+	}
+}
+
+func TestMatrixInverseTransformPoint(t *testing.T) {
+	m := IdentityMatrix().Translate(50, -20).Rotate(30).Shear(0.2, 0).Scale(2, -1.5)
+	points := []Point{{0, 0}, {1, 0}, {-3, 7}, {120.5, 33.25}}
+
+	for _, p := range points {
+		world := m.TransformPoint(p)
+		local, ok := m.InverseTransformPoint(world)
+		if !ok || !local.Equal(p) {
+			t.Errorf("InverseTransformPoint should have undone TransformPoint.\nPoint: %v\nGot: %v", p, local)
+		}
+
+		res := world
+		if _, ok := res.MultiplyInverseMatrixVector(m); !ok || !res.Equal(p) {
+			t.Errorf("MultiplyInverseMatrixVector should have undone MultiplyMatrixVector.\nPoint: %v\nGot: %v", p, res)
+		}
+	}
+
+	singular := IdentityMatrix().Scale(0, 1)
+	if _, ok := singular.InverseTransformPoint(Point{1, 1}); ok {
+		t.Error("InverseTransformPoint should have failed for a matrix without area")
+	}
+	p := Point{1, 1}
+	if _, ok := p.MultiplyInverseMatrixVector(singular); ok || !p.Equal(Point{1, 1}) {
+		t.Errorf("MultiplyInverseMatrixVector should have failed and left the point untouched, got %v", p)
+	}
+}
+
+func TestMatrixComposeDecompose(t *testing.T) {
+	data := []AffineTransform{
+		{Scale: Point{1, 1}},
+		{Translation: Point{10, -5}, Scale: Point{1, 1}},
+		{Rotation: 45, Scale: Point{2, 0.5}},
+		{Translation: Point{3, 4}, Rotation: -120, Scale: Point{1.5, 1.5}, Skew: 20},
+		{Translation: Point{-7, 0}, Rotation: 90, Scale: Point{3, -2}, Skew: -35},
+	}
+
+	for _, d := range data {
+		m := new(Matrix).Compose(d)
+		res := m.Decompose()
+		if !res.Translation.Equal(d.Translation) || !FloatEqual(res.Rotation, d.Rotation) ||
+			!res.Scale.Equal(d.Scale) || !FloatEqual(res.Skew, d.Skew) {
+			t.Errorf("Decompose did not return the composed components.\nWanted: %+v\nGot: %+v", d, res)
+		}
+		if again := new(Matrix).Compose(res); !again.Equal(m) {
+			t.Errorf("Composing the decomposed components did not return the matrix.\nWanted: %v\nGot: %v", m.Val, again.Val)
+		}
+	}
+
+	m := IdentityMatrix().Translate(3, 4).Rotate(30).Scale(2, 2)
+	composed := new(Matrix).Compose(AffineTransform{Translation: Point{3, 4}, Rotation: 30, Scale: Point{2, 2}})
+	if !composed.Equal(m) {
+		t.Errorf("Compose should have translated, rotated and scaled in that order.\nWanted: %v\nGot: %v", m.Val, composed.Val)
+	}
+
+	// A mirrored matrix keeps its orientation when recomposed
+	mirrored := IdentityMatrix().Scale(-1, 1)
+	if again := new(Matrix).Compose(mirrored.Decompose()); !again.Equal(mirrored) {
+		t.Errorf("Composing a decomposed mirror did not return it.\nWanted: %v\nGot: %v", mirrored.Val, again.Val)
+	}
+}
@@ -24,6 +24,8 @@ func getNewHandlerID() MessageHandlerId {
 type HandlerIDPair struct {
 	MessageHandlerId
 	MessageHandler
+	// Priority decides the order handlers are called in, from high to low
+	Priority int
 }
 
 // A Message is used to send messages within the MessageManager
@@ -39,6 +41,45 @@ type MessageManager struct {
 	sync.RWMutex
 	listeners        map[string][]HandlerIDPair
 	handlersToRemove map[string][]MessageHandlerId
+
+	// queue holds the messages that have been posted, until they are flushed
+	queue      []Message
+	queueMutex sync.Mutex
+
+	middleware []middlewareIDPair
+}
+
+// A MessageMiddleware is called for every message a MessageManager dispatches, before any of its
+// handlers. Returning false drops the message, so it doesn't reach the handlers or the middleware
+// added after it.
+type MessageMiddleware func(msg Message) bool
+
+type middlewareIDPair struct {
+	id         MessageHandlerId
+	middleware MessageMiddleware
+}
+
+// A Consumer is a Message which can be consumed by a handler. Once consumed, it does not reach the
+// handlers with a lower priority anymore.
+type Consumer interface {
+	Message
+	Consumed() bool
+}
+
+// Consumable can be embedded in a Message to turn it into a Consumer. The Message has to be
+// dispatched as a pointer for handlers to be able to consume it.
+type Consumable struct {
+	consumed bool
+}
+
+// Consume stops the Message from propagating to the handlers that haven't received it yet.
+func (c *Consumable) Consume() {
+	c.consumed = true
+}
+
+// Consumed reports whether the Message has been consumed by one of its handlers.
+func (c *Consumable) Consumed() bool {
+	return c.consumed
 }
 
 // Dispatch sends a message to all subscribed handlers of the message's type
@@ -54,24 +95,104 @@ func (mm *MessageManager) Dispatch(message Message) {
 	for i := range pairs {
 		handlers[i] = pairs[i].MessageHandler
 	}
+	middleware := mm.middleware
 	mm.RUnlock()
 
+	for _, pair := range middleware {
+		if !pair.middleware(message) {
+			return
+		}
+	}
+
+	consumer, consumable := message.(Consumer)
 	for _, handler := range handlers {
 		handler(message)
+		if consumable && consumer.Consumed() {
+			break
+		}
+	}
+
+}
+
+// Post queues a message, to be dispatched the next time the MessageManager is flushed, which
+// happens right after its Scene is updated. Unlike Dispatch, it is safe to call from any goroutine.
+func (mm *MessageManager) Post(message Message) {
+	mm.queueMutex.Lock()
+	mm.queue = append(mm.queue, message)
+	mm.queueMutex.Unlock()
+}
+
+// Flush dispatches all messages that have been posted, in the order they were posted. Messages
+// posted by their handlers are queued until the next Flush.
+func (mm *MessageManager) Flush() {
+	mm.queueMutex.Lock()
+	queue := mm.queue
+	mm.queue = nil
+	mm.queueMutex.Unlock()
+
+	for _, message := range queue {
+		mm.Dispatch(message)
 	}
+}
 
+// Use adds middleware which gets to see, and possibly drop, every message that is dispatched from
+// now on. Middleware is called in the order it was added.
+func (mm *MessageManager) Use(middleware MessageMiddleware) MessageHandlerId {
+	mm.Lock()
+	defer mm.Unlock()
+
+	id := getNewHandlerID()
+	// Dispatch iterates over the slice without holding the lock, so it has to be copied rather than
+	// appended to
+	pairs := make([]middlewareIDPair, len(mm.middleware), len(mm.middleware)+1)
+	copy(pairs, mm.middleware)
+	mm.middleware = append(pairs, middlewareIDPair{id: id, middleware: middleware})
+	return id
+}
+
+// StopUsing removes middleware which was added using Use.
+func (mm *MessageManager) StopUsing(id MessageHandlerId) {
+	mm.Lock()
+	defer mm.Unlock()
+
+	pairs := make([]middlewareIDPair, 0, len(mm.middleware))
+	for _, pair := range mm.middleware {
+		if pair.id != id {
+			pairs = append(pairs, pair)
+		}
+	}
+	mm.middleware = pairs
 }
 
 // Listen subscribes to the specified message type and calls the specified handler when fired
 func (mm *MessageManager) Listen(messageType string, handler MessageHandler) MessageHandlerId {
+	return mm.ListenWithPriority(messageType, 0, handler)
+}
+
+// ListenWithPriority subscribes to the specified message type like Listen, but calls the handler
+// before all handlers with a lower priority. Handlers subscribed using Listen have a priority of 0,
+// and handlers with the same priority are called in the order they subscribed.
+func (mm *MessageManager) ListenWithPriority(messageType string, priority int, handler MessageHandler) MessageHandlerId {
 	mm.Lock()
 	defer mm.Unlock()
 	if mm.listeners == nil {
 		mm.listeners = make(map[string][]HandlerIDPair)
 	}
 	handlerID := getNewHandlerID()
-	newHandlerIDPair := HandlerIDPair{MessageHandlerId: handlerID, MessageHandler: handler}
-	mm.listeners[messageType] = append(mm.listeners[messageType], newHandlerIDPair)
+	newHandlerIDPair := HandlerIDPair{MessageHandlerId: handlerID, MessageHandler: handler, Priority: priority}
+
+	pairs := mm.listeners[messageType]
+	index := len(pairs)
+	for i, pair := range pairs {
+		if pair.Priority < priority {
+			index = i
+			break
+		}
+	}
+	pairs = append(pairs, HandlerIDPair{})
+	copy(pairs[index+1:], pairs[index:])
+	pairs[index] = newHandlerIDPair
+	mm.listeners[messageType] = pairs
 	return handlerID
 }
 
@@ -0,0 +1,39 @@
+package engo
+
+import "reflect"
+
+// messageType returns the type of the messages of type T, as returned by their
+// Type method. T has to be a concrete type, such as WindowResizeMessage or
+// *WindowResizeMessage, since the type of a nil interface is unknown.
+func messageType[T Message]() string {
+	var msg T
+	if t := reflect.TypeOf(&msg).Elem(); t.Kind() == reflect.Pointer {
+		msg = reflect.New(t.Elem()).Interface().(T)
+	}
+	return msg.Type()
+}
+
+// ListenFor subscribes handler to the messages of type T, so it doesn't have to
+// type-assert them. Handlers for T and *T don't receive each other's messages.
+func ListenFor[T Message](mm *MessageManager, handler func(T)) MessageHandlerId {
+	return mm.Listen(messageType[T](), func(msg Message) {
+		if m, ok := msg.(T); ok {
+			handler(m)
+		}
+	})
+}
+
+// ListenOnceFor subscribes handler to the next message of type T, like
+// ListenFor, and unsubscribes it afterwards.
+func ListenOnceFor[T Message](mm *MessageManager, handler func(T)) {
+	var handlerID MessageHandlerId
+	handlerID = ListenFor(mm, func(msg T) {
+		handler(msg)
+		StopListenFor[T](mm, handlerID)
+	})
+}
+
+// StopListenFor removes a handler which was added using ListenFor.
+func StopListenFor[T Message](mm *MessageManager, handlerID MessageHandlerId) {
+	mm.StopListen(messageType[T](), handlerID)
+}
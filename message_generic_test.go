@@ -0,0 +1,82 @@
+package engo
+
+import "testing"
+
+type testGenericMessage struct {
+	value int
+}
+
+func (testGenericMessage) Type() string {
+	return "testGenericMessage"
+}
+
+func TestListenFor(t *testing.T) {
+	mailbox := &MessageManager{}
+	var received []int
+	ListenFor(mailbox, func(msg testGenericMessage) {
+		received = append(received, msg.value)
+	})
+
+	mailbox.Dispatch(testGenericMessage{value: 1})
+	mailbox.Dispatch(testGenericMessage{value: 2})
+	if len(received) != 2 || received[0] != 1 || received[1] != 2 {
+		t.Errorf("Handler should have received both messages, got %v", received)
+	}
+}
+
+func TestListenForCompatibility(t *testing.T) {
+	mailbox := &MessageManager{}
+	var typed, untyped int
+	ListenFor(mailbox, func(msg testGenericMessage) {
+		typed++
+	})
+	mailbox.Listen("testGenericMessage", func(msg Message) {
+		untyped++
+	})
+
+	mailbox.Dispatch(testGenericMessage{})
+	if typed != 1 || untyped != 1 {
+		t.Errorf("Both handlers should have received the message, got %d and %d", typed, untyped)
+	}
+
+	// Pointers share the message type, but only reach handlers for the pointer
+	// type
+	var pointers int
+	ListenFor(mailbox, func(msg *testGenericMessage) {
+		pointers++
+		msg.value++
+	})
+	msg := &testGenericMessage{}
+	mailbox.Dispatch(msg)
+	if typed != 1 || untyped != 2 || pointers != 1 || msg.value != 1 {
+		t.Errorf("Only the pointer and untyped handlers should have received the pointer, got %d, %d and %d", typed, untyped, pointers)
+	}
+}
+
+func TestStopListenFor(t *testing.T) {
+	mailbox := &MessageManager{}
+	counter := 0
+	handlerID := ListenFor(mailbox, func(msg testGenericMessage) {
+		counter++
+	})
+	mailbox.Dispatch(testGenericMessage{})
+
+	StopListenFor[testGenericMessage](mailbox, handlerID)
+	mailbox.Dispatch(testGenericMessage{})
+	if counter != 1 {
+		t.Errorf("Handler should have been called exactly 1 times since it was removed, got %d", counter)
+	}
+}
+
+func TestListenOnceFor(t *testing.T) {
+	mailbox := &MessageManager{}
+	counter := 0
+	ListenOnceFor(mailbox, func(msg *testGenericMessage) {
+		counter++
+	})
+	mailbox.Dispatch(&testGenericMessage{})
+	mailbox.Dispatch(&testGenericMessage{})
+	if counter != 1 {
+		t.Errorf("Handler should have been called exactly 1 times, got %d", counter)
+	}
+}
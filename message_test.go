@@ -1,6 +1,11 @@
 package engo
 
-import "testing"
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
 
 type testMessageCounter struct {
 	counter, counter2 int
@@ -109,3 +114,148 @@ func TestMessageRemoveNotExistAndMultipleMessageTypes(t *testing.T) {
 		t.Error("Message counter should be 1. Only one message was dispatched to it")
 	}
 }
+
+type testConsumableMessage struct {
+	Consumable
+	received []string
+}
+
+func (*testConsumableMessage) Type() string {
+	return "testConsumableMessage"
+}
+
+func TestMessagePriority(t *testing.T) {
+	mailbox := &MessageManager{}
+	var order []string
+	mailbox.Listen("testMessageCounter", func(Message) { order = append(order, "default") })
+	mailbox.ListenWithPriority("testMessageCounter", 10, func(Message) { order = append(order, "high") })
+	mailbox.ListenWithPriority("testMessageCounter", -10, func(Message) { order = append(order, "low") })
+	mailbox.Listen("testMessageCounter", func(Message) { order = append(order, "default2") })
+
+	mailbox.Dispatch(testMessageCounter{})
+	expected := []string{"high", "default", "default2", "low"}
+	if strings.Join(order, ",") != strings.Join(expected, ",") {
+		t.Errorf("Handlers should be called by priority, then in order of subscription, got %v", order)
+	}
+}
+
+func TestMessageConsume(t *testing.T) {
+	mailbox := &MessageManager{}
+	mailbox.ListenWithPriority("testConsumableMessage", 1, func(message Message) {
+		m := message.(*testConsumableMessage)
+		m.received = append(m.received, "first")
+		m.Consume()
+	})
+	mailbox.Listen("testConsumableMessage", func(message Message) {
+		m := message.(*testConsumableMessage)
+		m.received = append(m.received, "second")
+	})
+
+	msg := &testConsumableMessage{}
+	mailbox.Dispatch(msg)
+	if len(msg.received) != 1 || !msg.Consumed() {
+		t.Errorf("A consumed message should not reach handlers with a lower priority, got %v", msg.received)
+	}
+}
+
+func TestMessagePost(t *testing.T) {
+	mailbox := &MessageManager{}
+	msg := testMessageCounter{}
+	mailbox.Listen("testMessageCounter", func(message Message) {
+		m := message.(*testMessageCounter)
+		m.counter++
+		// Posting from a handler is delivered by the next Flush
+		if m.counter == 1 {
+			mailbox.Post(m)
+		}
+	})
+
+	mailbox.Post(&msg)
+	if msg.counter != 0 {
+		t.Error("Posted messages should not be dispatched before flushing")
+	}
+	mailbox.Flush()
+	if msg.counter != 1 {
+		t.Errorf("Posted message should have been received 1 times by now, got %d", msg.counter)
+	}
+	mailbox.Flush()
+	if msg.counter != 2 {
+		t.Errorf("Message posted by a handler should have been received by the next flush, got %d", msg.counter)
+	}
+	mailbox.Flush()
+	if msg.counter != 2 {
+		t.Errorf("Flushed messages should only be dispatched once, got %d", msg.counter)
+	}
+}
+
+func TestMessagePostConcurrent(t *testing.T) {
+	mailbox := &MessageManager{}
+	received := 0
+	mailbox.Listen("testMessageCounter", func(Message) { received++ })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				mailbox.Post(testMessageCounter{})
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	mailbox.Flush()
+	if received != 100 {
+		t.Errorf("All posted messages should have been received, got %d", received)
+	}
+}
+
+func TestMessagePostFlushedBySceneUpdate(t *testing.T) {
+	resetScenes()
+	Run(RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, &testStackScene{name: "testPostScene"})
+
+	received := 0
+	Mailbox.Listen("testMessageCounter", func(Message) { received++ })
+	Mailbox.Post(testMessageCounter{})
+	if err := Step(1, 10*time.Millisecond); err != nil {
+		t.Fatalf("Unable to step: %v", err)
+	}
+	if received != 1 {
+		t.Errorf("Posted message should have been delivered after the scene was updated, got %d", received)
+	}
+}
+
+func TestMessageMiddleware(t *testing.T) {
+	mailbox := &MessageManager{}
+	var order []string
+	mailbox.Listen("testMessageCounter", func(Message) { order = append(order, "handler") })
+	mailbox.Use(func(Message) bool {
+		order = append(order, "first")
+		return true
+	})
+	dropID := mailbox.Use(func(Message) bool {
+		order = append(order, "drop")
+		return false
+	})
+	mailbox.Use(func(Message) bool {
+		order = append(order, "last")
+		return true
+	})
+
+	mailbox.Dispatch(testMessageCounter{})
+	if strings.Join(order, ",") != "first,drop" {
+		t.Errorf("Dropped messages should not reach later middleware or handlers, got %v", order)
+	}
+
+	order = nil
+	mailbox.StopUsing(dropID)
+	mailbox.Dispatch(testMessageCounter{})
+	if strings.Join(order, ",") != "first,last,handler" {
+		t.Errorf("Middleware should run in order before the handlers, got %v", order)
+	}
+}
@@ -0,0 +1,242 @@
+package engo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	messageTypesMutex sync.RWMutex
+	// messageTypes are the types of messages which can be decoded from a
+	// recording, by the value of their Type method
+	messageTypes = map[string]reflect.Type{
+		WindowResizeMessage{}.Type(): reflect.TypeOf(WindowResizeMessage{}),
+		TextMessage{}.Type():         reflect.TypeOf(TextMessage{}),
+	}
+)
+
+// RegisterMessageType allows messages of the same type as msg to be decoded
+// from a recording, so they can be replayed. Whether msg is a pointer or not
+// decides whether the decoded messages are pointers as well. The messages of
+// engo itself are registered already.
+func RegisterMessageType(msg Message) {
+	messageTypesMutex.Lock()
+	messageTypes[msg.Type()] = reflect.TypeOf(msg)
+	messageTypesMutex.Unlock()
+}
+
+// RecordedMessage is a message that has been recorded by a MessageTracer.
+type RecordedMessage struct {
+	// Frame is the number of frames Time had ticked when the message was
+	// dispatched
+	Frame uint64 `json:"frame"`
+	// Time is the number of seconds Time had been running when the message was
+	// dispatched
+	Time float32 `json:"time"`
+	// Timestamp is the wall clock time the message was dispatched at
+	Timestamp time.Time `json:"timestamp"`
+	// Type is the value returned by the Type method of the message
+	Type string `json:"type"`
+	// Message holds the exported fields of the message, encoded as JSON
+	Message json.RawMessage `json:"message"`
+}
+
+// Decode turns the RecordedMessage back into the message that was dispatched.
+// Only exported fields are restored. The type of the message has to be
+// registered using RegisterMessageType.
+func (rm RecordedMessage) Decode() (Message, error) {
+	messageTypesMutex.RLock()
+	t, ok := messageTypes[rm.Type]
+	messageTypesMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unable to decode %q, its type has not been registered", rm.Type)
+	}
+
+	if t.Kind() == reflect.Pointer {
+		msg := reflect.New(t.Elem())
+		if err := json.Unmarshal(rm.Message, msg.Interface()); err != nil {
+			return nil, fmt.Errorf("unable to decode %q: %w", rm.Type, err)
+		}
+		return msg.Interface().(Message), nil
+	}
+
+	msg := reflect.New(t)
+	if err := json.Unmarshal(rm.Message, msg.Interface()); err != nil {
+		return nil, fmt.Errorf("unable to decode %q: %w", rm.Type, err)
+	}
+	return msg.Elem().Interface().(Message), nil
+}
+
+// MessageTracer keeps track of the messages dispatched by a MessageManager, for
+// debugging. It can log them, count them and record them to a file, which can
+// be replayed later using ReplayMessages. Use Trace to start tracing the
+// messages of a MessageManager. Keep in mind each Scene has its own Mailbox.
+type MessageTracer struct {
+	// Filter decides which messages are traced. All messages are traced when it
+	// is nil.
+	Filter func(Message) bool
+	// Logger logs each traced message when it is set.
+	Logger *log.Logger
+
+	mutex   sync.Mutex
+	counts  map[string]int
+	encoder *json.Encoder
+	err     error
+}
+
+// Trace starts tracing the messages dispatched by the given MessageManager. The
+// returned id can be passed to StopUsing to stop tracing.
+func (mt *MessageTracer) Trace(mm *MessageManager) MessageHandlerId {
+	return mm.Use(mt.trace)
+}
+
+// Record starts writing the traced messages to w, as one JSON encoded
+// RecordedMessage per line. Passing nil stops recording.
+func (mt *MessageTracer) Record(w io.Writer) {
+	mt.mutex.Lock()
+	defer mt.mutex.Unlock()
+
+	if w == nil {
+		mt.encoder = nil
+		return
+	}
+	mt.encoder = json.NewEncoder(w)
+}
+
+// Count returns the number of messages of the given type that have been traced.
+func (mt *MessageTracer) Count(messageType string) int {
+	mt.mutex.Lock()
+	defer mt.mutex.Unlock()
+
+	return mt.counts[messageType]
+}
+
+// Counts returns the number of messages that have been traced, by their type.
+func (mt *MessageTracer) Counts() map[string]int {
+	mt.mutex.Lock()
+	defer mt.mutex.Unlock()
+
+	counts := make(map[string]int, len(mt.counts))
+	for messageType, count := range mt.counts {
+		counts[messageType] = count
+	}
+	return counts
+}
+
+// Err returns the first error that occurred while recording. Messages that
+// can't be encoded are left out of the recording.
+func (mt *MessageTracer) Err() error {
+	mt.mutex.Lock()
+	defer mt.mutex.Unlock()
+
+	return mt.err
+}
+
+// trace is the MessageMiddleware of the MessageTracer. It never drops any
+// messages.
+func (mt *MessageTracer) trace(msg Message) bool {
+	if mt.Filter != nil && !mt.Filter(msg) {
+		return true
+	}
+
+	recorded := RecordedMessage{
+		Timestamp: time.Now(),
+		Type:      msg.Type(),
+	}
+	if Time != nil {
+		recorded.Frame = Time.Frames()
+		recorded.Time = Time.Time()
+	}
+
+	mt.mutex.Lock()
+	defer mt.mutex.Unlock()
+
+	if mt.counts == nil {
+		mt.counts = make(map[string]int)
+	}
+	mt.counts[recorded.Type]++
+
+	if mt.Logger != nil {
+		mt.Logger.Printf("[frame %d] %s: %+v", recorded.Frame, recorded.Type, msg)
+	}
+
+	if mt.encoder != nil {
+		data, err := json.Marshal(msg)
+		if err == nil {
+			recorded.Message = data
+			err = mt.encoder.Encode(recorded)
+		}
+		if err != nil && mt.err == nil {
+			mt.err = fmt.Errorf("unable to record %q: %w", recorded.Type, err)
+		}
+	}
+	return true
+}
+
+// ReadMessageRecording reads the messages which were recorded by a
+// MessageTracer.
+func ReadMessageRecording(r io.Reader) ([]RecordedMessage, error) {
+	var recording []RecordedMessage
+	decoder := json.NewDecoder(r)
+	for {
+		var recorded RecordedMessage
+		err := decoder.Decode(&recorded)
+		if errors.Is(err, io.EOF) {
+			return recording, nil
+		}
+		if err != nil {
+			return recording, err
+		}
+		recording = append(recording, recorded)
+	}
+}
+
+// ReplayMessages steps the current Scene one frame of dt at a time, like Step,
+// and dispatches the recorded messages in the frames they were recorded in.
+// Like Step, this only works in HeadlessMode.
+func ReplayMessages(recording []RecordedMessage, dt time.Duration) error {
+	if !opts.HeadlessMode {
+		return errors.New("unable to replay messages outside of HeadlessMode")
+	}
+	if currentScene == nil {
+		return errors.New("unable to replay messages without a scene, call engo.Run first")
+	}
+	if len(recording) == 0 {
+		return nil
+	}
+
+	// Messages may have been recorded from multiple Mailboxes
+	sorted := make([]RecordedMessage, len(recording))
+	copy(sorted, recording)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Frame < sorted[j].Frame
+	})
+
+	messages := make([]Message, len(sorted))
+	for i, recorded := range sorted {
+		msg, err := recorded.Decode()
+		if err != nil {
+			return err
+		}
+		messages[i] = msg
+	}
+
+	frame := sorted[0].Frame
+	for i, recorded := range sorted {
+		if recorded.Frame > frame {
+			if err := Step(int(recorded.Frame-frame), dt); err != nil {
+				return err
+			}
+			frame = recorded.Frame
+		}
+		Mailbox.Dispatch(messages[i])
+	}
+	return Step(1, dt)
+}
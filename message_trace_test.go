@@ -0,0 +1,166 @@
+package engo
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testTracedMessage struct {
+	Value  int
+	hidden int
+}
+
+func (*testTracedMessage) Type() string {
+	return "testTracedMessage"
+}
+
+type testUnregisteredMessage struct{}
+
+func (testUnregisteredMessage) Type() string {
+	return "testUnregisteredMessage"
+}
+
+func TestMessageTracerCounts(t *testing.T) {
+	mailbox := &MessageManager{}
+	tracer := &MessageTracer{
+		Filter: func(msg Message) bool { return msg.Type() != "TextMessage" },
+	}
+	tracer.Trace(mailbox)
+
+	mailbox.Dispatch(WindowResizeMessage{})
+	mailbox.Dispatch(WindowResizeMessage{})
+	mailbox.Dispatch(TextMessage{})
+	mailbox.Dispatch(&testTracedMessage{})
+
+	if tracer.Count("WindowResizeMessage") != 2 || tracer.Count("testTracedMessage") != 1 {
+		t.Errorf("Traced messages were not counted correctly, got %v", tracer.Counts())
+	}
+	if tracer.Count("TextMessage") != 0 {
+		t.Error("Filtered messages should not be traced")
+	}
+}
+
+func TestMessageTracerLog(t *testing.T) {
+	mailbox := &MessageManager{}
+	out := &bytes.Buffer{}
+	tracer := &MessageTracer{Logger: log.New(out, "", 0)}
+	id := tracer.Trace(mailbox)
+
+	received := 0
+	mailbox.Listen("TextMessage", func(Message) { received++ })
+	mailbox.Dispatch(TextMessage{Char: 'a'})
+	if received != 1 {
+		t.Error("Tracing should not stop messages from being dispatched")
+	}
+	if !strings.Contains(out.String(), "TextMessage: {Char:97}") {
+		t.Errorf("Traced message was not logged, got %q", out.String())
+	}
+
+	mailbox.StopUsing(id)
+	mailbox.Dispatch(TextMessage{Char: 'b'})
+	if strings.Count(out.String(), "\n") != 1 {
+		t.Errorf("Messages should not be traced after stopping, got %q", out.String())
+	}
+}
+
+func TestMessageTracerRecord(t *testing.T) {
+	RegisterMessageType(&testTracedMessage{})
+
+	mailbox := &MessageManager{}
+	out := &bytes.Buffer{}
+	tracer := &MessageTracer{}
+	tracer.Trace(mailbox)
+	tracer.Record(out)
+
+	mailbox.Dispatch(&testTracedMessage{Value: 3, hidden: 4})
+	mailbox.Dispatch(WindowResizeMessage{OldWidth: 1, NewWidth: 2})
+	mailbox.Dispatch(testUnregisteredMessage{})
+	tracer.Record(nil)
+	mailbox.Dispatch(TextMessage{})
+
+	if err := tracer.Err(); err != nil {
+		t.Fatalf("Unable to record: %v", err)
+	}
+	recording, err := ReadMessageRecording(out)
+	if err != nil {
+		t.Fatalf("Unable to read recording: %v", err)
+	}
+	if len(recording) != 3 {
+		t.Fatalf("Recording should contain 3 messages, got %d", len(recording))
+	}
+
+	msg, err := recording[0].Decode()
+	if err != nil {
+		t.Fatalf("Unable to decode message: %v", err)
+	}
+	if traced, ok := msg.(*testTracedMessage); !ok || traced.Value != 3 || traced.hidden != 0 {
+		t.Errorf("Only the exported fields should have been restored, got %#v", msg)
+	}
+
+	msg, err = recording[1].Decode()
+	if err != nil {
+		t.Fatalf("Unable to decode message: %v", err)
+	}
+	if resize, ok := msg.(WindowResizeMessage); !ok || resize.OldWidth != 1 || resize.NewWidth != 2 {
+		t.Errorf("Message was not decoded correctly, got %#v", msg)
+	}
+
+	if _, err = recording[2].Decode(); err == nil {
+		t.Error("Messages of unregistered types should not be decoded")
+	}
+}
+
+func TestReplayMessages(t *testing.T) {
+	resetScenes()
+	Run(RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, &testStackScene{name: "testReplayScene"})
+
+	out := &bytes.Buffer{}
+	tracer := &MessageTracer{}
+	tracer.Trace(Mailbox)
+	tracer.Record(out)
+
+	Step(2, 10*time.Millisecond)
+	Mailbox.Dispatch(TextMessage{Char: 'a'})
+	Mailbox.Dispatch(TextMessage{Char: 'b'})
+	Step(3, 10*time.Millisecond)
+	Mailbox.Dispatch(TextMessage{Char: 'c'})
+
+	recording, err := ReadMessageRecording(out)
+	if err != nil {
+		t.Fatalf("Unable to read recording: %v", err)
+	}
+
+	// Replay into a fresh scene
+	resetScenes()
+	Run(RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, &testStackScene{name: "testReplayScene"})
+	Step(1, 10*time.Millisecond)
+
+	var chars []rune
+	var frames []uint64
+	Mailbox.Listen("TextMessage", func(msg Message) {
+		chars = append(chars, msg.(TextMessage).Char)
+		frames = append(frames, Time.Frames())
+	})
+	if err = ReplayMessages(recording, 10*time.Millisecond); err != nil {
+		t.Fatalf("Unable to replay: %v", err)
+	}
+
+	if string(chars) != "abc" {
+		t.Errorf("Messages were not replayed in order, got %q", string(chars))
+	}
+	if len(frames) != 3 || frames[0] != 1 || frames[1] != 1 || frames[2] != 4 {
+		t.Errorf("Messages were not replayed at the same relative frames, got %v", frames)
+	}
+	if Time.Frames() != 5 {
+		t.Errorf("Scene should have been stepped one frame past the last message, got %d frames", Time.Frames())
+	}
+}
@@ -0,0 +1,77 @@
+package noise
+
+const (
+	// DefaultLacunarity is the Lacunarity of a Fractal created by NewFractal,
+	// which doubles the frequency of each octave.
+	DefaultLacunarity = 2
+	// DefaultPersistence is the Persistence of a Fractal created by NewFractal,
+	// which halves the amplitude of each octave.
+	DefaultPersistence = 0.5
+)
+
+// Fractal adds up several octaves of a Noise, each one with a higher frequency
+// and a lower amplitude than the one before it, also known as fractional
+// Brownian motion. Its values stay within the range of the Noise.
+type Fractal struct {
+	// Noise is the noise of which the octaves are added up.
+	Noise Noise
+	// Octaves is the number of octaves. A Fractal with less than one octave is
+	// always 0.
+	Octaves int
+	// Frequency scales the coordinates of the first octave.
+	Frequency float32
+	// Lacunarity multiplies the frequency of each next octave.
+	Lacunarity float32
+	// Persistence multiplies the amplitude of each next octave.
+	Persistence float32
+}
+
+// NewFractal returns a Fractal of the noise with the given number of octaves, a
+// Frequency of 1, and the DefaultLacunarity and DefaultPersistence.
+func NewFractal(noise Noise, octaves int) *Fractal {
+	return &Fractal{
+		Noise:       noise,
+		Octaves:     octaves,
+		Frequency:   1,
+		Lacunarity:  DefaultLacunarity,
+		Persistence: DefaultPersistence,
+	}
+}
+
+// sum adds up the octaves of sample, which returns the noise at the given
+// frequency
+func (f *Fractal) sum(sample func(frequency float32) float32) float32 {
+	var total, amplitudes float32
+	frequency, amplitude := f.Frequency, float32(1)
+	for i := 0; i < f.Octaves; i++ {
+		total += amplitude * sample(frequency)
+		amplitudes += amplitude
+		frequency *= f.Lacunarity
+		amplitude *= f.Persistence
+	}
+	if amplitudes == 0 {
+		return 0
+	}
+	return total / amplitudes
+}
+
+// Noise1D returns the noise at x.
+func (f *Fractal) Noise1D(x float32) float32 {
+	return f.sum(func(frequency float32) float32 {
+		return f.Noise.Noise1D(x * frequency)
+	})
+}
+
+// Noise2D returns the noise at x, y.
+func (f *Fractal) Noise2D(x, y float32) float32 {
+	return f.sum(func(frequency float32) float32 {
+		return f.Noise.Noise2D(x*frequency, y*frequency)
+	})
+}
+
+// Noise3D returns the noise at x, y, z.
+func (f *Fractal) Noise3D(x, y, z float32) float32 {
+	return f.sum(func(frequency float32) float32 {
+		return f.Noise.Noise3D(x*frequency, y*frequency, z*frequency)
+	})
+}
@@ -0,0 +1,113 @@
+// Package noise contains coherent noise functions, which give random values
+// that change smoothly, for generating terrain, textures and other procedural
+// content. Each kind of noise is created from a seed, and the same seed always
+// gives the same noise, without using any global state.
+package noise
+
+import "github.com/EngoEngine/engo/random"
+
+// Noise is a coherent noise function in one, two and three dimensions.
+type Noise interface {
+	Noise1D(x float32) float32
+	Noise2D(x, y float32) float32
+	Noise3D(x, y, z float32) float32
+}
+
+// permutation holds the numbers from 0 to 255 in a random order, twice, so the
+// lookups of neighbouring cells don't have to wrap around
+type permutation [512]uint8
+
+// newPermutation returns the permutation for the seed
+func newPermutation(seed uint64) *permutation {
+	p := &permutation{}
+	for i, v := range random.New(seed).Perm(256) {
+		p[i] = uint8(v)
+		p[i+256] = uint8(v)
+	}
+	return p
+}
+
+// hash1 returns the hash of the cell at x
+func (p *permutation) hash1(x int32) uint8 {
+	return p[x&255]
+}
+
+// hash2 returns the hash of the cell at x, y
+func (p *permutation) hash2(x, y int32) uint8 {
+	return p[int(p[x&255])+int(y&255)]
+}
+
+// hash3 returns the hash of the cell at x, y, z
+func (p *permutation) hash3(x, y, z int32) uint8 {
+	return p[int(p[int(p[x&255])+int(y&255)])+int(z&255)]
+}
+
+// floor returns the largest int32 which is less than or equal to x
+func floor(x float32) int32 {
+	i := int32(x)
+	if float32(i) > x {
+		i--
+	}
+	return i
+}
+
+// lerp interpolates from a to b, where t goes from 0 to 1
+func lerp(t, a, b float32) float32 {
+	return a + t*(b-a)
+}
+
+// grad1 returns the dot product of x and one of 16 gradients from -8 to 8,
+// picked by the hash
+func grad1(hash uint8, x float32) float32 {
+	g := float32(hash&7) + 1
+	if hash&8 != 0 {
+		g = -g
+	}
+	return g * x
+}
+
+// grad2 returns the dot product of x, y and one of 8 gradients, picked by the
+// hash
+func grad2(hash uint8, x, y float32) float32 {
+	switch hash & 7 {
+	case 0:
+		return x + y
+	case 1:
+		return -x + y
+	case 2:
+		return x - y
+	case 3:
+		return -x - y
+	case 4:
+		return x
+	case 5:
+		return -x
+	case 6:
+		return y
+	default:
+		return -y
+	}
+}
+
+// grad3 returns the dot product of x, y, z and one of the 12 gradients pointing
+// to the edges of a cube, picked by the hash
+func grad3(hash uint8, x, y, z float32) float32 {
+	h := hash & 15
+	u, v := x, y
+	if h >= 8 {
+		u = y
+	}
+	if h >= 4 {
+		v = z
+		if h == 12 || h == 14 {
+			v = x
+		}
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
@@ -0,0 +1,123 @@
+package noise
+
+import "testing"
+
+func noises(seed uint64) map[string]Noise {
+	return map[string]Noise{
+		"Perlin":  NewPerlin(seed),
+		"Simplex": NewSimplex(seed),
+		"Worley":  NewWorley(seed),
+		"Fractal": NewFractal(NewSimplex(seed), 4),
+	}
+}
+
+// sample returns the 1D, 2D and 3D noise at points spread over a few cells
+func sample(n Noise, i int) [3]float32 {
+	x, y, z := float32(i%31)*0.173-2.5, float32(i/31)*0.219+0.1, float32(i%7)*0.457
+	return [3]float32{n.Noise1D(x), n.Noise2D(x, y), n.Noise3D(x, y, z)}
+}
+
+func TestNoiseDeterministic(t *testing.T) {
+	a, b, other := noises(1), noises(1), noises(2)
+	for name := range a {
+		differs := false
+		for i := 0; i < 500; i++ {
+			v := sample(a[name], i)
+			if res := sample(b[name], i); res != v {
+				t.Fatalf("%s should have been the same for the same seed, wanted %v, got %v", name, v, res)
+			}
+			if sample(other[name], i) != v {
+				differs = true
+			}
+		}
+		if !differs {
+			t.Errorf("%s should have been different for another seed", name)
+		}
+	}
+}
+
+func TestNoiseRange(t *testing.T) {
+	for name, n := range noises(3) {
+		low, high := float32(-1), float32(1)
+		if name == "Worley" {
+			low, high = 0, 2
+		}
+		var min, max [3]float32
+		for i := 0; i < 5000; i++ {
+			for d, v := range sample(n, i) {
+				if v < low || v > high {
+					t.Fatalf("%s in %dD should have been within [%v, %v], got %v", name, d+1, low, high, v)
+				}
+				if i == 0 || v < min[d] {
+					min[d] = v
+				}
+				if i == 0 || v > max[d] {
+					max[d] = v
+				}
+			}
+		}
+		for d := range min {
+			if max[d]-min[d] < 0.5 {
+				t.Errorf("%s in %dD should have varied, got values from %v to %v", name, d+1, min[d], max[d])
+			}
+		}
+	}
+}
+
+func TestNoiseSmooth(t *testing.T) {
+	const step = 0.001
+	for name, n := range noises(4) {
+		for i := 0; i < 500; i++ {
+			x, y, z := float32(i)*0.0371, float32(i)*0.0213, float32(i)*0.0157
+			if d := n.Noise2D(x+step, y) - n.Noise2D(x, y); d > 0.05 || d < -0.05 {
+				t.Fatalf("%s should have changed smoothly at %v, %v, changed by %v", name, x, y, d)
+			}
+			if d := n.Noise3D(x, y, z+step) - n.Noise3D(x, y, z); d > 0.05 || d < -0.05 {
+				t.Fatalf("%s should have changed smoothly at %v, %v, %v, changed by %v", name, x, y, z, d)
+			}
+		}
+	}
+}
+
+func TestGradientNoiseAtWholeCoordinates(t *testing.T) {
+	p := NewPerlin(5)
+	for i := int32(-3); i < 3; i++ {
+		x := float32(i)
+		if res := p.Noise1D(x) + p.Noise2D(x, x+1) + p.Noise3D(x, 2, x-1); res != 0 {
+			t.Errorf("Perlin should have been 0 at whole coordinates, got %v at %v", res, x)
+		}
+	}
+}
+
+func TestWorleyFeaturePoints(t *testing.T) {
+	w := NewWorley(6)
+	for i := int32(-2); i < 2; i++ {
+		// The 2D noise uses the feature points of the cells at z = 0
+		fx, fy, _ := w.feature(i, i*3, 0)
+		if res := w.Noise2D(float32(i)+fx, float32(i*3)+fy); res > 1e-5 {
+			t.Errorf("Worley should have been 0 at a feature point, got %v", res)
+		}
+		fx, fy, fz := w.feature(i, i*3, -i)
+		if res := w.Noise3D(float32(i)+fx, float32(i*3)+fy, float32(-i)+fz); res > 1e-5 {
+			t.Errorf("Worley should have been 0 at a feature point, got %v", res)
+		}
+	}
+}
+
+func TestFractal(t *testing.T) {
+	s := NewSimplex(7)
+	single := NewFractal(s, 1)
+	if res, e := single.Noise2D(1.3, 2.7), s.Noise2D(1.3, 2.7); res != e {
+		t.Errorf("A Fractal with one octave should have been the noise itself, wanted %v, got %v", e, res)
+	}
+
+	f := &Fractal{Noise: s, Octaves: 2, Frequency: 0.5, Lacunarity: 3, Persistence: 0.25}
+	e := (s.Noise3D(0.5, 1, 1.5) + 0.25*s.Noise3D(1.5, 3, 4.5)) / 1.25
+	if res := f.Noise3D(1, 2, 3); res != e {
+		t.Errorf("Fractal should have added up the octaves, wanted %v, got %v", e, res)
+	}
+
+	if res := NewFractal(s, 0).Noise1D(0.3); res != 0 {
+		t.Errorf("A Fractal without octaves should have been 0, got %v", res)
+	}
+}
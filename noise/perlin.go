@@ -0,0 +1,62 @@
+package noise
+
+import "github.com/EngoEngine/engo/math"
+
+// Perlin is the improved gradient noise of Ken Perlin. Its values go from about
+// -1 to 1, and are 0 at whole coordinates. It repeats every 256 units along
+// each axis.
+type Perlin struct {
+	perm *permutation
+}
+
+// NewPerlin returns the Perlin noise for the seed.
+func NewPerlin(seed uint64) *Perlin {
+	return &Perlin{perm: newPermutation(seed)}
+}
+
+// fade eases t from 0 to 1, so the noise changes smoothly between cells
+func fade(t float32) float32 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// Noise1D returns the noise at x.
+func (p *Perlin) Noise1D(x float32) float32 {
+	xi := floor(x)
+	x -= float32(xi)
+	n := lerp(fade(x), grad1(p.perm.hash1(xi), x), grad1(p.perm.hash1(xi+1), x-1))
+	// The gradients go up to 8, which gives values up to 4 halfway in between
+	// whole coordinates
+	return math.Clamp(n/4, -1, 1)
+}
+
+// Noise2D returns the noise at x, y.
+func (p *Perlin) Noise2D(x, y float32) float32 {
+	xi, yi := floor(x), floor(y)
+	x, y = x-float32(xi), y-float32(yi)
+	u, v := fade(x), fade(y)
+	h := p.perm
+	n := lerp(v,
+		lerp(u, grad2(h.hash2(xi, yi), x, y), grad2(h.hash2(xi+1, yi), x-1, y)),
+		lerp(u, grad2(h.hash2(xi, yi+1), x, y-1), grad2(h.hash2(xi+1, yi+1), x-1, y-1)),
+	)
+	return math.Clamp(n, -1, 1)
+}
+
+// Noise3D returns the noise at x, y, z.
+func (p *Perlin) Noise3D(x, y, z float32) float32 {
+	xi, yi, zi := floor(x), floor(y), floor(z)
+	x, y, z = x-float32(xi), y-float32(yi), z-float32(zi)
+	u, v, w := fade(x), fade(y), fade(z)
+	h := p.perm
+	n := lerp(w,
+		lerp(v,
+			lerp(u, grad3(h.hash3(xi, yi, zi), x, y, z), grad3(h.hash3(xi+1, yi, zi), x-1, y, z)),
+			lerp(u, grad3(h.hash3(xi, yi+1, zi), x, y-1, z), grad3(h.hash3(xi+1, yi+1, zi), x-1, y-1, z)),
+		),
+		lerp(v,
+			lerp(u, grad3(h.hash3(xi, yi, zi+1), x, y, z-1), grad3(h.hash3(xi+1, yi, zi+1), x-1, y, z-1)),
+			lerp(u, grad3(h.hash3(xi, yi+1, zi+1), x, y-1, z-1), grad3(h.hash3(xi+1, yi+1, zi+1), x-1, y-1, z-1)),
+		),
+	)
+	return math.Clamp(n, -1, 1)
+}
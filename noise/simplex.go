@@ -0,0 +1,113 @@
+package noise
+
+import "github.com/EngoEngine/engo/math"
+
+const (
+	// skew2 and unskew2 turn 2D coordinates into the ones of the grid of
+	// triangles, and back
+	skew2   = 0.36602540378443864676 // (sqrt(3) - 1) / 2
+	unskew2 = 0.21132486540518711775 // (3 - sqrt(3)) / 6
+	// skew3 and unskew3 turn 3D coordinates into the ones of the grid of
+	// tetrahedrons, and back
+	skew3   = 1.0 / 3
+	unskew3 = 1.0 / 6
+
+	// simplexScale1, simplexScale2 and simplexScale3 scale the sums of the
+	// corners to go from about -1 to 1
+	simplexScale1 = 0.395
+	simplexScale2 = 70
+	simplexScale3 = 32
+)
+
+// Simplex is the simplex noise of Ken Perlin, following the implementation of
+// Stefan Gustavson. It looks like Perlin noise, but has fewer directional
+// artifacts, and is cheaper to compute in more dimensions. Its values go from
+// about -1 to 1. It repeats every 256 units along each axis.
+type Simplex struct {
+	perm *permutation
+}
+
+// NewSimplex returns the Simplex noise for the seed.
+func NewSimplex(seed uint64) *Simplex {
+	return &Simplex{perm: newPermutation(seed)}
+}
+
+// falloff returns how much a corner of a simplex contributes at the given
+// squared distance from it
+func falloff(r, d2 float32) float32 {
+	t := r - d2
+	if t < 0 {
+		return 0
+	}
+	t *= t
+	return t * t
+}
+
+// Noise1D returns the noise at x.
+func (s *Simplex) Noise1D(x float32) float32 {
+	i := floor(x)
+	x0 := x - float32(i)
+	x1 := x0 - 1
+	n := falloff(1, x0*x0)*grad1(s.perm.hash1(i), x0) + falloff(1, x1*x1)*grad1(s.perm.hash1(i+1), x1)
+	return math.Clamp(simplexScale1*n, -1, 1)
+}
+
+// Noise2D returns the noise at x, y.
+func (s *Simplex) Noise2D(x, y float32) float32 {
+	// Find the triangle the point is in, and the offsets to its corners
+	skew := (x + y) * skew2
+	i, j := floor(x+skew), floor(y+skew)
+	unskew := float32(i+j) * unskew2
+	x0, y0 := x-(float32(i)-unskew), y-(float32(j)-unskew)
+	var i1, j1 int32
+	if x0 > y0 {
+		i1 = 1
+	} else {
+		j1 = 1
+	}
+	x1, y1 := x0-float32(i1)+unskew2, y0-float32(j1)+unskew2
+	x2, y2 := x0-1+2*unskew2, y0-1+2*unskew2
+
+	h := s.perm
+	n := falloff(0.5, x0*x0+y0*y0)*grad2(h.hash2(i, j), x0, y0) +
+		falloff(0.5, x1*x1+y1*y1)*grad2(h.hash2(i+i1, j+j1), x1, y1) +
+		falloff(0.5, x2*x2+y2*y2)*grad2(h.hash2(i+1, j+1), x2, y2)
+	return math.Clamp(simplexScale2*n, -1, 1)
+}
+
+// Noise3D returns the noise at x, y, z.
+func (s *Simplex) Noise3D(x, y, z float32) float32 {
+	// Find the tetrahedron the point is in, and the offsets to its corners
+	skew := (x + y + z) * skew3
+	i, j, k := floor(x+skew), floor(y+skew), floor(z+skew)
+	unskew := float32(i+j+k) * unskew3
+	x0, y0, z0 := x-(float32(i)-unskew), y-(float32(j)-unskew), z-(float32(k)-unskew)
+
+	// The order of the offsets tells which of the six tetrahedrons of the cube
+	// the point is in
+	var i1, j1, k1, i2, j2, k2 int32
+	switch {
+	case x0 >= y0 && y0 >= z0:
+		i1, i2, j2 = 1, 1, 1
+	case x0 >= z0 && z0 >= y0:
+		i1, i2, k2 = 1, 1, 1
+	case z0 >= x0 && x0 >= y0:
+		k1, i2, k2 = 1, 1, 1
+	case z0 >= y0 && y0 >= x0:
+		k1, j2, k2 = 1, 1, 1
+	case y0 >= z0 && z0 >= x0:
+		j1, j2, k2 = 1, 1, 1
+	default:
+		j1, i2, j2 = 1, 1, 1
+	}
+	x1, y1, z1 := x0-float32(i1)+unskew3, y0-float32(j1)+unskew3, z0-float32(k1)+unskew3
+	x2, y2, z2 := x0-float32(i2)+2*unskew3, y0-float32(j2)+2*unskew3, z0-float32(k2)+2*unskew3
+	x3, y3, z3 := x0-1+3*unskew3, y0-1+3*unskew3, z0-1+3*unskew3
+
+	h := s.perm
+	n := falloff(0.6, x0*x0+y0*y0+z0*z0)*grad3(h.hash3(i, j, k), x0, y0, z0) +
+		falloff(0.6, x1*x1+y1*y1+z1*z1)*grad3(h.hash3(i+i1, j+j1, k+k1), x1, y1, z1) +
+		falloff(0.6, x2*x2+y2*y2+z2*z2)*grad3(h.hash3(i+i2, j+j2, k+k2), x2, y2, z2) +
+		falloff(0.6, x3*x3+y3*y3+z3*z3)*grad3(h.hash3(i+1, j+1, k+1), x3, y3, z3)
+	return math.Clamp(simplexScale3*n, -1, 1)
+}
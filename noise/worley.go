@@ -0,0 +1,76 @@
+package noise
+
+import "github.com/EngoEngine/engo/math"
+
+// Worley is the cellular noise of Steven Worley. Every cell of the grid holds a
+// feature point at a random position, and the noise is the distance to the
+// closest one, in cells. Its values go from 0 at the feature points up to about
+// 1, which makes it look like cells or stones. Unlike Perlin and Simplex, it
+// doesn't repeat.
+type Worley struct {
+	seed uint64
+}
+
+// NewWorley returns the Worley noise for the seed.
+func NewWorley(seed uint64) *Worley {
+	return &Worley{seed: seed}
+}
+
+// mix scrambles the bits of h, using the finalizer of SplitMix64
+func mix(h uint64) uint64 {
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	return h ^ h>>31
+}
+
+// feature returns the position of the feature point within the cell at x, y, z,
+// from 0 to 1 along each axis
+func (w *Worley) feature(x, y, z int32) (fx, fy, fz float32) {
+	h := mix(w.seed + 0x9e3779b97f4a7c15 + uint64(uint32(x))*0xc2b2ae3d27d4eb4f +
+		uint64(uint32(y))*0x165667b19e3779f9 + uint64(uint32(z))*0x27d4eb2f165667c5)
+	fx = float32(h>>40) / (1 << 24)
+	fy = float32(h>>16&0xffffff) / (1 << 24)
+	fz = float32(mix(h)>>40) / (1 << 24)
+	return
+}
+
+// Noise1D returns the noise at x.
+func (w *Worley) Noise1D(x float32) float32 {
+	xi := floor(x)
+	closest := float32(2)
+	for i := xi - 1; i <= xi+1; i++ {
+		fx, _, _ := w.feature(i, 0, 0)
+		closest = math.Min(closest, math.Abs(float32(i)+fx-x))
+	}
+	return closest
+}
+
+// Noise2D returns the noise at x, y.
+func (w *Worley) Noise2D(x, y float32) float32 {
+	xi, yi := floor(x), floor(y)
+	closest := float32(8)
+	for i := xi - 1; i <= xi+1; i++ {
+		for j := yi - 1; j <= yi+1; j++ {
+			fx, fy, _ := w.feature(i, j, 0)
+			dx, dy := float32(i)+fx-x, float32(j)+fy-y
+			closest = math.Min(closest, dx*dx+dy*dy)
+		}
+	}
+	return math.Sqrt(closest)
+}
+
+// Noise3D returns the noise at x, y, z.
+func (w *Worley) Noise3D(x, y, z float32) float32 {
+	xi, yi, zi := floor(x), floor(y), floor(z)
+	closest := float32(12)
+	for i := xi - 1; i <= xi+1; i++ {
+		for j := yi - 1; j <= yi+1; j++ {
+			for k := zi - 1; k <= zi+1; k++ {
+				fx, fy, fz := w.feature(i, j, k)
+				dx, dy, dz := float32(i)+fx-x, float32(j)+fy-y, float32(k)+fz-z
+				closest = math.Min(closest, dx*dx+dy*dy+dz*dz)
+			}
+		}
+	}
+	return math.Sqrt(closest)
+}
@@ -0,0 +1,247 @@
+// Package random contains a seedable random number generator with helpers for
+// games. Unlike the one of math/rand, its sequence of numbers is part of its
+// API, so the same seed gives the same results on every platform and Go
+// version, which procedural content and replays rely on.
+package random
+
+import (
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/math"
+)
+
+// pcgMultiplier is the multiplier of the linear congruential generator within
+// PCG32
+const pcgMultiplier = 6364136223846793005
+
+// RNG is a deterministic random number generator, using the PCG32 algorithm by
+// Melissa O'Neill. An RNG is not safe for concurrent use; give each goroutine
+// an RNG of its own instead, e.g. using a different stream.
+//
+// The zero value is a valid RNG on stream 0, though its sequence differs from
+// the one of New(0).
+type RNG struct {
+	state uint64
+	// inc selects the stream, and is made odd when used, so the zero value is
+	// valid
+	inc uint64
+}
+
+// New returns an RNG seeded with seed, on stream 0.
+func New(seed uint64) *RNG {
+	return NewStream(seed, 0)
+}
+
+// NewStream returns an RNG seeded with seed, on the given stream. RNGs with the
+// same seed on different streams give unrelated sequences of numbers.
+func NewStream(seed, stream uint64) *RNG {
+	r := &RNG{}
+	r.Seed(seed, stream)
+	return r
+}
+
+// Seed resets the RNG to the start of the sequence of the seed on the given
+// stream.
+func (r *RNG) Seed(seed, stream uint64) {
+	r.state = 0
+	r.inc = stream << 1
+	r.Uint32()
+	r.state += seed
+	r.Uint32()
+}
+
+// State returns the current state of the RNG, which can be saved to continue
+// the same sequence later on using Restore.
+func (r *RNG) State() (state, stream uint64) {
+	return r.state, r.inc >> 1
+}
+
+// Restore returns an RNG which continues the sequence of the one that had the
+// given State.
+func Restore(state, stream uint64) *RNG {
+	return &RNG{state: state, inc: stream << 1}
+}
+
+// Clone returns a copy of the RNG, which gives the same numbers as the RNG from
+// now on.
+func (r *RNG) Clone() *RNG {
+	c := *r
+	return &c
+}
+
+// Uint32 returns a random uint32.
+func (r *RNG) Uint32() uint32 {
+	old := r.state
+	r.state = old*pcgMultiplier + (r.inc | 1)
+	xorshifted := uint32(((old >> 18) ^ old) >> 27)
+	rot := uint32(old >> 59)
+	return xorshifted>>rot | xorshifted<<((-rot)&31)
+}
+
+// Uint64 returns a random uint64.
+func (r *RNG) Uint64() uint64 {
+	return uint64(r.Uint32())<<32 | uint64(r.Uint32())
+}
+
+// Intn returns a random int from 0 up to, but not including, n. It panics if n
+// <= 0.
+func (r *RNG) Intn(n int) int {
+	if n <= 0 {
+		panic("random: invalid argument to Intn")
+	}
+	if uint64(n) <= 1<<32-1 {
+		return int(r.uint32n(uint32(n)))
+	}
+	// Larger ranges only fit in 64 bits, so reject the values which would make
+	// some results more likely
+	max := uint64(1<<63-1) - (uint64(1<<63-1)+1)%uint64(n)
+	v := r.Uint64() >> 1
+	for v > max {
+		v = r.Uint64() >> 1
+	}
+	return int(v % uint64(n))
+}
+
+// uint32n returns a random uint32 from 0 up to, but not including, n, using
+// Lemire's method without bias
+func (r *RNG) uint32n(n uint32) uint32 {
+	m := uint64(r.Uint32()) * uint64(n)
+	if low := uint32(m); low < n {
+		threshold := -n % n
+		for low < threshold {
+			m = uint64(r.Uint32()) * uint64(n)
+			low = uint32(m)
+		}
+	}
+	return uint32(m >> 32)
+}
+
+// IntRange returns a random int from min up to and including max.
+func (r *RNG) IntRange(min, max int) int {
+	if max < min {
+		min, max = max, min
+	}
+	// The span can be larger than the biggest int, and wraps around to 0 when
+	// it covers every int
+	span := uint64(max) - uint64(min) + 1
+	if span == 0 {
+		return int(r.Uint64())
+	}
+	if span <= uint64(^uint(0)>>1) {
+		return min + r.Intn(int(span))
+	}
+	// Reject the values which would make some results more likely, and let the
+	// sum wrap around past the biggest int
+	threshold := -span % span
+	v := r.Uint64()
+	for v < threshold {
+		v = r.Uint64()
+	}
+	return min + int(v%span)
+}
+
+// Float32 returns a random float32 from 0 up to, but not including, 1.
+func (r *RNG) Float32() float32 {
+	return float32(r.Uint32()>>8) / (1 << 24)
+}
+
+// Float64 returns a random float64 from 0 up to, but not including, 1.
+func (r *RNG) Float64() float64 {
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
+
+// Range returns a random float32 from min up to, but not including, max.
+func (r *RNG) Range(min, max float32) float32 {
+	return min + r.Float32()*(max-min)
+}
+
+// Bool returns true or false, with an equal chance.
+func (r *RNG) Bool() bool {
+	return r.Uint32()&1 == 1
+}
+
+// Chance returns true with the given probability, from 0 for never up to 1 for
+// always.
+func (r *RNG) Chance(probability float32) bool {
+	return r.Float32() < probability
+}
+
+// Normal returns a normally distributed float32, with the given mean and
+// standard deviation.
+func (r *RNG) Normal(mean, stddev float32) float32 {
+	// The Box-Muller transform turns two uniform numbers into a normal one
+	u := 1 - r.Float32()
+	v := r.Float32()
+	return mean + stddev*math.Sqrt(-2*math.Log(u))*math.Cos(2*math.Pi*v)
+}
+
+// Weighted returns a random index of weights, where each index is picked with a
+// chance proportional to its weight. Weights which are zero or negative are
+// never picked. It returns -1 if no weight is positive.
+func (r *RNG) Weighted(weights []float32) int {
+	var total float32
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total <= 0 {
+		return -1
+	}
+	pick := r.Float32() * total
+	last := -1
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if pick < w {
+			return i
+		}
+		pick -= w
+		last = i
+	}
+	// Rounding errors can leave a tiny bit of the total
+	return last
+}
+
+// Shuffle randomly orders n elements, using swap to swap the ones at indices i
+// and j.
+func (r *RNG) Shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, r.Intn(i+1))
+	}
+}
+
+// Perm returns a random permutation of the ints from 0 up to, but not
+// including, n.
+func (r *RNG) Perm(n int) []int {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	r.Shuffle(n, func(i, j int) { p[i], p[j] = p[j], p[i] })
+	return p
+}
+
+// InCircle returns a random point within the circle, with every point in it
+// equally likely.
+func (r *RNG) InCircle(center engo.Point, radius float32) engo.Point {
+	distance := radius * math.Sqrt(r.Float32())
+	sin, cos := math.Sincos(2 * math.Pi * r.Float32())
+	return engo.Point{X: center.X + distance*cos, Y: center.Y + distance*sin}
+}
+
+// OnCircle returns a random point on the edge of the circle.
+func (r *RNG) OnCircle(center engo.Point, radius float32) engo.Point {
+	sin, cos := math.Sincos(2 * math.Pi * r.Float32())
+	return engo.Point{X: center.X + radius*cos, Y: center.Y + radius*sin}
+}
+
+// InRect returns a random point within the axis aligned bounding box.
+func (r *RNG) InRect(box engo.AABB) engo.Point {
+	return engo.Point{X: r.Range(box.Min.X, box.Max.X), Y: r.Range(box.Min.Y, box.Max.Y)}
+}
+
+// Direction returns a random unit vector.
+func (r *RNG) Direction() engo.Point {
+	return r.OnCircle(engo.Point{}, 1)
+}
@@ -0,0 +1,222 @@
+package random
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/EngoEngine/engo"
+)
+
+func TestRNGReferenceSequence(t *testing.T) {
+	// The first numbers of the reference implementation of PCG32, seeded with
+	// 42 on stream 54
+	expected := []uint32{0xa15c02b7, 0x7b47f409, 0xba1d3330, 0x83d2f293, 0xbfa4784b, 0xcbed606e}
+	r := NewStream(42, 54)
+	for i, e := range expected {
+		if res := r.Uint32(); res != e {
+			t.Errorf("Number %d should have been %#x, got %#x", i, e, res)
+		}
+	}
+}
+
+func TestRNGDeterministic(t *testing.T) {
+	a, b, other, stream := New(7), New(7), New(8), NewStream(7, 1)
+	same, sameOther, sameStream := true, 0, 0
+	for i := 0; i < 100; i++ {
+		v := a.Uint32()
+		if v != b.Uint32() {
+			same = false
+		}
+		if v == other.Uint32() {
+			sameOther++
+		}
+		if v == stream.Uint32() {
+			sameStream++
+		}
+	}
+	if !same {
+		t.Error("RNGs with the same seed should have given the same numbers")
+	}
+	if sameOther > 1 || sameStream > 1 {
+		t.Errorf("RNGs with another seed or stream should have given other numbers, got %d and %d the same", sameOther, sameStream)
+	}
+
+	a.Seed(7, 0)
+	if res, e := a.Uint32(), New(7).Uint32(); res != e {
+		t.Errorf("Seed should have restarted the sequence, wanted %v, got %v", e, res)
+	}
+
+	var zero RNG
+	zero.Uint32()
+}
+
+func TestRNGStateAndClone(t *testing.T) {
+	r := NewStream(3, 5)
+	r.Uint64()
+	restored := Restore(r.State())
+	clone := r.Clone()
+	for i := 0; i < 10; i++ {
+		e := r.Uint32()
+		if res := restored.Uint32(); res != e {
+			t.Fatalf("Restore should have continued the sequence, wanted %v, got %v", e, res)
+		}
+		if res := clone.Uint32(); res != e {
+			t.Fatalf("Clone should have continued the sequence, wanted %v, got %v", e, res)
+		}
+	}
+	if _, stream := r.State(); stream != 5 {
+		t.Errorf("State should have returned the stream, got %v", stream)
+	}
+}
+
+func TestRNGRanges(t *testing.T) {
+	r := New(1)
+	seen := make(map[int]bool)
+	for i := 0; i < 1000; i++ {
+		if res := r.Intn(10); res < 0 || res >= 10 {
+			t.Fatalf("Intn(10) should have been in [0, 10), got %v", res)
+		}
+		res := r.IntRange(5, -2)
+		if res < -2 || res > 5 {
+			t.Fatalf("IntRange(5, -2) should have been in [-2, 5], got %v", res)
+		}
+		seen[res] = true
+		if res := r.Intn(1 << 40); res < 0 || res >= 1<<40 {
+			t.Fatalf("Intn(1 << 40) should have been in [0, 1 << 40), got %v", res)
+		}
+		if res := r.Float32(); res < 0 || res >= 1 {
+			t.Fatalf("Float32 should have been in [0, 1), got %v", res)
+		}
+		if res := r.Float64(); res < 0 || res >= 1 {
+			t.Fatalf("Float64 should have been in [0, 1), got %v", res)
+		}
+		if res := r.Range(-3, 2); res < -3 || res >= 2 {
+			t.Fatalf("Range(-3, 2) should have been in [-3, 2), got %v", res)
+		}
+	}
+	if len(seen) != 8 {
+		t.Errorf("IntRange should have returned both ends of the range, got %v", seen)
+	}
+	for i := 0; i < 100; i++ {
+		r.IntRange(math.MinInt, math.MaxInt)
+		if res := r.IntRange(math.MinInt, 0); res > 0 {
+			t.Fatalf("IntRange(math.MinInt, 0) should have been in [math.MinInt, 0], got %v", res)
+		}
+		if res := r.IntRange(-1, math.MaxInt); res < -1 {
+			t.Fatalf("IntRange(-1, math.MaxInt) should have been in [-1, math.MaxInt], got %v", res)
+		}
+	}
+	if r.Chance(0) || !r.Chance(1) {
+		t.Error("Chance should have been false for 0 and true for 1")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Intn(0) should have panicked")
+		}
+	}()
+	r.Intn(0)
+}
+
+func TestRNGNormal(t *testing.T) {
+	r := New(2)
+	const n = 10000
+	var sum, squares float32
+	for i := 0; i < n; i++ {
+		v := r.Normal(10, 2)
+		sum += v
+		squares += v * v
+	}
+	mean := sum / n
+	variance := squares/n - mean*mean
+	if mean < 9.9 || mean > 10.1 || variance < 3.8 || variance > 4.2 {
+		t.Errorf("Normal(10, 2) should have had a mean of 10 and a variance of 4, got %v and %v", mean, variance)
+	}
+}
+
+func TestRNGWeighted(t *testing.T) {
+	r := New(3)
+	counts := make([]int, 4)
+	for i := 0; i < 10000; i++ {
+		counts[r.Weighted([]float32{1, 0, 3, -1})]++
+	}
+	if counts[1] != 0 || counts[3] != 0 {
+		t.Errorf("Weighted should never have picked weights which aren't positive, got %v", counts)
+	}
+	if ratio := float32(counts[2]) / float32(counts[0]); ratio < 2.7 || ratio > 3.3 {
+		t.Errorf("Weighted should have picked the weight of 3 three times as often, got %v", counts)
+	}
+	if res := r.Weighted([]float32{0, -1}); res != -1 {
+		t.Errorf("Weighted should have returned -1 without positive weights, got %v", res)
+	}
+	if res := r.Weighted(nil); res != -1 {
+		t.Errorf("Weighted should have returned -1 without weights, got %v", res)
+	}
+}
+
+func TestRNGShuffle(t *testing.T) {
+	r := New(4)
+	p := r.Perm(50)
+	moved := 0
+	for i, v := range p {
+		if i != v {
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Error("Perm should have shuffled the ints")
+	}
+	sort.Ints(p)
+	for i, v := range p {
+		if i != v {
+			t.Fatalf("Perm should have returned each int once, got %v", p)
+		}
+	}
+
+	if res, e := New(9).Perm(20), New(9).Perm(20); !equalInts(res, e) {
+		t.Errorf("Perm should have been the same for the same seed, got %v and %v", res, e)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRNGPoints(t *testing.T) {
+	r := New(5)
+	center := engo.Point{X: 10, Y: -5}
+	box := engo.AABB{Min: engo.Point{X: -1, Y: 2}, Max: engo.Point{X: 3, Y: 4}}
+	var far int
+	for i := 0; i < 1000; i++ {
+		p := r.InCircle(center, 2)
+		d := p.PointDistance(center)
+		if d > 2.0001 {
+			t.Fatalf("InCircle should have been within the circle, got %v", p)
+		}
+		if d > 1.5 {
+			far++
+		}
+		if p := r.OnCircle(center, 2); !engo.FloatEqual(p.PointDistance(center), 2) {
+			t.Fatalf("OnCircle should have been on the circle, got %v", p)
+		}
+		if p := r.Direction(); !engo.FloatEqual(p.PointDistance(engo.Point{}), 1) {
+			t.Fatalf("Direction should have been a unit vector, got %v", p)
+		}
+		if p := r.InRect(box); p.X < box.Min.X || p.X >= box.Max.X || p.Y < box.Min.Y || p.Y >= box.Max.Y {
+			t.Fatalf("InRect should have been within the box, got %v", p)
+		}
+	}
+	// The outer ring covers 7/16 of the area of the circle
+	if far < 380 || far > 500 {
+		t.Errorf("InCircle should have spread the points evenly over the circle, got %d of 1000 in the outer ring", far)
+	}
+}
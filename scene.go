@@ -1,11 +1,25 @@
 package engo
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 )
 
-var scenes = make(map[string]*sceneWrapper)
+var (
+	scenes = make(map[string]*sceneWrapper)
+
+	// sceneStack holds the Scenes that are currently active, the last one being the top-most
+	// (current) Scene
+	sceneStack []stackedScene
+
+	// sceneVisible and drawingOverlay describe the Scene that is being updated at the moment
+	sceneVisible   = true
+	drawingOverlay bool
+
+	// currentLoad is the Scene being loaded by SetSceneWithLoadingScene, if any
+	currentLoad *sceneLoad
+)
 
 // Scene represents a screen ingame.
 // i.e.: main menu, settings, but also the game itself
@@ -45,6 +59,28 @@ type Exiter interface {
 	Exit()
 }
 
+// AsyncPreloader is an optional interface a Scene can implement, listing the assets it needs.
+// When the Scene is set using SetSceneWithLoadingScene, those assets are loaded in the
+// background while the loading Scene is shown, and Preload and Setup are only called once they
+// have finished loading.
+type AsyncPreloader interface {
+	// PreloadAsync returns the urls of the assets to load before the Scene is set up
+	PreloadAsync() []string
+}
+
+// Overlay is an optional interface a Scene can implement, indicating what should happen to the
+// Scenes below it whenever it's pushed on top of them using PushScene. A pushed Scene which
+// does not implement Overlay stops the Scenes below it from being updated and drawn until it's
+// popped again.
+type Overlay interface {
+	// UpdateBelow reports whether the Scenes below this one should keep being updated
+	UpdateBelow() bool
+
+	// RenderBelow reports whether the Scenes below this one should keep being drawn, which
+	// updates them with a delta of zero
+	RenderBelow() bool
+}
+
 // Updater is an interface for what handles your game's Update during each frame.
 // typically, this will be an *ecs.World, but you can implement your own Updater
 // and use engo without using engo's ecs
@@ -53,9 +89,20 @@ type Updater interface {
 }
 
 type sceneWrapper struct {
-	scene   Scene
-	update  Updater
-	mailbox *MessageManager
+	scene     Scene
+	update    Updater
+	mailbox   *MessageManager
+	scheduler *Scheduler
+
+	timeScale float32
+	fixed     fixedStep
+}
+
+// stackedScene is a Scene on the scene stack, along with the wrapper holding its Updater and
+// Mailbox
+type stackedScene struct {
+	scene Scene
+	*sceneWrapper
 }
 
 // CurrentScene returns the SceneWorld that is currently active
@@ -65,14 +112,96 @@ func CurrentScene() Scene {
 
 // SetScene sets the currentScene to the given Scene, and
 // optionally forcing to create a new ecs.World that goes with it.
+// Any Scenes that were pushed using PushScene are hidden and removed from the stack.
 func SetScene(s Scene, forceNewWorld bool) {
-	// Break down currentScene
-	if currentScene != nil {
-		if hider, ok := currentScene.(Hider); ok {
+	finishTransition()
+	currentLoad = nil
+
+	// Break down all Scenes on the stack
+	hideScenes(clearStack())
+
+	activateScene(s, forceNewWorld)
+}
+
+// clearStack empties the scene stack, and returns the Scenes that were on it
+func clearStack() []stackedScene {
+	sceneMutex.Lock()
+	defer sceneMutex.Unlock()
+
+	stack := sceneStack
+	sceneStack = nil
+	return stack
+}
+
+// hideScenes calls Hide on the given Scenes that are a Hider, starting at the top
+func hideScenes(stack []stackedScene) {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if hider, ok := stack[i].scene.(Hider); ok {
 			hider.Hide()
 		}
 	}
+}
+
+// PushScene puts the given Scene on top of the current Scene, optionally forcing to create a
+// new ecs.World that goes with it. Unlike SetScene, the Scenes below are not hidden, and they
+// may keep updating and being drawn if the pushed Scene implements Overlay. Only the top-most
+// Scene receives input and the messages sent by engo. A Scene can only be on the stack once.
+func PushScene(s Scene, forceNewWorld bool) error {
+	sceneMutex.RLock()
+	for _, stacked := range sceneStack {
+		if stacked.scene.Type() == s.Type() {
+			sceneMutex.RUnlock()
+			return fmt.Errorf("scene already on the stack: %s", s.Type())
+		}
+	}
+	sceneMutex.RUnlock()
+
+	activateScene(s, forceNewWorld)
+
+	return nil
+}
+
+// PushSceneByName does a lookup for the `Scene` where its `Type()` equals `name`, and then
+// pushes it on top of the current `Scene`
+func PushSceneByName(name string, forceNewWorld bool) error {
+	sceneMutex.RLock()
+	scene, ok := scenes[name]
+	sceneMutex.RUnlock()
+	if !ok {
+		return fmt.Errorf("scene not registered: %s", name)
+	}
+
+	return PushScene(scene.scene, forceNewWorld)
+}
 
+// PopScene removes the top-most Scene from the stack, and makes the Scene below it the current
+// Scene again. The removed Scene is hidden, and can be shown again by pushing or setting it.
+// The last Scene cannot be popped.
+func PopScene() error {
+	sceneMutex.Lock()
+	if len(sceneStack) < 2 {
+		sceneMutex.Unlock()
+		return errors.New("unable to pop the only scene on the stack")
+	}
+	top := sceneStack[len(sceneStack)-1]
+	sceneStack = sceneStack[:len(sceneStack)-1]
+	below := sceneStack[len(sceneStack)-1]
+	sceneMutex.Unlock()
+
+	if hider, ok := top.scene.(Hider); ok {
+		hider.Hide()
+	}
+
+	currentScene = below.scene
+	currentUpdater = below.update
+	Mailbox = below.mailbox
+	Timers = below.scheduler
+
+	return nil
+}
+
+// activateScene puts the given Scene on top of the stack, and sets it up if needed
+func activateScene(s Scene, forceNewWorld bool) {
 	// Register Scene if needed
 	sceneMutex.RLock()
 	wrapper, registered := scenes[s.Type()]
@@ -94,14 +223,20 @@ func SetScene(s Scene, forceNewWorld bool) {
 		v := reflect.New(t)
 		wrapper.update = v.Interface().(Updater)
 		wrapper.mailbox = &MessageManager{}
+		wrapper.scheduler = &Scheduler{}
 
 		doSetup = true
 	}
 
 	// Do the switch
+	sceneMutex.Lock()
+	sceneStack = append(sceneStack, stackedScene{scene: s, sceneWrapper: wrapper})
+	sceneMutex.Unlock()
+
 	currentScene = s
 	currentUpdater = wrapper.update
 	Mailbox = wrapper.mailbox
+	Timers = wrapper.scheduler
 
 	// doSetup is true whenever we're (re)initializing the Scene
 	if doSetup {
@@ -117,6 +252,179 @@ func SetScene(s Scene, forceNewWorld bool) {
 	}
 }
 
+// sceneLoad is a Scene waiting for its assets to be loaded
+type sceneLoad struct {
+	scene         Scene
+	forceNewWorld bool
+	progress      *LoadProgress
+}
+
+// SetSceneWithLoadingScene sets the loading Scene as the current Scene, and starts loading the
+// assets of the given Scene in the background if it's an AsyncPreloader. Once all assets are
+// done loading, whether they succeeded or not, the given Scene is set just like SetScene does.
+// The loading Scene can follow the progress using SceneLoadProgress. Setting another Scene
+// before the assets have finished loading cancels the switch.
+func SetSceneWithLoadingScene(s, loading Scene, forceNewWorld bool) *LoadProgress {
+	var urls []string
+	if preloader, ok := s.(AsyncPreloader); ok {
+		urls = preloader.PreloadAsync()
+	}
+	progress := Files.LoadAsync(urls...)
+
+	SetScene(loading, false)
+	currentLoad = &sceneLoad{scene: s, forceNewWorld: forceNewWorld, progress: progress}
+
+	return progress
+}
+
+// SceneLoadProgress returns the progress of the assets being loaded by
+// SetSceneWithLoadingScene, or nil if no Scene is being loaded.
+func SceneLoadProgress() *LoadProgress {
+	if currentLoad == nil {
+		return nil
+	}
+	return currentLoad.progress
+}
+
+// finishSceneLoad sets the Scene being loaded once its assets are done loading
+func finishSceneLoad() {
+	if currentLoad == nil || !currentLoad.progress.Finished() {
+		return
+	}
+
+	load := currentLoad
+	SetScene(load.scene, load.forceNewWorld)
+}
+
+// sceneLayer describes what happens to a Scene on the stack during a frame
+type sceneLayer struct {
+	update, render bool
+}
+
+// updateScenes finishes the assets loaded in the background, records or replays the input,
+// and updates the Scenes on the stack. unscaled is the delta passed to an Unscaler.
+func updateScenes(dt, unscaled float32) {
+	processAsyncLoads()
+	finishSceneLoad()
+	if Input != nil {
+		Input.updateRecording()
+		Input.updateBuffer(unscaled)
+	}
+
+	if currentTransition != nil {
+		currentTransition.update(dt, unscaled)
+	} else {
+		stack := currentStack()
+		if len(stack) == 0 {
+			if dt > 0 {
+				Timers.Update(dt)
+			}
+			fixedUpdate(currentUpdater, defaultFixedStep.advance(dt))
+			interpolationAlpha = defaultFixedStep.alpha
+			runUpdater(currentUpdater, dt, unscaled)
+			if Mailbox != nil {
+				Mailbox.Flush()
+			}
+			return
+		}
+		updateStack(stack, dt, unscaled, true)
+	}
+
+	sceneMutex.RLock()
+	if len(sceneStack) > 0 {
+		Mailbox = sceneStack[len(sceneStack)-1].mailbox
+		Timers = sceneStack[len(sceneStack)-1].scheduler
+	}
+	sceneMutex.RUnlock()
+}
+
+// currentStack returns a copy of the scene stack, so it can be iterated while Scenes are being
+// pushed or popped
+func currentStack() []stackedScene {
+	sceneMutex.RLock()
+	defer sceneMutex.RUnlock()
+
+	stack := make([]stackedScene, len(sceneStack))
+	copy(stack, sceneStack)
+	return stack
+}
+
+// updateStack runs the Updaters of the given Scenes from the bottom up, each with its own
+// time scale, Timers and Mailbox. Only the top-most Scene gets to see the InputManager, and
+// only if input is true, the others get a blocked one.
+func updateStack(stack []stackedScene, dt, unscaled float32, input bool) {
+	if len(stack) == 0 {
+		return
+	}
+
+	// Walk down from the top to figure out what each Scene is allowed to do
+	layers := make([]sceneLayer, len(stack))
+	layers[len(stack)-1] = sceneLayer{update: true, render: true}
+	for i := len(stack) - 2; i >= 0; i-- {
+		above := layers[i+1]
+		overlay, ok := stack[i+1].scene.(Overlay)
+		if !ok {
+			break
+		}
+		layers[i].update = above.update && overlay.UpdateBelow()
+		layers[i].render = above.render && overlay.RenderBelow()
+	}
+
+	manager := Input
+	drawn := false
+	for i, stacked := range stack {
+		layer := layers[i]
+		if !layer.update && !layer.render {
+			continue
+		}
+
+		sceneVisible = layer.render
+		drawingOverlay = drawn && layer.render
+		if layer.render {
+			drawn = true
+		}
+
+		if !input || i < len(stack)-1 {
+			Input = manager.blocked()
+		}
+		Mailbox = stacked.mailbox
+		Timers = stacked.scheduler
+
+		if layer.update {
+			sceneDt := dt * stacked.timeScale
+			if sceneDt > 0 {
+				stacked.scheduler.Update(sceneDt)
+			}
+			fixedUpdate(stacked.update, stacked.fixed.advance(sceneDt))
+			interpolationAlpha = stacked.fixed.alpha
+			runUpdater(stacked.update, sceneDt, unscaled)
+			stacked.mailbox.Flush()
+		} else {
+			interpolationAlpha = stacked.fixed.alpha
+			runUpdater(stacked.update, 0, 0)
+		}
+
+		Input = manager
+	}
+
+	sceneVisible = true
+	drawingOverlay = false
+}
+
+// SceneVisible reports whether the Scene that is currently being updated gets drawn this
+// frame. Scenes below an Overlay can be updated without being drawn, so systems that draw
+// should check this before doing so.
+func SceneVisible() bool {
+	return sceneVisible
+}
+
+// DrawingOverlay reports whether the Scene that is currently being updated is drawn on top of
+// another Scene this frame. Systems that draw should not clear the screen when this is true,
+// as that would erase the Scenes below.
+func DrawingOverlay() bool {
+	return drawingOverlay
+}
+
 // RegisterScene registers the `Scene`, so it can later be used by `SetSceneByName`
 func RegisterScene(s Scene) {
 	sceneMutex.RLock()
@@ -124,7 +432,7 @@ func RegisterScene(s Scene) {
 	sceneMutex.RUnlock()
 	if !ok {
 		sceneMutex.Lock()
-		scenes[s.Type()] = &sceneWrapper{scene: s}
+		scenes[s.Type()] = &sceneWrapper{scene: s, timeScale: 1}
 		sceneMutex.Unlock()
 	}
 }
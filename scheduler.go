@@ -0,0 +1,159 @@
+package engo
+
+import (
+	"sync"
+	"time"
+)
+
+// Timers schedules callbacks for the Scene that is currently active. Each Scene
+// has its own Scheduler, which runs at the time scale of the Scene.
+var Timers = &Scheduler{}
+
+// Timer is a callback that has been scheduled using a Scheduler. It can be used
+// to cancel the callback before it runs.
+type Timer struct {
+	scheduler *Scheduler
+
+	interval  float32
+	remaining float32
+	repeat    bool
+	done      bool
+
+	fn func()
+}
+
+// Cancel stops the Timer, so its callback doesn't run anymore. Cancelling a
+// Timer that has already stopped does nothing.
+func (t *Timer) Cancel() {
+	t.scheduler.mutex.Lock()
+	t.done = true
+	t.scheduler.mutex.Unlock()
+}
+
+// Active reports whether the callback of the Timer is still going to run, i.e.
+// it has not been cancelled and, if it does not repeat, has not run yet.
+func (t *Timer) Active() bool {
+	t.scheduler.mutex.Lock()
+	defer t.scheduler.mutex.Unlock()
+
+	return !t.done
+}
+
+// Remaining returns the time left until the callback of the Timer runs next.
+func (t *Timer) Remaining() time.Duration {
+	t.scheduler.mutex.Lock()
+	defer t.scheduler.mutex.Unlock()
+
+	if t.done || t.remaining <= 0 {
+		return 0
+	}
+	return time.Duration(float64(t.remaining) * float64(time.Second))
+}
+
+// Scheduler runs callbacks after a given amount of time, or repeatedly at a
+// given interval. Timers can be scheduled from any goroutine, but their
+// callbacks always run during Update, which engo calls on the main goroutine
+// for the Scheduler of each Scene. The zero value is ready to use.
+type Scheduler struct {
+	mutex  sync.Mutex
+	timers []*Timer
+}
+
+// After runs fn once, after d has passed.
+func (s *Scheduler) After(d time.Duration, fn func()) *Timer {
+	return s.schedule(d, fn, false)
+}
+
+// Every runs fn repeatedly, each time d has passed, until the returned Timer is
+// cancelled. Whenever more than d passed since the last frame, fn runs as many
+// times as needed to catch up. Any d less than or equal to zero makes fn run
+// once every frame.
+func (s *Scheduler) Every(d time.Duration, fn func()) *Timer {
+	return s.schedule(d, fn, true)
+}
+
+func (s *Scheduler) schedule(d time.Duration, fn func(), repeat bool) *Timer {
+	t := &Timer{
+		scheduler: s,
+		interval:  float32(d.Seconds()),
+		remaining: float32(d.Seconds()),
+		repeat:    repeat,
+		fn:        fn,
+	}
+
+	s.mutex.Lock()
+	s.timers = append(s.timers, t)
+	s.mutex.Unlock()
+
+	return t
+}
+
+// CancelAll cancels all Timers that have been scheduled.
+func (s *Scheduler) CancelAll() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, t := range s.timers {
+		t.done = true
+	}
+	s.timers = nil
+}
+
+// Update moves the Scheduler dt seconds forward, and runs the callbacks of the
+// Timers that are due. Timers scheduled by those callbacks only start counting
+// down during the next Update.
+func (s *Scheduler) Update(dt float32) {
+	s.mutex.Lock()
+	timers := make([]*Timer, len(s.timers))
+	copy(timers, s.timers)
+	s.mutex.Unlock()
+
+	for _, t := range timers {
+		s.mutex.Lock()
+		t.remaining -= dt
+		s.mutex.Unlock()
+
+		for s.due(t) {
+			t.fn()
+
+			// Timers without an interval run once every frame
+			if t.interval <= 0 {
+				break
+			}
+		}
+	}
+
+	// Forget about the Timers that are done
+	s.mutex.Lock()
+	active := s.timers[:0]
+	for _, t := range s.timers {
+		if !t.done {
+			active = append(active, t)
+		}
+	}
+	for i := len(active); i < len(s.timers); i++ {
+		s.timers[i] = nil
+	}
+	s.timers = active
+	s.mutex.Unlock()
+}
+
+// due reports whether the callback of the Timer has to run now, and schedules
+// the next run if it repeats.
+func (s *Scheduler) due(t *Timer) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if t.done || t.remaining > 0 {
+		return false
+	}
+
+	if !t.repeat {
+		t.done = true
+	} else if t.interval > 0 {
+		t.remaining += t.interval
+	} else {
+		t.remaining = 0
+	}
+	return true
+}
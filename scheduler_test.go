@@ -0,0 +1,179 @@
+package engo
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSchedulerAfter(t *testing.T) {
+	s := &Scheduler{}
+	runs := 0
+	timer := s.After(time.Second, func() { runs++ })
+
+	s.Update(0.5)
+	if runs != 0 || !timer.Active() || timer.Remaining() != 500*time.Millisecond {
+		t.Errorf("Timer ran too early, runs: %v, remaining: %v", runs, timer.Remaining())
+	}
+
+	s.Update(0.5)
+	if runs != 1 || timer.Active() || timer.Remaining() != 0 {
+		t.Errorf("Timer did not run once after a second, runs: %v", runs)
+	}
+
+	s.Update(5)
+	if runs != 1 {
+		t.Errorf("Timer ran more than once, runs: %v", runs)
+	}
+	if len(s.timers) != 0 {
+		t.Error("Scheduler did not forget about the timer once it was done")
+	}
+}
+
+func TestSchedulerEvery(t *testing.T) {
+	s := &Scheduler{}
+	runs := 0
+	timer := s.Every(250*time.Millisecond, func() { runs++ })
+
+	data := []struct {
+		dt      float32
+		expRuns int
+	}{
+		{0.125, 0},
+		{0.125, 1},
+		{0.25, 2},
+		{1, 6},
+		{0.125, 6},
+	}
+	for i, d := range data {
+		s.Update(d.dt)
+		if runs != d.expRuns {
+			t.Errorf("Frame %d did not run the timer the right amount of times, want: %v, got: %v", i, d.expRuns, runs)
+		}
+	}
+
+	timer.Cancel()
+	s.Update(1)
+	if runs != 6 || timer.Active() {
+		t.Errorf("Cancelled timer kept running, runs: %v", runs)
+	}
+}
+
+func TestSchedulerEveryFrame(t *testing.T) {
+	s := &Scheduler{}
+	runs := 0
+	s.Every(0, func() { runs++ })
+
+	for i := 0; i < 3; i++ {
+		s.Update(0.01)
+	}
+	if runs != 3 {
+		t.Errorf("Timer without an interval did not run once every frame, runs: %v", runs)
+	}
+}
+
+func TestSchedulerScheduleFromCallback(t *testing.T) {
+	s := &Scheduler{}
+	var order []string
+	s.After(0, func() {
+		order = append(order, "first")
+		s.After(0, func() { order = append(order, "second") })
+	})
+	cancelled := s.After(time.Second, func() { order = append(order, "cancelled") })
+	s.After(0, func() { cancelled.Cancel() })
+
+	s.Update(0.1)
+	if !reflect.DeepEqual(order, []string{"first"}) {
+		t.Errorf("Timer scheduled by a callback should only run in the next frame, got: %v", order)
+	}
+
+	s.Update(1)
+	if !reflect.DeepEqual(order, []string{"first", "second"}) {
+		t.Errorf("Timers did not run in order, got: %v", order)
+	}
+}
+
+func TestSchedulerCancelAll(t *testing.T) {
+	s := &Scheduler{}
+	runs := 0
+	timers := []*Timer{
+		s.After(time.Second, func() { runs++ }),
+		s.Every(time.Second, func() { runs++ }),
+	}
+
+	s.CancelAll()
+	s.Update(2)
+	if runs != 0 {
+		t.Errorf("Cancelled timers ran, runs: %v", runs)
+	}
+	for _, timer := range timers {
+		if timer.Active() {
+			t.Error("Timer was still active after cancelling all timers")
+		}
+	}
+}
+
+func TestSchedulerConcurrent(t *testing.T) {
+	s := &Scheduler{}
+	runs := 0
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.After(time.Second, func() { runs++ })
+		}()
+	}
+	wg.Wait()
+
+	s.Update(1)
+	if runs != 10 {
+		t.Errorf("Timers scheduled from other goroutines did not all run, runs: %v", runs)
+	}
+}
+
+func TestSceneTimers(t *testing.T) {
+	resetScenes()
+
+	game := &testStackScene{name: "testTimersGame"}
+	menu := testStackOverlay{&testStackScene{name: "testTimersMenu", updateBelow: true, renderBelow: true}}
+	Run(RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, game)
+
+	gameRuns, menuRuns := 0, 0
+	Timers.Every(100*time.Millisecond, func() { gameRuns++ })
+	if err := PushScene(menu, true); err != nil {
+		t.Fatalf("Unable to push menu: %v", err)
+	}
+	Timers.Every(100*time.Millisecond, func() { menuRuns++ })
+
+	Step(2, 100*time.Millisecond)
+	if gameRuns != 2 || menuRuns != 2 {
+		t.Errorf("Timers did not run with their scene, got %v and %v runs", gameRuns, menuRuns)
+	}
+
+	SetSceneTimeScale(game, 0.5)
+	Step(2, 100*time.Millisecond)
+	if gameRuns != 3 || menuRuns != 4 {
+		t.Errorf("Timers did not follow the time scale of their scene, got %v and %v runs", gameRuns, menuRuns)
+	}
+
+	Time.Pause()
+	Step(2, 100*time.Millisecond)
+	Time.Unpause()
+	if gameRuns != 3 || menuRuns != 4 {
+		t.Errorf("Timers ran while the game was paused, got %v and %v runs", gameRuns, menuRuns)
+	}
+
+	if err := PopScene(); err != nil {
+		t.Fatalf("Unable to pop menu: %v", err)
+	}
+	Step(2, 100*time.Millisecond)
+	if gameRuns != 4 || menuRuns != 4 {
+		t.Errorf("Timers of a popped scene kept running, got %v and %v runs", gameRuns, menuRuns)
+	}
+}
@@ -0,0 +1,55 @@
+package engo
+
+import "github.com/EngoEngine/ecs"
+
+// Unscaler is an optional interface a System within an *ecs.World can implement
+// to opt out of time scaling, e.g. for menus, HUDs and audio. When Unscaled
+// returns true, the System keeps going while Time is paused.
+type Unscaler interface {
+	Unscaled() bool
+}
+
+// runUpdater updates the Updater with dt. If the Updater is an *ecs.World, its
+// Systems which are an Unscaler are given the unscaled delta instead.
+func runUpdater(u Updater, dt, unscaled float32) {
+	w, ok := u.(*ecs.World)
+	if !ok {
+		u.Update(dt)
+		return
+	}
+
+	for _, system := range w.Systems() {
+		if unscaler, ok := system.(Unscaler); ok && unscaler.Unscaled() {
+			system.Update(unscaled)
+		} else {
+			system.Update(dt)
+		}
+	}
+}
+
+// SetSceneTimeScale sets the factor the delta of the given Scene is multiplied
+// by, on top of the time scale of Time, e.g. to pause the game below a menu.
+// Any scale less than zero results in zero.
+func SetSceneTimeScale(s Scene, scale float32) {
+	if scale < 0 {
+		scale = 0
+	}
+
+	RegisterScene(s)
+
+	sceneMutex.Lock()
+	scenes[s.Type()].timeScale = scale
+	sceneMutex.Unlock()
+}
+
+// SceneTimeScale returns the factor the delta of the given Scene is multiplied
+// by, which defaults to 1.
+func SceneTimeScale(s Scene) float32 {
+	sceneMutex.RLock()
+	defer sceneMutex.RUnlock()
+
+	if wrapper, ok := scenes[s.Type()]; ok {
+		return wrapper.timeScale
+	}
+	return 1
+}
@@ -0,0 +1,74 @@
+package engo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/EngoEngine/ecs"
+)
+
+type testUnscaledSystem struct {
+	updates []float32
+}
+
+func (*testUnscaledSystem) Remove(ecs.BasicEntity) {}
+
+func (s *testUnscaledSystem) Update(dt float32) { s.updates = append(s.updates, dt) }
+
+func (*testUnscaledSystem) Unscaled() bool { return true }
+
+type testTimeScaleScene struct {
+	*testStackScene
+	unscaled *testUnscaledSystem
+}
+
+func (s *testTimeScaleScene) Setup(u Updater) {
+	s.testStackScene.Setup(u)
+	u.(*ecs.World).AddSystem(s.unscaled)
+}
+
+func TestTimeScale(t *testing.T) {
+	resetScenes()
+
+	game := &testTimeScaleScene{&testStackScene{name: "testTimeScaleGame"}, &testUnscaledSystem{}}
+	menu := testStackOverlay{&testStackScene{name: "testTimeScaleMenu", updateBelow: true, renderBelow: true}}
+	Run(RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, game)
+	if err := PushScene(menu, true); err != nil {
+		t.Fatalf("Unable to push menu: %v", err)
+	}
+
+	Step(1, 100*time.Millisecond)
+
+	Time.SetTimeScale(0.5)
+	Step(1, 100*time.Millisecond)
+
+	SetSceneTimeScale(game, 0)
+	Step(1, 100*time.Millisecond)
+
+	Time.Pause()
+	Step(1, 100*time.Millisecond)
+	Time.Unpause()
+
+	if SceneTimeScale(game) != 0 || SceneTimeScale(menu) != 1 {
+		t.Errorf("Scene time scales were not set, got %v and %v", SceneTimeScale(game), SceneTimeScale(menu))
+	}
+	if exp := []float32{0.1, 0.05, 0, 0}; !reflect.DeepEqual(game.updates, exp) {
+		t.Errorf("Game was not updated with a scaled delta, want: %v, got: %v", exp, game.updates)
+	}
+	if exp := []float32{0.1, 0.05, 0.05, 0}; !reflect.DeepEqual(menu.updates, exp) {
+		t.Errorf("Menu was not updated with a scaled delta, want: %v, got: %v", exp, menu.updates)
+	}
+	if exp := []float32{0.1, 0.1, 0.1, 0.1}; !reflect.DeepEqual(game.unscaled.updates, exp) {
+		t.Errorf("Unscaled system was not updated with the actual delta, want: %v, got: %v", exp, game.unscaled.updates)
+	}
+
+	SetSceneTimeScale(game, -1)
+	if SceneTimeScale(game) != 0 {
+		t.Errorf("Negative scene time scale was not set to zero, got %v", SceneTimeScale(game))
+	}
+	Time.SetTimeScale(1)
+}
@@ -0,0 +1,140 @@
+package engo
+
+import "time"
+
+// Transition animates the switch from one Scene to another when using
+// SetSceneWithTransition. The common package contains fades, slides and
+// transitions using custom shaders.
+type Transition interface {
+	// Duration returns how long the transition takes
+	Duration() time.Duration
+
+	// Begin is called once the incoming Scene is set up, right before the first
+	// frame of the transition
+	Begin()
+
+	// Draw is called every frame of the transition, with progress going from 0
+	// to 1. Calling drawOut and drawIn draws the outgoing and incoming Scenes,
+	// neither of which receive any input.
+	Draw(progress float32, drawOut, drawIn func())
+
+	// End is called once, right after the last frame of the transition
+	End()
+}
+
+// TransitionStartedMessage is dispatched on the Mailbox of both the outgoing
+// and incoming Scene whenever a transition between them starts
+type TransitionStartedMessage struct {
+	From, To Scene
+}
+
+// Type returns the type of the message, "TransitionStartedMessage"
+func (TransitionStartedMessage) Type() string { return "TransitionStartedMessage" }
+
+// TransitionFinishedMessage is dispatched on the Mailbox of both the outgoing
+// and incoming Scene whenever a transition between them has finished
+type TransitionFinishedMessage struct {
+	From, To Scene
+}
+
+// Type returns the type of the message, "TransitionFinishedMessage"
+func (TransitionFinishedMessage) Type() string { return "TransitionFinishedMessage" }
+
+// sceneTransition keeps track of a Transition while it is running
+type sceneTransition struct {
+	transition Transition
+	from       []stackedScene
+	to         stackedScene
+	elapsed    float32
+}
+
+var currentTransition *sceneTransition
+
+// SetSceneWithTransition sets the currentScene to the given Scene just like
+// SetScene does, but uses the Transition to animate going from the Scenes that
+// are currently on the stack to the new one. The outgoing Scenes are hidden
+// once the transition has finished. Setting a Scene while another transition is
+// running finishes that transition right away.
+func SetSceneWithTransition(s Scene, forceNewWorld bool, t Transition) {
+	finishTransition()
+	currentLoad = nil
+
+	from := clearStack()
+	if len(from) == 0 || t == nil {
+		hideScenes(from)
+		activateScene(s, forceNewWorld)
+		return
+	}
+
+	// The Scene can't transition to itself, as both sides would share the same
+	// Updater
+	for _, stacked := range from {
+		if stacked.scene.Type() == s.Type() {
+			hideScenes(from)
+			activateScene(s, forceNewWorld)
+			return
+		}
+	}
+
+	activateScene(s, forceNewWorld)
+
+	sceneMutex.RLock()
+	to := sceneStack[len(sceneStack)-1]
+	sceneMutex.RUnlock()
+
+	currentTransition = &sceneTransition{
+		transition: t,
+		from:       from,
+		to:         to,
+	}
+
+	t.Begin()
+
+	msg := TransitionStartedMessage{From: from[len(from)-1].scene, To: s}
+	from[len(from)-1].mailbox.Dispatch(msg)
+	to.mailbox.Dispatch(msg)
+}
+
+// Transitioning reports whether a Transition between Scenes is running
+func Transitioning() bool {
+	return currentTransition != nil
+}
+
+// update advances the transition by the unscaled delta, so transitions keep
+// going while the game is paused or slowed down, and finishes it once its
+// duration has passed. The outgoing Scenes are frozen.
+func (st *sceneTransition) update(dt, unscaled float32) {
+	st.elapsed += unscaled
+
+	progress := float32(1)
+	if duration := float32(st.transition.Duration().Seconds()); duration > 0 && st.elapsed < duration {
+		progress = st.elapsed / duration
+	}
+
+	st.transition.Draw(progress, func() {
+		updateStack(st.from, 0, 0, false)
+	}, func() {
+		updateStack(currentStack(), dt, unscaled, false)
+	})
+
+	if progress >= 1 {
+		finishTransition()
+	}
+}
+
+// finishTransition ends the running transition, if any, and hides the outgoing
+// Scenes
+func finishTransition() {
+	st := currentTransition
+	if st == nil {
+		return
+	}
+	currentTransition = nil
+
+	st.transition.End()
+	hideScenes(st.from)
+
+	msg := TransitionFinishedMessage{From: st.from[len(st.from)-1].scene, To: st.to.scene}
+	st.from[len(st.from)-1].mailbox.Dispatch(msg)
+	st.to.mailbox.Dispatch(msg)
+}
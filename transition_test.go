@@ -0,0 +1,133 @@
+package engo
+
+import (
+	"testing"
+	"time"
+)
+
+type testTransition struct {
+	length   time.Duration
+	begun    int
+	ended    int
+	progress []float32
+}
+
+func (t *testTransition) Duration() time.Duration { return t.length }
+
+func (t *testTransition) Begin() { t.begun++ }
+
+func (t *testTransition) Draw(progress float32, drawOut, drawIn func()) {
+	t.progress = append(t.progress, progress)
+	drawOut()
+	drawIn()
+}
+
+func (t *testTransition) End() { t.ended++ }
+
+func TestSetSceneWithTransition(t *testing.T) {
+	resetScenes()
+	from := &testStackScene{name: "testTransitionFrom"}
+	to := &testStackScene{name: "testTransitionTo"}
+	Run(RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, from)
+
+	var started, finished []Message
+	from.mailbox.Listen("TransitionStartedMessage", func(msg Message) {
+		started = append(started, msg)
+	})
+	from.mailbox.Listen("TransitionFinishedMessage", func(msg Message) {
+		finished = append(finished, msg)
+	})
+
+	tr := &testTransition{length: time.Second}
+	SetSceneWithTransition(to, false, tr)
+
+	if CurrentScene() != to {
+		t.Errorf("CurrentScene was not set to the incoming scene, was: %v", CurrentScene().Type())
+	}
+	if !Transitioning() {
+		t.Error("Transitioning did not report a running transition")
+	}
+	if tr.begun != 1 {
+		t.Error("Transition did not begin")
+	}
+	if len(started) != 1 || started[0].(TransitionStartedMessage).To != to {
+		t.Errorf("TransitionStartedMessage was not dispatched on the outgoing mailbox, got: %v", started)
+	}
+	if from.hidden != 0 {
+		t.Error("Outgoing scene was hidden before the transition finished")
+	}
+
+	updateScenes(0.5, 0.5)
+	updateScenes(0.25, 0.25)
+
+	if len(tr.progress) != 2 || tr.progress[0] != 0.5 || tr.progress[1] != 0.75 {
+		t.Errorf("Transition did not progress properly, got: %v", tr.progress)
+	}
+	if len(from.updates) != 2 || from.updates[0] != 0 || from.updates[1] != 0 {
+		t.Errorf("Outgoing scene was not frozen during the transition, got: %v", from.updates)
+	}
+	if len(to.updates) != 2 || to.updates[0] != 0.5 || to.updates[1] != 0.25 {
+		t.Errorf("Incoming scene was not updated during the transition, got: %v", to.updates)
+	}
+
+	updateScenes(0.5, 0.5)
+
+	if Transitioning() {
+		t.Error("Transition was still running after its duration passed")
+	}
+	if tr.progress[len(tr.progress)-1] != 1 {
+		t.Errorf("Transition did not end with a progress of 1, got: %v", tr.progress)
+	}
+	if tr.ended != 1 {
+		t.Error("Transition did not end")
+	}
+	if from.hidden != 1 {
+		t.Error("Outgoing scene was not hidden after the transition finished")
+	}
+	if len(finished) != 1 || finished[0].(TransitionFinishedMessage).From != from {
+		t.Errorf("TransitionFinishedMessage was not dispatched on the outgoing mailbox, got: %v", finished)
+	}
+	if Mailbox != to.mailbox {
+		t.Error("Mailbox was not set to the incoming scene's mailbox")
+	}
+
+	updateScenes(0.5, 0.5)
+	if len(from.updates) != 3 || len(to.updates) != 4 {
+		t.Errorf("Only the incoming scene should be updated after the transition, got: %v and %v", from.updates, to.updates)
+	}
+}
+
+func TestSetSceneDuringTransition(t *testing.T) {
+	resetScenes()
+	from := &testStackScene{name: "testTransitionInterruptFrom"}
+	to := &testStackScene{name: "testTransitionInterruptTo"}
+	Run(RunOptions{
+		NoRun:        true,
+		HeadlessMode: true,
+	}, from)
+
+	tr := &testTransition{length: time.Second}
+	SetSceneWithTransition(to, false, tr)
+	SetScene(from, false)
+
+	if Transitioning() {
+		t.Error("Transition was still running after setting a scene")
+	}
+	if tr.ended != 1 {
+		t.Error("Transition did not end when setting a scene")
+	}
+	if from.hidden != 1 || to.hidden != 1 {
+		t.Error("Scenes were not hidden when setting a scene during a transition")
+	}
+	if CurrentScene() != from {
+		t.Errorf("CurrentScene was not set, was: %v", CurrentScene().Type())
+	}
+
+	SetSceneWithTransition(from, false, tr)
+	if Transitioning() {
+		t.Error("Scene should not transition to itself")
+	}
+}